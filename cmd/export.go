@@ -0,0 +1,109 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	goflag "flag"
+	"os"
+
+	"github.com/golang/glog"
+	blendedset "github.com/inwinstack/blended/generated/clientset/versioned"
+	"github.com/inwinstack/pa-controller/pkg/exporter"
+	inwinset "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	firewallctrl "github.com/inwinstack/pa-controller/pkg/operator/firewall"
+	"github.com/inwinstack/pango/version"
+	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runExport renders the NATs, Securities and Services in the cluster into the set commands
+// or the XML config of PAN, without connecting to PAN.
+func runExport(args []string) {
+	var (
+		firewallName string
+		format       string
+		panosVersion string
+		all          bool
+	)
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.StringVarP(&kubeconfig, "kubeconfig", "", "", "Absolute path to the kubeconfig file.")
+	locationFlags(flags)
+	flags.StringVarP(&cfg.NamingScheme, "naming-scheme", "", "name", "The naming scheme of the NAT and Security entries as the controller, either name or namespace.")
+	flags.StringVarP(&cfg.ClusterID, "cluster-id", "", "", "The ID of the cluster, which adds the ownership tags to the entries as the controller.")
+	flags.StringVarP(&firewallName, "firewall", "", "", "The Firewall of the exported resources, the resources of the default firewall are exported when it's empty. The location of the Firewall overrides the location flags.")
+	flags.BoolVarP(&all, "all", "", false, "Export the resources which aren't active as well, such as the pending and failed ones.")
+	flags.IntVarP(&cfg.MoveType, "move-type", "", 5, "The default position of the Security policies as the controller, which should be one of the Move constants(0:Skip, 1:Before, 2:DirectlyBefore, 3:After, 4:DirectlyAfter, 5:Top and 6:Bottom).")
+	flags.StringVarP(&cfg.MoveRule, "move-rule", "", "", "The rule of the default position of the Security policies as the controller.")
+	flags.StringVarP(&format, "format", "", "set", "The format of the output, either set for the set commands of the CLI or xml for the XML config.")
+	flags.StringVarP(&panosVersion, "panos-version", "", "8.1.0", "The PAN-OS version of the firewall or Panorama, which decides the XML of the entries.")
+	flags.AddGoFlagSet(goflag.CommandLine)
+	flags.Parse(args)
+
+	if format != "set" && format != "xml" {
+		glog.Fatalf("The format must be either set or xml.")
+	}
+
+	v, err := version.New(panosVersion)
+	if err != nil {
+		glog.Fatalf("Error to parse the PAN-OS version: %s", err.Error())
+	}
+
+	k8scfg, err := restConfig(kubeconfig)
+	if err != nil {
+		glog.Fatalf("Error to build kubeconfig: %s", err.Error())
+	}
+
+	blendedclient, err := blendedset.NewForConfig(k8scfg)
+	if err != nil {
+		glog.Fatalf("Error to build Blended client: %s", err.Error())
+	}
+
+	if firewallName != "" {
+		inwinclient, err := inwinset.NewForConfig(k8scfg)
+		if err != nil {
+			glog.Fatalf("Error to build inwinSTACK client: %s", err.Error())
+		}
+
+		f, err := inwinclient.InwinstackV1().Firewalls().Get(firewallName, metav1.GetOptions{})
+		if err != nil {
+			glog.Fatalf("Error to get the firewall: %s", err.Error())
+		}
+		cfg = firewallctrl.Config(cfg, f)
+	}
+
+	objs, err := exporter.List(cfg, blendedclient, all)
+	if err != nil {
+		glog.Fatalf("Error to list the resources: %s", err.Error())
+	}
+
+	exp := exporter.New(cfg, v)
+	for _, obj := range objs {
+		if err := exp.Add(obj); err != nil {
+			glog.Fatalf("Error to render the resources: %s", err.Error())
+		}
+	}
+
+	out, err := exp.SetCommands()
+	if format == "xml" {
+		out, err = exp.XML()
+	}
+	if err != nil {
+		glog.Fatalf("Error to render the resources: %s", err.Error())
+	}
+	os.Stdout.Write(out)
+}
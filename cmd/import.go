@@ -0,0 +1,121 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	goflag "flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	blendedset "github.com/inwinstack/blended/generated/clientset/versioned"
+	"github.com/inwinstack/pa-controller/pkg/importer"
+	"github.com/inwinstack/pa-controller/pkg/panorama"
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/kubernetes"
+)
+
+// runImport converts the NAT policies, security policies and service objects on the default
+// firewall or Panorama into resources, and either prints them as YAML or creates them.
+func runImport(args []string) {
+	var (
+		namespace    string
+		firewallName string
+		apply        bool
+	)
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.StringVarP(&kubeconfig, "kubeconfig", "", "", "Absolute path to the kubeconfig file.")
+	firewallFlags(flags)
+	flags.StringVarP(&namespace, "namespace", "n", "default", "The namespace of the imported NATs and Securities.")
+	flags.StringVarP(&firewallName, "firewall", "", "", "The Firewall set in the inwinstack.com/pa-firewall label of the imported resources.")
+	flags.BoolVarP(&apply, "apply", "", false, "Flag apply creates the imported resources in the cluster instead of printing them as YAML.")
+	flags.AddGoFlagSet(goflag.CommandLine)
+	flags.Parse(args)
+
+	var kubeclient kubernetes.Interface
+	var blendedclient blendedset.Interface
+	if apply || credentialsSecret != "" {
+		k8scfg, err := restConfig(kubeconfig)
+		if err != nil {
+			glog.Fatalf("Error to build kubeconfig: %s", err.Error())
+		}
+
+		kubeclient, err = kubernetes.NewForConfig(k8scfg)
+		if err != nil {
+			glog.Fatalf("Error to build Kubernetes client: %s", err.Error())
+		}
+
+		blendedclient, err = blendedset.NewForConfig(k8scfg)
+		if err != nil {
+			glog.Fatalf("Error to build Blended client: %s", err.Error())
+		}
+	}
+
+	opts := importer.Options{Vsys: cfg.Vsys, Namespace: namespace, Firewall: firewallName}
+	var imp *importer.Importer
+	switch fw, pano, _, _ := defaultFirewall(kubeclient); {
+	case fw != nil:
+		imp = importer.New(fw.Policies.Nat, fw.Policies.Security, fw.Objects.Services, opts)
+	case pano != nil:
+		location := cfg.DeviceGroup
+		if cfg.SharedObjects {
+			location = panorama.Shared
+		}
+		imp = importer.New(
+			panorama.NewNat(pano.Policies.Nat, cfg.DeviceGroup, cfg.Rulebase),
+			panorama.NewSecurity(pano.Policies.Security, cfg.DeviceGroup, cfg.Rulebase),
+			panorama.NewServices(pano.Objects.Services, location),
+			opts,
+		)
+	default:
+		glog.Fatalf("Either the host or the credentials secret must be set.")
+	}
+
+	result, err := imp.Import()
+	if err != nil {
+		glog.Fatalf("Error to import the entries: %s", err.Error())
+	}
+
+	for _, s := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s.\n", s)
+	}
+
+	if !apply {
+		out, err := importer.YAML(result.Objects)
+		if err != nil {
+			glog.Fatalf("Error to print the resources: %s", err.Error())
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	for _, obj := range result.Objects {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		accessor, _ := meta.Accessor(obj)
+		if err := importer.Create(blendedclient, obj); err != nil {
+			if errors.IsAlreadyExists(err) {
+				fmt.Fprintf(os.Stderr, "Skipped %s '%s': the resource already exists.\n", kind, accessor.GetName())
+				continue
+			}
+			glog.Fatalf("Error to create %s '%s': %s", kind, accessor.GetName(), err.Error())
+		}
+		fmt.Fprintf(os.Stdout, "Created %s '%s'.\n", kind, accessor.GetName())
+	}
+}
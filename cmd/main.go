@@ -28,6 +28,8 @@ import (
 	"github.com/golang/glog"
 	blendedset "github.com/inwinstack/blended/generated/clientset/versioned"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	inwinset "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
 	"github.com/inwinstack/pa-controller/pkg/ha"
 	palog "github.com/inwinstack/pa-controller/pkg/log"
 	"github.com/inwinstack/pa-controller/pkg/operator"
@@ -35,36 +37,89 @@ import (
 	"github.com/inwinstack/pango"
 	"github.com/inwinstack/pango/util"
 	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 var (
-	cfg             = &config.Config{}
-	kubeconfig      string
-	haMode          bool
-	inspectorSecond int
-	ver             bool
+	cfg               = &config.Config{}
+	kubeconfig        string
+	haMode            bool
+	inspectorSecond   int
+	credentialsSecret string
+	ver               bool
 )
 
+// firewallFlags adds the flags to connect to the default firewall or Panorama, which are
+// shared by the subcommands.
+func firewallFlags(flags *flag.FlagSet) {
+	flags.StringVarP(&cfg.Host, "host", "", "", "The address of host for the Palo Alto firewall.")
+	flags.StringVarP(&cfg.Username, "username", "", "", "The API username of Palo Alto firewall.")
+	flags.StringVarP(&cfg.Password, "password", "", "", "The API password of Palo Alto firewall .")
+	flags.StringVarP(&cfg.APIKey, "api-key", "", "", "the API key of Palo Alto firewall .")
+	flags.StringVarP(&credentialsSecret, "credentials-secret", "", "", "The secret in the form of namespace/name to read the host, username, password and api-key of Palo Alto firewall. The credentials are reloaded when the secret changes.")
+	locationFlags(flags)
+}
+
+// locationFlags adds the flags of the location of the resources on the firewall or Panorama.
+func locationFlags(flags *flag.FlagSet) {
+	flags.StringVarP(&cfg.Vsys, "vsys", "", "", "A virtual system (vsys) is an independent (virtual) firewall instance that you can separately manage within a physical firewall.")
+	flags.BoolVarP(&cfg.Panorama, "panorama", "", false, "Flag panorama should be true if the host is a Panorama, and then the resources are synced to a device group and pushed to its devices.")
+	flags.StringVarP(&cfg.DeviceGroup, "device-group", "", "", "The device group of Panorama to sync the resources.")
+	flags.StringVarP(&cfg.Rulebase, "rulebase", "", "pre-rulebase", "The rulebase of the device group to sync the policies, either pre-rulebase or post-rulebase.")
+	flags.BoolVarP(&cfg.SharedObjects, "shared-objects", "", false, "Flag shared-objects should be true if you want to sync the objects to shared instead of the device group.")
+}
+
+// webhookFlags adds the flags of the webhook server, which are shared by the controller and
+// the webhook subcommand.
+func webhookFlags(flags *flag.FlagSet) {
+	flags.StringVarP(&cfg.WebhookListenAddress, "webhook-listen-address", "", "", "The address to serve the admission webhooks over TLS, e.g. :8443. The webhooks are disabled when it's empty.")
+	flags.StringVarP(&cfg.WebhookCertFile, "webhook-cert-file", "", "", "The certificate file of the webhook server.")
+	flags.StringVarP(&cfg.WebhookKeyFile, "webhook-key-file", "", "", "The private key file of the webhook server.")
+	flags.BoolVarP(&cfg.WebhookSelfSigned, "webhook-self-signed", "", false, "Flag webhook-self-signed generates a self-signed certificate for the webhook server instead of the certificate files.")
+	flags.StringSliceVarP(&cfg.WebhookHosts, "webhook-hosts", "", []string{"localhost", "127.0.0.1"}, "The DNS names and IPs of the self-signed certificate, e.g. pa-controller.kube-system.svc.")
+	flags.StringVarP(&cfg.WebhookConfiguration, "webhook-configuration", "", "", "The ValidatingWebhookConfiguration and MutatingWebhookConfiguration whose CA bundles are set to the self-signed certificate.")
+	flags.StringSliceVarP(&cfg.WebhookTrustedUsers, "webhook-trusted-users", "", []string{"system:serviceaccount:kube-system:pa-controller"}, "The users allowed to set the inwinstack.com/pa-entry-name annotation, such as the controller and the users applying the imported resources.")
+}
+
 func parserFlags() {
 	flag.StringVarP(&kubeconfig, "kubeconfig", "", "", "Absolute path to the kubeconfig file.")
+	firewallFlags(flag.CommandLine)
 	flag.IntVarP(&cfg.Threads, "threads", "", 2, "Number of worker threads used by the controller.")
 	flag.IntVarP(&cfg.SyncSec, "sync-seconds", "", 60, "Seconds for syncing and retrying objects.")
-	flag.StringVarP(&cfg.Host, "host", "", "", "The address of host for the Palo Alto firewall.")
-	flag.StringVarP(&cfg.Username, "username", "", "", "The API username of Palo Alto firewall.")
-	flag.StringVarP(&cfg.Password, "password", "", "", "The API password of Palo Alto firewall .")
-	flag.StringVarP(&cfg.APIKey, "api-key", "", "", "the API key of Palo Alto firewall .")
 	flag.IntVarP(&cfg.MoveType, "move-type", "", 5, "The param should be one of the Move constants(0:Skip, 1:Before, 2:DirectlyBefore, 3:After, 4:DirectlyAfter, 5:Top and 6:Bottom).")
 	flag.StringVarP(&cfg.MoveRule, "move-rule", "", "", "A logical group of security policies somewhere in relation to another security policy.")
-	flag.StringVarP(&cfg.Vsys, "vsys", "", "", "A virtual system (vsys) is an independent (virtual) firewall instance that you can separately manage within a physical firewall.")
 	flag.IntVarP(&cfg.Retry, "commit-retry", "", 5, "The number of retry for PA commit job.")
 	flag.IntVarP(&cfg.CommitWaitTime, "commit-wait-time", "", 2, "Seconds for waiting next PA commit.")
+	flag.IntVarP(&cfg.CommitMaxDelay, "commit-max-delay", "", 30, "Max seconds for a change to wait for PA commit, 0 is unlimited.")
+	flag.IntVarP(&cfg.CommitMaxBatch, "commit-max-batch", "", 100, "Max number of changes in a PA commit, 0 is unlimited.")
 	flag.StringSliceVarP(&cfg.Admins, "commit-admins", "", []string{"api"}, "Flag commit-admins is an advanced option for doing the partial commit changes by administrators.")
 	flag.BoolVarP(&cfg.Force, "force-commit", "", false, "Flag force-commit is if you want to force a commit even if no changes are required.")
-	flag.BoolVarP(&cfg.Sync, "sync-commit", "", false, "Flag sync-commit should be true if you want this function to block until the commit job completes.")
+	flag.BoolVarP(&cfg.Lock, "commit-lock", "", false, "Flag commit-lock takes a config and commit lock for the edits and commits of the controller, and releases it when the changes are committed.")
+	flag.BoolVarP(&cfg.Sync, "sync-commit", "", false, "Deprecated, the commit jobs are always polled to completion to record their results on the resources.")
 	flag.BoolVarP(&cfg.DaNPartial, "dan-partial", "", false, "Flag dan-partial is an advanced option for doing the partial commit for the device and network configuration.")
 	flag.BoolVarP(&cfg.PaOPartial, "pao-partial", "", true, "Flag pao-partial is an advanced option for doing the partial commit for the policy and object configuration.")
+	flag.BoolVarP(&cfg.DryRun, "dry-run", "", false, "Flag dry-run plans the changes of the resources to PAN in the inwinstack.com/pa-plan annotation without editing or committing.")
+	flag.BoolVarP(&cfg.Validate, "validate", "", false, "Flag validate runs a PAN validate job on the candidate config in the dry-run mode.")
+	flag.StringVarP(&cfg.DriftPolicy, "drift-policy", "", "reapply", "The default policy for the NAT, Security and Service entries changed on PAN out of the controller, either reapply or report.")
+	flag.StringVarP(&cfg.NamingScheme, "naming-scheme", "", "name", "The naming scheme of the NAT and Security entries, either name for the resource name or namespace for <namespace>-<name>. The existing entries keep their names.")
+	flag.StringSliceVarP(&cfg.PredefinedServices, "predefined-services", "", []string{"service-http", "service-https"}, "The services that exist on PAN without a Service resource, e.g. predefined or shared services, which service groups don't wait for.")
+	flag.StringVarP(&cfg.ClusterID, "cluster-id", "", "", "The ID of the cluster, which tags the entries created by the controller with pa-controller and pa-cluster-<id>. The ownership tags and the sweeper are disabled when it isn't set.")
+	flag.IntVarP(&cfg.SweepSec, "sweep-seconds", "", 600, "Seconds for sweeping the entries owned by the cluster without any resource, 0 disables the sweeper.")
+	flag.IntVarP(&cfg.SweepThreshold, "sweep-threshold", "", 10, "The max number of orphaned entries deleted by a sweep, nothing is deleted when there are more, 0 is unlimited.")
+	flag.BoolVarP(&cfg.SweepDryRun, "sweep-dry-run", "", false, "Flag sweep-dry-run reports the orphaned entries without deleting them.")
+	flag.BoolVarP(&cfg.SyncLoadBalancer, "sync-loadbalancer", "", false, "Flag sync-loadbalancer is an advanced option for syncing Kubernetes LoadBalancer services into PAN NAT and security policies.")
+	flag.StringVarP(&cfg.LBSourceZone, "lb-source-zone", "", "untrust", "The source zone of PAN policies synced from Kubernetes LoadBalancer services.")
+	flag.StringVarP(&cfg.LBDestinationZone, "lb-destination-zone", "", "trust", "The destination zone of PAN security policies synced from Kubernetes LoadBalancer services.")
+	flag.BoolVarP(&cfg.SyncNetworkPolicy, "sync-networkpolicy", "", false, "Flag sync-networkpolicy is an advanced option for translating Kubernetes NetworkPolicies into PAN security policies.")
+	flag.BoolVarP(&cfg.SyncPodTags, "sync-pod-tags", "", false, "Flag sync-pod-tags is an advanced option for registering Kubernetes pod IPs to PAN User-ID tags.")
+	flag.IntVarP(&cfg.PodTagResyncSec, "pod-tag-resync-seconds", "", 300, "Seconds between full resyncs of the pod IP tags registered on PAN firewall.")
+	flag.StringVarP(&cfg.EDLListenAddress, "edl-listen-address", "", "", "The address to serve external dynamic lists, e.g. :8080. The server is disabled when it's empty.")
+	flag.StringVarP(&cfg.EDLURL, "edl-url", "", "", "The base URL of the external dynamic list server that PAN firewall polls, e.g. http://10.0.0.10:8080.")
+	flag.StringVarP(&cfg.FirewallSecretNamespace, "firewall-secret-namespace", "", "kube-system", "The namespace of the credentials secrets of Firewall resources, where the controller is granted to read secrets. The firewalls with a secret in another namespace fail.")
+	webhookFlags(flag.CommandLine)
 	flag.BoolVarP(&haMode, "ha", "", false, "Flag ha is an advanced option for enabling high availability.")
 	flag.IntVarP(&inspectorSecond, "inspector-seconds", "", 30, "Seconds for checking the PAN status of high availability.")
 	flag.BoolVarP(&ver, "version", "", false, "Display the version.")
@@ -88,32 +143,93 @@ func restConfig(kubeconfig string) (*rest.Config, error) {
 	return cfg, nil
 }
 
-func main() {
-	defer glog.Flush()
-	log.SetOutput(new(palog.LogWriter))
-	parserFlags()
-
-	if ver {
-		fmt.Fprintf(os.Stdout, "%s\n", version.GetVersion())
-		os.Exit(0)
+// defaultFirewall initializes the default firewall or Panorama from the flags or the credentials
+// secret. Both of them are nil when neither of the flags is set, and then the firewalls are only
+// managed by Firewall resources.
+func defaultFirewall(kubeclient kubernetes.Interface) (*pango.Firewall, *pango.Panorama, *credentials.Client, *credentials.Credentials) {
+	if cfg.Host == "" && credentialsSecret == "" {
+		glog.Infof("The default firewall is disabled.")
+		return nil, nil, nil, nil
 	}
 
-	fw := &pango.Firewall{Client: pango.Client{
+	client := pango.Client{
 		Hostname: cfg.Host,
 		Username: cfg.Username,
 		Logging:  pango.LogAction | pango.LogOp,
-	}}
+	}
 	if len(cfg.Password) != 0 {
-		fw.Client.Password = cfg.Password
+		client.Password = cfg.Password
 	}
 
 	if len(cfg.APIKey) != 0 {
-		fw.Client.ApiKey = cfg.APIKey
+		client.ApiKey = cfg.APIKey
 	}
 
+	var creds *credentials.Credentials
+	if credentialsSecret != "" {
+		secretNamespace, secretName, err := credentials.ParseSecretName(credentialsSecret)
+		if err != nil {
+			glog.Fatalf("Error to parse the credentials secret: %s", err.Error())
+		}
+
+		secret, err := kubeclient.CoreV1().Secrets(secretNamespace).Get(secretName, metav1.GetOptions{})
+		if err != nil {
+			glog.Fatalf("Error to get the credentials secret: %s", err.Error())
+		}
+
+		creds, err = credentials.FromSecret(secret)
+		if err != nil {
+			glog.Fatalf("Error to read the credentials secret: %s", err.Error())
+		}
+		creds.Apply(&client)
+	}
+
+	if cfg.Panorama {
+		if cfg.DeviceGroup == "" {
+			glog.Fatalf("The device group must be set for Panorama.")
+		}
+
+		pano := &pango.Panorama{Client: client}
+		if err := pano.Initialize(); err != nil {
+			glog.Fatalf("Error to initialize Panorama: %s", err.Error())
+		}
+		pclient := credentials.NewClient(&pano.Client)
+		pclient.BindPanorama(pano)
+		return nil, pano, pclient, creds
+	}
+
+	fw := &pango.Firewall{Client: client}
 	if err := fw.Initialize(); err != nil {
 		glog.Fatalf("Error to initialize PAN firewall: %s", err.Error())
 	}
+	fwclient := credentials.NewClient(&fw.Client)
+	fwclient.BindFirewall(fw)
+	return fw, nil, fwclient, creds
+}
+
+func main() {
+	defer glog.Flush()
+	log.SetOutput(new(palog.LogWriter))
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "webhook":
+			runWebhook(os.Args[2:])
+			return
+		}
+	}
+
+	parserFlags()
+
+	if ver {
+		fmt.Fprintf(os.Stdout, "%s\n", version.GetVersion())
+		os.Exit(0)
+	}
 
 	k8scfg, err := restConfig(kubeconfig)
 	if err != nil {
@@ -125,12 +241,31 @@ func main() {
 		glog.Fatalf("Error to build Blended client: %s", err.Error())
 	}
 
-	op := operator.New(cfg, fw, blendedclient)
+	inwinclient, err := inwinset.NewForConfig(k8scfg)
+	if err != nil {
+		glog.Fatalf("Error to build inwinSTACK client: %s", err.Error())
+	}
+
+	kubeclient, err := kubernetes.NewForConfig(k8scfg)
+	if err != nil {
+		glog.Fatalf("Error to build Kubernetes client: %s", err.Error())
+	}
+
+	fw, pano, client, creds := defaultFirewall(kubeclient)
+	op := operator.New(cfg, fw, pano, client, blendedclient, inwinclient, kubeclient)
 	ctx, cancel := context.WithCancel(context.Background())
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 
-	if haMode {
+	if creds != nil {
+		secretNamespace, secretName, _ := credentials.ParseSecretName(credentialsSecret)
+		watcher := credentials.NewWatcher(kubeclient, secretNamespace, secretName, "", client, creds)
+		if err := watcher.Run(ctx); err != nil {
+			glog.Fatalf("Error to watch the credentials secret: %s", err.Error())
+		}
+	}
+
+	if haMode && client != nil {
 		active := false
 		callbacks := &ha.Callbacks{
 			OnActive: func(status *util.HighAvailability) {
@@ -167,7 +302,7 @@ func main() {
 				glog.Fatalf("Error to get HA status: %s.", err)
 			},
 		}
-		inspector := ha.NewInspector(fw, inspectorSecond, callbacks)
+		inspector := ha.NewInspector(client, inspectorSecond, callbacks)
 		if err := inspector.Run(ctx); err != nil {
 			glog.Fatalf("Error to run the operator: %s.", err)
 		}
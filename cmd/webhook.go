@@ -0,0 +1,84 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	goflag "flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	inwinset "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/webhook"
+	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+)
+
+// runWebhook serves only the admission webhooks without connecting to PAN, so the webhooks
+// can run in their own deployment, which stays available while the controller restarts or
+// waits on the passive firewall.
+func runWebhook(args []string) {
+	flags := flag.NewFlagSet("webhook", flag.ExitOnError)
+	flags.StringVarP(&kubeconfig, "kubeconfig", "", "", "Absolute path to the kubeconfig file.")
+	flags.StringVarP(&cfg.NamingScheme, "naming-scheme", "", "name", "The naming scheme of the NAT and Security entries as the controller, either name or namespace.")
+	webhookFlags(flags)
+	flags.AddGoFlagSet(goflag.CommandLine)
+	flags.Parse(args)
+
+	if cfg.WebhookListenAddress == "" {
+		glog.Fatalf("The webhook listen address must be set.")
+	}
+
+	k8scfg, err := restConfig(kubeconfig)
+	if err != nil {
+		glog.Fatalf("Error to build kubeconfig: %s", err.Error())
+	}
+
+	inwinclient, err := inwinset.NewForConfig(k8scfg)
+	if err != nil {
+		glog.Fatalf("Error to build inwinSTACK client: %s", err.Error())
+	}
+
+	kubeclient, err := kubernetes.NewForConfig(k8scfg)
+	if err != nil {
+		glog.Fatalf("Error to build Kubernetes client: %s", err.Error())
+	}
+
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinclient, 30*time.Second)
+	kubeInformer := informers.NewSharedInformerFactory(kubeclient, 30*time.Second)
+	server := webhook.NewServer(cfg, kubeclient, kubeInformer, inwinInformer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	if err := server.Run(ctx); err != nil {
+		glog.Fatalf("Error to serve the webhooks: %s.", err)
+	}
+
+	<-signalChan
+	cancel()
+	server.Stop()
+	glog.Infof("Shutdown signal received, exiting...")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddressList is a list of address.
+type AddressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Address `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Address represents a Kubernetes Address Custom Resource.
+// The Address will be used as PA address object.
+type Address struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   AddressSpec   `json:"spec"`
+	Status AddressStatus `json:"status,omitempty"`
+}
+
+// These are the valid type of an address.
+const (
+	AddressIPNetmask = "ip-netmask"
+	AddressIPRange   = "ip-range"
+	AddressFQDN      = "fqdn"
+)
+
+// AddressSpec is the spec for an address resource.
+type AddressSpec struct {
+	Type        string   `json:"type,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// FirewallRef is the name of the Firewall that the resource is synced to.
+	FirewallRef string `json:"firewallRef,omitempty"`
+}
+
+type AddressPhase string
+
+// These are the valid phases of an address.
+const (
+	AddressNone        AddressPhase = ""
+	AddressPending     AddressPhase = "Pending"
+	AddressActive      AddressPhase = "Active"
+	AddressFailed      AddressPhase = "Failed"
+	AddressTerminating AddressPhase = "Terminating"
+)
+
+// AddressStatus represents the current state of an address resource.
+type AddressStatus struct {
+	Phase          AddressPhase `json:"phase"`
+	Reason         string       `json:"reason,omitempty"`
+	LastUpdateTime metav1.Time  `json:"lastUpdateTime"`
+}
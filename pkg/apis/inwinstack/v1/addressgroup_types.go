@@ -0,0 +1,74 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddressGroupList is a list of address group.
+type AddressGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AddressGroup `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddressGroup represents a Kubernetes AddressGroup Custom Resource.
+// The AddressGroup will be used as PA address group.
+type AddressGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   AddressGroupSpec   `json:"spec"`
+	Status AddressGroupStatus `json:"status,omitempty"`
+}
+
+// AddressGroupSpec is the spec for an address group resource.
+// A group is either static, which lists the member addresses by name,
+// or dynamic, which matches the addresses by a tag expression such as
+// "'web' and 'prod'".
+type AddressGroupSpec struct {
+	Description     string   `json:"description,omitempty"`
+	StaticAddresses []string `json:"staticAddresses,omitempty"`
+	DynamicMatch    string   `json:"dynamicMatch,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// FirewallRef is the name of the Firewall that the resource is synced to.
+	FirewallRef string `json:"firewallRef,omitempty"`
+}
+
+type AddressGroupPhase string
+
+// These are the valid phases of an address group.
+const (
+	AddressGroupNone        AddressGroupPhase = ""
+	AddressGroupPending     AddressGroupPhase = "Pending"
+	AddressGroupActive      AddressGroupPhase = "Active"
+	AddressGroupFailed      AddressGroupPhase = "Failed"
+	AddressGroupTerminating AddressGroupPhase = "Terminating"
+)
+
+// AddressGroupStatus represents the current state of an address group resource.
+type AddressGroupStatus struct {
+	Phase          AddressGroupPhase `json:"phase"`
+	Reason         string            `json:"reason,omitempty"`
+	LastUpdateTime metav1.Time       `json:"lastUpdateTime"`
+}
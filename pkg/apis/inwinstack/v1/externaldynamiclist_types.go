@@ -0,0 +1,92 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalDynamicListList is a list of external dynamic list.
+type ExternalDynamicListList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ExternalDynamicList `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalDynamicList represents a Kubernetes External Dynamic List Custom Resource.
+// The IPs selected by the list are served by the controller, and the list will be used
+// as PA external dynamic list object.
+type ExternalDynamicList struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   ExternalDynamicListSpec   `json:"spec"`
+	Status ExternalDynamicListStatus `json:"status,omitempty"`
+}
+
+type ExternalDynamicListSource string
+
+// These are the valid sources of an external dynamic list.
+const (
+	ExternalDynamicListPod     ExternalDynamicListSource = "pod"
+	ExternalDynamicListNode    ExternalDynamicListSource = "node"
+	ExternalDynamicListService ExternalDynamicListSource = "service"
+)
+
+// ExternalDynamicListSpec is the spec for an external dynamic list resource.
+type ExternalDynamicListSpec struct {
+	Source ExternalDynamicListSource `json:"source"`
+	// Selector selects the pods, nodes or services by labels. An empty selector selects all of them.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Namespaces and NamespaceSelector filter the namespaces of pods and services.
+	Namespaces        []string              `json:"namespaces,omitempty"`
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Repeat is the interval that PAN refreshes the list, either "every five minutes", "hourly",
+	// "daily" at RepeatAt, "weekly" at RepeatAt of RepeatDayOfWeek, or "monthly" at RepeatAt of
+	// RepeatDayOfMonth. RepeatAt is the hour of the day, such as "03".
+	Repeat           string `json:"repeat,omitempty"`
+	RepeatAt         string `json:"repeatAt,omitempty"`
+	RepeatDayOfWeek  string `json:"repeatDayOfWeek,omitempty"`
+	RepeatDayOfMonth int    `json:"repeatDayOfMonth,omitempty"`
+	Description      string `json:"description,omitempty"`
+	// FirewallRef is the name of the Firewall that the resource is synced to.
+	FirewallRef string `json:"firewallRef,omitempty"`
+}
+
+type ExternalDynamicListPhase string
+
+// These are the valid phases of an external dynamic list.
+const (
+	ExternalDynamicListNone        ExternalDynamicListPhase = ""
+	ExternalDynamicListPending     ExternalDynamicListPhase = "Pending"
+	ExternalDynamicListActive      ExternalDynamicListPhase = "Active"
+	ExternalDynamicListFailed      ExternalDynamicListPhase = "Failed"
+	ExternalDynamicListTerminating ExternalDynamicListPhase = "Terminating"
+)
+
+// ExternalDynamicListStatus represents the current state of an external dynamic list resource.
+type ExternalDynamicListStatus struct {
+	Phase          ExternalDynamicListPhase `json:"phase"`
+	Reason         string                   `json:"reason,omitempty"`
+	URL            string                   `json:"url,omitempty"`
+	LastUpdateTime metav1.Time              `json:"lastUpdateTime"`
+}
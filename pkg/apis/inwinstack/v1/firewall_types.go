@@ -0,0 +1,114 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FirewallList is a list of firewall.
+type FirewallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Firewall `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Firewall represents a Kubernetes Firewall Custom Resource.
+// The Firewall describes a PAN firewall that the resources can be synced to.
+type Firewall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   FirewallSpec   `json:"spec"`
+	Status FirewallStatus `json:"status,omitempty"`
+}
+
+// FirewallSpec is the spec for a firewall resource.
+type FirewallSpec struct {
+	Host string `json:"host"`
+	// CredentialsSecret is the secret in the form of namespace/name, which has the
+	// username, password and api-key of the firewall.
+	CredentialsSecret string             `json:"credentialsSecret"`
+	Vsys              string             `json:"vsys,omitempty"`
+	Commit            FirewallCommitSpec `json:"commit,omitempty"`
+	HighAvailability  bool               `json:"highAvailability,omitempty"`
+	InspectorSeconds  int                `json:"inspectorSeconds,omitempty"`
+	// Panorama is set when the host is a Panorama, and then the resources are synced
+	// to the device group and pushed to its devices.
+	Panorama *FirewallPanoramaSpec `json:"panorama,omitempty"`
+	// DryRun plans the changes of the resources without editing or committing, and
+	// defaults to the option of the controller.
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
+// FirewallPanoramaSpec is the device group options of a Panorama.
+type FirewallPanoramaSpec struct {
+	DeviceGroup string `json:"deviceGroup"`
+	// Rulebase is either pre-rulebase or post-rulebase, and defaults to pre-rulebase.
+	Rulebase string `json:"rulebase,omitempty"`
+	// SharedObjects writes the objects to shared instead of the device group.
+	SharedObjects bool `json:"sharedObjects,omitempty"`
+}
+
+// FirewallCommitSpec is the commit options of a firewall. The options of the controller
+// are used when they aren't set.
+type FirewallCommitSpec struct {
+	Admins     []string `json:"admins,omitempty"`
+	DaNPartial *bool    `json:"danPartial,omitempty"`
+	PaOPartial *bool    `json:"paoPartial,omitempty"`
+	Force      *bool    `json:"force,omitempty"`
+	Sync       *bool    `json:"sync,omitempty"`
+	Lock       *bool    `json:"lock,omitempty"`
+	WaitTime   int      `json:"waitTime,omitempty"`
+	MaxDelay   int      `json:"maxDelay,omitempty"`
+	MaxBatch   int      `json:"maxBatch,omitempty"`
+	Retry      int      `json:"retry,omitempty"`
+}
+
+type FirewallPhase string
+
+// These are the valid phases of a firewall.
+const (
+	FirewallNone    FirewallPhase = ""
+	FirewallPending FirewallPhase = "Pending"
+	FirewallActive  FirewallPhase = "Active"
+	FirewallPassive FirewallPhase = "Passive"
+	FirewallFailed  FirewallPhase = "Failed"
+)
+
+// FirewallStatus represents the current state of a firewall resource.
+type FirewallStatus struct {
+	Phase          FirewallPhase `json:"phase"`
+	Reason         string        `json:"reason,omitempty"`
+	Version        string        `json:"version,omitempty"`
+	LastUpdateTime metav1.Time   `json:"lastUpdateTime"`
+	// Devices are the results of the last push to the devices of the device group.
+	Devices []FirewallDeviceStatus `json:"devices,omitempty"`
+}
+
+// FirewallDeviceStatus represents the push result of a device managed by Panorama.
+type FirewallDeviceStatus struct {
+	Serial  string   `json:"serial"`
+	Name    string   `json:"name,omitempty"`
+	Result  string   `json:"result"`
+	Details []string `json:"details,omitempty"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FirewallPolicyList is a list of firewall policies.
+type FirewallPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []FirewallPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FirewallPolicy represents a Kubernetes Firewall Policy Custom Resource.
+// The policy constrains what the Securities and NATs of the selected namespaces may use, and the
+// resources violating it are rejected by the validating webhook and never synced to PAN.
+type FirewallPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec FirewallPolicySpec `json:"spec"`
+}
+
+// FirewallPolicySpec is the spec for a firewall policy resource. The empty lists allow
+// everything, and a namespace selected by more than one policy has to satisfy all of them.
+type FirewallPolicySpec struct {
+	// NamespaceSelector selects the namespaces by labels. An empty selector selects all of them.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// AllowedZones are the source and destination zones which the resources may use. The empty
+	// zones are any, so they need "any" in the allowed zones.
+	AllowedZones []string `json:"allowedZones,omitempty"`
+	// AllowedDestinationCIDRs are the networks which the destination addresses must be within.
+	// The names of Address resources are resolved, and the other names aren't allowed.
+	AllowedDestinationCIDRs []string `json:"allowedDestinationCIDRs,omitempty"`
+	// AllowedServices are the services which the resources may use.
+	AllowedServices []string `json:"allowedServices,omitempty"`
+	// AllowedActions are the actions which the Securities may use, such as deny and drop.
+	AllowedActions []string `json:"allowedActions,omitempty"`
+	// ForbidAnySource forbids any in the source addresses.
+	ForbidAnySource bool `json:"forbidAnySource,omitempty"`
+}
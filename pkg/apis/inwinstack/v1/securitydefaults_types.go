@@ -0,0 +1,82 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecurityDefaultsList is a list of security defaults.
+type SecurityDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SecurityDefaults `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +resourceName=securitydefaults
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecurityDefaults represents a Kubernetes Security Defaults Custom Resource.
+// The defaults are filled in the Securities and NATs of the selected namespaces by the
+// mutating webhook of the controller.
+type SecurityDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec SecurityDefaultsSpec `json:"spec"`
+}
+
+// SecurityDefaultsSpec is the spec for a security defaults resource.
+type SecurityDefaultsSpec struct {
+	// NamespaceSelector selects the namespaces by labels. An empty selector selects all of them.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Priority decides the defaults of a namespace selected by more than one of them, and
+	// the higher one is applied.
+	Priority int32                 `json:"priority,omitempty"`
+	Security SecurityDefaultValues `json:"security,omitempty"`
+	NAT      NATDefaultValues      `json:"nat,omitempty"`
+	// Enforced are the fields of Security, such as logSetting, which are always set to the
+	// defaults instead of only when they're empty.
+	Enforced []string `json:"enforced,omitempty"`
+}
+
+// SecurityDefaultValues are the default values of the Securities. The tags are added to the
+// tags of every Security.
+type SecurityDefaultValues struct {
+	SourceZones      []string `json:"sourceZones,omitempty"`
+	DestinationZones []string `json:"destinationZones,omitempty"`
+	SourceUsers      []string `json:"sourceUsers,omitempty"`
+	HipProfiles      []string `json:"hipProfiles,omitempty"`
+	Categories       []string `json:"categories,omitempty"`
+	LogSetting       string   `json:"logSetting,omitempty"`
+	LogStart         *bool    `json:"logStart,omitempty"`
+	LogEnd           *bool    `json:"logEnd,omitempty"`
+	Group            string   `json:"group,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+// NATDefaultValues are the default values of the NATs. The tags are added to the tags of
+// every NAT.
+type NATDefaultValues struct {
+	SourceZones     []string `json:"sourceZones,omitempty"`
+	DestinationZone string   `json:"destinationZone,omitempty"`
+	ToInterface     string   `json:"toInterface,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+}
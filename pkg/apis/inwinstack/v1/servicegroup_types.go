@@ -0,0 +1,77 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceGroupList is a list of service group.
+type ServiceGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceGroup `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceGroup represents a Kubernetes ServiceGroup Custom Resource.
+// The ServiceGroup will be used as PA service group.
+type ServiceGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   ServiceGroupSpec   `json:"spec"`
+	Status ServiceGroupStatus `json:"status,omitempty"`
+}
+
+// These are the PAN predefined services that can be used as members
+// without a backing Service resource.
+const (
+	ServiceGroupHTTP  = "service-http"
+	ServiceGroupHTTPS = "service-https"
+)
+
+// ServiceGroupSpec is the spec for a service group resource.
+type ServiceGroupSpec struct {
+	Services []string `json:"services,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// FirewallRef is the name of the Firewall that the resource is synced to.
+	FirewallRef string `json:"firewallRef,omitempty"`
+}
+
+type ServiceGroupPhase string
+
+// These are the valid phases of a service group.
+const (
+	ServiceGroupNone        ServiceGroupPhase = ""
+	ServiceGroupPending     ServiceGroupPhase = "Pending"
+	ServiceGroupActive      ServiceGroupPhase = "Active"
+	ServiceGroupFailed      ServiceGroupPhase = "Failed"
+	ServiceGroupTerminating ServiceGroupPhase = "Terminating"
+)
+
+// ServiceGroupStatus represents the current state of a service group resource.
+type ServiceGroupStatus struct {
+	Phase           ServiceGroupPhase `json:"phase"`
+	Reason          string            `json:"reason,omitempty"`
+	MissingServices []string          `json:"missingServices,omitempty"`
+	LastUpdateTime  metav1.Time       `json:"lastUpdateTime"`
+}
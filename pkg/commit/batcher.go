@@ -0,0 +1,143 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commit
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// Batcher collects the objects of the commit jobs into batches. A batch is ready when no
+// object comes within the idle window, when its first object has waited for the max delay,
+// or when it reaches the max size, so a steady stream of changes can't postpone a commit.
+type Batcher struct {
+	clock    clock.Clock
+	queue    chan *Object
+	idle     time.Duration
+	maxDelay time.Duration
+	maxSize  int
+
+	lock      sync.Mutex
+	pending   int
+	batchSize int
+}
+
+// NewBatcher creates a batcher. The max delay and the max size are unlimited when they
+// aren't positive.
+func NewBatcher(idle, maxDelay time.Duration, maxSize int, clk clock.Clock) *Batcher {
+	size := maxSize
+	if size <= 0 {
+		size = 1
+	}
+	return &Batcher{
+		clock:    clk,
+		queue:    make(chan *Object, size),
+		idle:     idle,
+		maxDelay: maxDelay,
+		maxSize:  maxSize,
+	}
+}
+
+// Queue returns the channel that the objects are sent to.
+func (b *Batcher) Queue() chan *Object {
+	return b.queue
+}
+
+// QueueDepth returns the number of objects waiting for a commit.
+func (b *Batcher) QueueDepth() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.pending + len(b.queue)
+}
+
+// BatchSize returns the size of the last batch.
+func (b *Batcher) BatchSize() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.batchSize
+}
+
+// Drain removes the objects waiting in the queue without blocking, and returns them.
+func (b *Batcher) Drain() []*Object {
+	var objects []*Object
+	for {
+		select {
+		case obj := <-b.queue:
+			if obj != nil {
+				objects = append(objects, obj)
+			}
+		default:
+			return objects
+		}
+	}
+}
+
+// Next blocks until a batch is ready, and returns nil when stopCh is closed.
+func (b *Batcher) Next(stopCh <-chan struct{}) []*Object {
+	var batch []*Object
+	for len(batch) == 0 {
+		select {
+		case obj := <-b.queue:
+			if obj != nil {
+				batch = b.add(batch, obj)
+			}
+		case <-stopCh:
+			return nil
+		}
+	}
+
+	var deadline <-chan time.Time
+	if b.maxDelay > 0 {
+		timer := b.clock.NewTimer(b.maxDelay)
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+
+	for b.maxSize <= 0 || len(batch) < b.maxSize {
+		idle := b.clock.NewTimer(b.idle)
+		select {
+		case obj := <-b.queue:
+			idle.Stop()
+			if obj != nil {
+				batch = b.add(batch, obj)
+			}
+			continue
+		case <-idle.C():
+		case <-deadline:
+			idle.Stop()
+		case <-stopCh:
+			idle.Stop()
+			return nil
+		}
+		break
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.pending = 0
+	b.batchSize = len(batch)
+	return batch
+}
+
+func (b *Batcher) add(batch []*Object, obj *Object) []*Object {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.pending++
+	return append(batch, obj)
+}
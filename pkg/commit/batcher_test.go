@@ -0,0 +1,146 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+const timeout = 3 * time.Second
+
+func newObject(i int) *Object {
+	return NewObject(KindSecurity, metav1.ObjectMeta{Name: fmt.Sprintf("test-%d", i), Namespace: "default"})
+}
+
+func next(b *Batcher, stopCh <-chan struct{}) <-chan []*Object {
+	ch := make(chan []*Object, 1)
+	go func() { ch <- b.Next(stopCh) }()
+	return ch
+}
+
+func waitPending(t *testing.T, b *Batcher, clk *clock.FakeClock, n int) {
+	for start := time.Now(); time.Since(start) < timeout; {
+		b.lock.Lock()
+		pending := b.pending
+		b.lock.Unlock()
+		if pending == n && clk.HasWaiters() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("The batcher hasn't received %d objects.", n)
+}
+
+func TestBatcherIdle(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	clk := clock.NewFakeClock(time.Now())
+	b := NewBatcher(time.Second, 0, 0, clk)
+	batch := next(b, stopCh)
+
+	b.Queue() <- newObject(0)
+	waitPending(t, b, clk, 1)
+	b.Queue() <- newObject(1)
+	waitPending(t, b, clk, 2)
+	assert.Equal(t, 2, b.QueueDepth())
+
+	clk.Step(time.Second)
+	select {
+	case objects := <-batch:
+		assert.Equal(t, []*Object{newObject(0), newObject(1)}, objects)
+	case <-time.After(timeout):
+		t.Fatal("The batch isn't ready after the idle window.")
+	}
+	assert.Equal(t, 2, b.BatchSize())
+	assert.Equal(t, 0, b.QueueDepth())
+}
+
+func TestBatcherMaxDelay(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	clk := clock.NewFakeClock(time.Now())
+	b := NewBatcher(time.Second, 3*time.Second, 0, clk)
+	batch := next(b, stopCh)
+
+	// The objects keep coming within the idle window, so only the max delay flushes the batch.
+	for i := 0; i < 6; i++ {
+		b.Queue() <- newObject(i)
+		waitPending(t, b, clk, i+1)
+		clk.Step(500 * time.Millisecond)
+	}
+
+	select {
+	case objects := <-batch:
+		assert.Equal(t, 6, len(objects))
+	case <-time.After(timeout):
+		t.Fatal("The batch isn't ready after the max delay.")
+	}
+	assert.Equal(t, 6, b.BatchSize())
+}
+
+func TestBatcherMaxSize(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	clk := clock.NewFakeClock(time.Now())
+	b := NewBatcher(time.Second, time.Minute, 3, clk)
+	for i := 0; i < 3; i++ {
+		b.Queue() <- newObject(i)
+	}
+	assert.Equal(t, 3, b.QueueDepth())
+
+	select {
+	case objects := <-next(b, stopCh):
+		assert.Equal(t, 3, len(objects))
+	case <-time.After(timeout):
+		t.Fatal("The batch isn't ready after reaching the max size.")
+	}
+	assert.Equal(t, 3, b.BatchSize())
+	assert.Equal(t, 0, b.QueueDepth())
+}
+
+func TestBatcherStop(t *testing.T) {
+	stopCh := make(chan struct{})
+	clk := clock.NewFakeClock(time.Now())
+	b := NewBatcher(time.Second, time.Minute, 0, clk)
+	batch := next(b, stopCh)
+
+	b.Queue() <- newObject(0)
+	waitPending(t, b, clk, 1)
+	close(stopCh)
+	assert.Nil(t, <-batch)
+}
+
+func TestBatcherDrain(t *testing.T) {
+	clk := clock.NewFakeClock(time.Now())
+	b := NewBatcher(time.Second, time.Minute, 3, clk)
+	assert.Nil(t, b.Drain())
+
+	b.Queue() <- newObject(0)
+	b.Queue() <- nil
+	b.Queue() <- newObject(1)
+	assert.Equal(t, []*Object{newObject(0), newObject(1)}, b.Drain())
+	assert.Equal(t, 0, b.QueueDepth())
+}
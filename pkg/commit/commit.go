@@ -0,0 +1,194 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// These are the kinds of the resources synced to PAN.
+const (
+	KindAddress             = "Address"
+	KindAddressGroup        = "AddressGroup"
+	KindExternalDynamicList = "ExternalDynamicList"
+	KindNAT                 = "NAT"
+	KindSecurity            = "Security"
+	KindService             = "Service"
+	KindServiceGroup        = "ServiceGroup"
+)
+
+// Object represents a resource whose change is included in a commit.
+type Object struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// NewObject creates an object of the given kind.
+func NewObject(kind string, meta metav1.ObjectMeta) *Object {
+	return &Object{Kind: kind, Namespace: meta.Namespace, Name: meta.Name}
+}
+
+// String returns the object in the form of kind/namespace/name.
+func (o *Object) String() string {
+	if o.Namespace == "" {
+		return fmt.Sprintf("%s/%s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+// Unique returns the objects without the duplicated ones, the nil objects are dropped.
+func Unique(objects []*Object) []*Object {
+	seen := map[Object]bool{}
+	unique := make([]*Object, 0, len(objects))
+	for _, o := range objects {
+		if o == nil || seen[*o] {
+			continue
+		}
+		seen[*o] = true
+		unique = append(unique, o)
+	}
+	return unique
+}
+
+// Result represents the outcome of a commit job.
+type Result struct {
+	JobID     uint
+	Time      time.Time
+	Committed bool
+	Messages  []string
+}
+
+// Annotate records the result on the annotations of a resource.
+func (r *Result) Annotate(meta *metav1.ObjectMeta) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+
+	meta.Annotations[constants.CommittedKey] = "False"
+	if r.Committed {
+		meta.Annotations[constants.CommittedKey] = "True"
+	}
+	meta.Annotations[constants.CommitJobKey] = fmt.Sprintf("%d", r.JobID)
+	meta.Annotations[constants.CommitTimeKey] = r.Time.UTC().Format(time.RFC3339)
+	if len(r.Messages) != 0 {
+		meta.Annotations[constants.CommitMessageKey] = strings.Join(r.Messages, "; ")
+	} else {
+		delete(meta.Annotations, constants.CommitMessageKey)
+	}
+}
+
+type jobRequest struct {
+	XMLName xml.Name `xml:"show"`
+	ID      uint     `xml:"jobs>id"`
+}
+
+type jobResponse struct {
+	XMLName  xml.Name `xml:"response"`
+	Result   string   `xml:"result>job>result"`
+	Details  []string `xml:"result>job>details>line"`
+	Warnings []string `xml:"result>job>warnings>line"`
+}
+
+// Job returns the result of a completed commit job, where the messages are its warnings and
+// errors.
+func Job(client util.XapiClient, id uint) (*Result, error) {
+	ans := jobResponse{}
+	if _, err := client.Op(jobRequest{ID: id}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+
+	result := &Result{JobID: id, Time: time.Now(), Committed: ans.Result == "OK"}
+	result.Messages = append(result.Messages, ans.Warnings...)
+	if !result.Committed {
+		result.Messages = append(result.Messages, ans.Details...)
+	}
+	return result, nil
+}
+
+// Wait polls the commit job to completion, and returns its result. The result is returned
+// with the error when the job fails.
+func Wait(client util.XapiClient, id uint) (*Result, error) {
+	waitErr := client.WaitForJob(id, nil)
+	result, err := Job(client, id)
+	if err != nil {
+		if waitErr != nil {
+			return nil, waitErr
+		}
+		return nil, err
+	}
+
+	if waitErr != nil {
+		result.Committed = false
+		if len(result.Messages) == 0 {
+			result.Messages = []string{waitErr.Error()}
+		}
+		return result, waitErr
+	}
+
+	if !result.Committed {
+		return result, fmt.Errorf("commit job %d failed: %s", id, strings.Join(result.Messages, "; "))
+	}
+	return result, nil
+}
+
+type validateRequest struct {
+	XMLName xml.Name `xml:"validate"`
+	Full    string   `xml:"full"`
+}
+
+// Validate runs a validate job on the candidate config without committing it, and returns
+// the result of the job.
+func Validate(client util.XapiClient) (*Result, error) {
+	ans := util.JobResponse{}
+	if _, err := client.Op(validateRequest{}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+	return Wait(client, ans.Id)
+}
+
+type revertRequest struct {
+	XMLName xml.Name     `xml:"revert"`
+	Config  revertConfig `xml:"config"`
+}
+
+type revertConfig struct {
+	Partial *revertPartial `xml:"partial,omitempty"`
+}
+
+type revertPartial struct {
+	Admins *util.MemberType `xml:"admin"`
+}
+
+// Revert reverts the candidate config changed by the admins to the running config. The admins
+// must be set, since the changes of all the admins are reverted without them.
+func Revert(client util.XapiClient, admins []string) error {
+	if len(admins) == 0 {
+		return fmt.Errorf("the admins to revert aren't set")
+	}
+
+	req := revertRequest{Config: revertConfig{Partial: &revertPartial{Admins: util.StrToMem(admins)}}}
+	_, err := client.Op(req, "", nil, nil)
+	return err
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango/testdata"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnique(t *testing.T) {
+	sec := NewObject(KindSecurity, metav1.ObjectMeta{Name: "test", Namespace: "default"})
+	svc := NewObject(KindService, metav1.ObjectMeta{Name: "test"})
+	objects := Unique([]*Object{sec, nil, svc, NewObject(KindSecurity, metav1.ObjectMeta{Name: "test", Namespace: "default"})})
+	assert.Equal(t, []*Object{sec, svc}, objects)
+	assert.Equal(t, "Security/default/test", sec.String())
+	assert.Equal(t, "Service/test", svc.String())
+}
+
+func TestWait(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<job><id>5</id><result>OK</result>
+		<details><line>Configuration committed successfully</line></details>
+		<warnings><line>Rule 'test' shadows rule 'web'</line></warnings></job>`)
+
+	result, err := Wait(mc, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(5), result.JobID)
+	assert.True(t, result.Committed)
+	assert.Equal(t, []string{"Rule 'test' shadows rule 'web'"}, result.Messages)
+
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<job><id>6</id><result>FAIL</result>
+		<details><line>Validation Error: rule 'test' is invalid</line></details></job>`)
+
+	result, err = Wait(mc, 6)
+	assert.NotNil(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, []string{"Validation Error: rule 'test' is invalid"}, result.Messages)
+}
+
+func TestValidate(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<job>7</job>`)
+	job := `<job><id>7</id><result>FAIL</result>
+		<details><line>Validation Error: rule 'test' is invalid</line></details></job>`
+	mc.AddResp(job)
+	mc.AddResp(job)
+
+	result, err := Validate(mc)
+	assert.NotNil(t, err)
+	assert.Equal(t, uint(7), result.JobID)
+	assert.False(t, result.Committed)
+	assert.Equal(t, []string{"Validation Error: rule 'test' is invalid"}, result.Messages)
+}
+
+func TestAnnotate(t *testing.T) {
+	meta := &metav1.ObjectMeta{Annotations: map[string]string{constants.CommitMessageKey: "old"}}
+	result := &Result{JobID: 7, Time: time.Date(2018, 10, 1, 8, 0, 0, 0, time.UTC), Committed: true}
+	result.Annotate(meta)
+	assert.Equal(t, map[string]string{
+		constants.CommittedKey:  "True",
+		constants.CommitJobKey:  "7",
+		constants.CommitTimeKey: "2018-10-01T08:00:00Z",
+	}, meta.Annotations)
+}
+
+func TestRevert(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+
+	assert.Nil(t, Revert(mc, []string{"api"}))
+	assert.Equal(t, "<revert><config><partial><admin><member>api</member></admin></partial></config></revert>", mc.Elm)
+
+	mc.Reset()
+	assert.NotNil(t, Revert(mc, nil))
+	assert.Equal(t, "", mc.Elm)
+}
@@ -29,9 +29,61 @@ type Config struct {
 	MoveRule       string
 	Vsys           string
 	CommitWaitTime int
+	CommitMaxDelay int
+	CommitMaxBatch int
 	Admins         []string
 	DaNPartial     bool
 	PaOPartial     bool
 	Force          bool
 	Sync           bool
+	Lock           bool
+	DryRun         bool
+	Validate       bool
+	DriftPolicy    string
+	NamingScheme   string
+
+	// PredefinedServices are the services on PAN without a Service resource, which
+	// service groups don't wait for.
+	PredefinedServices []string
+
+	ClusterID      string
+	SweepSec       int
+	SweepThreshold int
+	SweepDryRun    bool
+
+	SyncLoadBalancer  bool
+	LBSourceZone      string
+	LBDestinationZone string
+
+	SyncNetworkPolicy bool
+
+	SyncPodTags     bool
+	PodTagResyncSec int
+
+	EDLListenAddress string
+	EDLURL           string
+
+	WebhookListenAddress string
+	WebhookCertFile      string
+	WebhookKeyFile       string
+	WebhookSelfSigned    bool
+	WebhookHosts         []string
+	WebhookConfiguration string
+
+	// WebhookTrustedUsers are the users allowed to set the entry names of the resources,
+	// such as the controller and the importer.
+	WebhookTrustedUsers []string
+
+	Panorama      bool
+	DeviceGroup   string
+	Rulebase      string
+	SharedObjects bool
+
+	// FirewallSecretNamespace is the namespace of the credentials secrets of Firewall
+	// resources, where the controller is granted to read the secrets.
+	FirewallSecretNamespace string
+
+	// Firewall is the name of the Firewall resource whose resources are synced, and
+	// it's empty for the default firewall.
+	Firewall string
 }
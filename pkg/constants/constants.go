@@ -0,0 +1,65 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+// These are the labels and annotations used by the PA controller.
+const (
+	// SyncKey is the annotation of a Kubernetes service to enable or disable
+	// syncing it into PAN policies.
+	SyncKey = "inwinstack.com/pa-sync"
+	// PublicIPKey is the annotation of a Kubernetes service that specifies the
+	// public address translated to the service external IP by the PAN NAT policy.
+	PublicIPKey = "inwinstack.com/pa-public-ip"
+	// OwnerKey is the label of the resources created by the PA controller,
+	// its value is the key of the owner object.
+	OwnerKey = "inwinstack.com/pa-owner"
+	// NetworkPolicyKey is the label of the resources translated from a Kubernetes
+	// network policy, its value is the key of the network policy.
+	NetworkPolicyKey = "inwinstack.com/pa-networkpolicy"
+	// FirewallKey is the label of a resource that selects the Firewall it is synced to.
+	// The resources without the label are synced to the default firewall.
+	FirewallKey = "inwinstack.com/pa-firewall"
+	// CommittedKey is the annotation of a resource that tells whether its last change is
+	// committed to PAN, either "True" or "False".
+	CommittedKey = "inwinstack.com/pa-committed"
+	// CommitJobKey is the annotation of a resource that has the ID of the last commit job
+	// including its change.
+	CommitJobKey = "inwinstack.com/pa-commit-job"
+	// CommitTimeKey is the annotation of a resource that has the time of the last commit job.
+	CommitTimeKey = "inwinstack.com/pa-commit-time"
+	// CommitMessageKey is the annotation of a resource that has the warnings and errors of
+	// the last commit job.
+	CommitMessageKey = "inwinstack.com/pa-commit-message"
+	// PlanKey is the annotation of a resource that has the changes to PAN planned in the
+	// dry-run mode.
+	PlanKey = "inwinstack.com/pa-plan"
+	// DriftPolicyKey is the annotation of a resource that tells how its entry drifted on PAN
+	// is handled, either "reapply" or "report".
+	DriftPolicyKey = "inwinstack.com/pa-drift-policy"
+	// DriftedKey is the annotation of a resource that has the fields of its entry drifted
+	// on PAN, and it's only set by the "report" drift policy.
+	DriftedKey = "inwinstack.com/pa-drifted"
+	// AdoptKey is the annotation of a resource imported from an existing entry on PAN,
+	// the entry is edited in place and a security policy is kept at its position.
+	AdoptKey = "inwinstack.com/pa-adopt"
+	// PositionKey is the annotation of a Security that positions its security policy in the
+	// rulebase, such as "top", "bottom", "before deny-all" or "directly-after allow-dns".
+	PositionKey = "inwinstack.com/pa-position"
+	// EntryNameKey is the annotation of a NAT, Security or Service that has the name of its
+	// entry on PAN, which is named by the naming scheme or imported from PAN.
+	EntryNameKey = "inwinstack.com/pa-entry-name"
+)
@@ -0,0 +1,215 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"sync/atomic"
+
+	"github.com/inwinstack/pango"
+	"github.com/inwinstack/pango/dev"
+	"github.com/inwinstack/pango/licen"
+	"github.com/inwinstack/pango/netw"
+	"github.com/inwinstack/pango/objs"
+	"github.com/inwinstack/pango/pnrm"
+	"github.com/inwinstack/pango/poli"
+	"github.com/inwinstack/pango/userid"
+	"github.com/inwinstack/pango/util"
+	"github.com/inwinstack/pango/version"
+)
+
+// Client is the PAN client shared by the controllers, the commits and the HA inspector. The
+// current client is swapped atomically when the credentials rotate, and it's never modified
+// once stored, so the callers always call PAN through a fully initialized client.
+type Client struct {
+	current atomic.Value
+}
+
+var _ util.XapiClient = &Client{}
+
+// NewClient creates a client of an initialized PAN client.
+func NewClient(client *pango.Client) *Client {
+	c := &Client{}
+	c.Store(client)
+	return c
+}
+
+// Load returns the current PAN client.
+func (c *Client) Load() *pango.Client {
+	return c.current.Load().(*pango.Client)
+}
+
+// Store replaces the current PAN client. The client must not be modified after it's stored.
+func (c *Client) Store(client *pango.Client) {
+	c.current.Store(client)
+}
+
+// BindFirewall binds the namespaces of the firewall to the client, so the objects and policies
+// use the current client after the credentials rotate.
+func (c *Client) BindFirewall(fw *pango.Firewall) {
+	fw.Network = &netw.FwNetw{}
+	fw.Network.Initialize(c)
+	fw.Device = &dev.FwDev{}
+	fw.Device.Initialize(c)
+	fw.Policies = &poli.FwPoli{}
+	fw.Policies.Initialize(c)
+	fw.Objects = &objs.FwObjs{}
+	fw.Objects.Initialize(c)
+	fw.Licensing = &licen.Licen{}
+	fw.Licensing.Initialize(c)
+	fw.UserId = &userid.UserId{}
+	fw.UserId.Initialize(c)
+}
+
+// BindPanorama binds the namespaces of Panorama to the client, so the objects and policies use
+// the current client after the credentials rotate.
+func (c *Client) BindPanorama(pano *pango.Panorama) {
+	pano.Device = &dev.PanoDev{}
+	pano.Device.Initialize(c)
+	pano.Licensing = &licen.Licen{}
+	pano.Licensing.Initialize(c)
+	pano.UserId = &userid.UserId{}
+	pano.UserId.Initialize(c)
+	pano.Panorama = &pnrm.Pnrm{}
+	pano.Panorama.Initialize(c)
+	pano.Objects = &objs.PanoObjs{}
+	pano.Objects.Initialize(c)
+	pano.Policies = &poli.PanoPoli{}
+	pano.Policies.Initialize(c)
+	pano.Network = &netw.PanoNetw{}
+	pano.Network.Initialize(c)
+}
+
+// Panorama returns a Panorama of the current client for the commands which only Panorama has,
+// such as the commit-all. Its namespaces aren't initialized.
+func (c *Client) Panorama() *pango.Panorama {
+	return &pango.Panorama{Client: *c.Load()}
+}
+
+// String implements util.XapiClient.
+func (c *Client) String() string {
+	return c.Load().String()
+}
+
+// Versioning implements util.XapiClient.
+func (c *Client) Versioning() version.Number {
+	return c.Load().Versioning()
+}
+
+// LogAction implements util.XapiClient.
+func (c *Client) LogAction(msg string, i ...interface{}) {
+	c.Load().LogAction(msg, i...)
+}
+
+// LogQuery implements util.XapiClient.
+func (c *Client) LogQuery(msg string, i ...interface{}) {
+	c.Load().LogQuery(msg, i...)
+}
+
+// LogOp implements util.XapiClient.
+func (c *Client) LogOp(msg string, i ...interface{}) {
+	c.Load().LogOp(msg, i...)
+}
+
+// LogUid implements util.XapiClient.
+func (c *Client) LogUid(msg string, i ...interface{}) {
+	c.Load().LogUid(msg, i...)
+}
+
+// Op implements util.XapiClient.
+func (c *Client) Op(req interface{}, vsys string, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Op(req, vsys, extras, ans)
+}
+
+// Show implements util.XapiClient.
+func (c *Client) Show(path, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Show(path, extras, ans)
+}
+
+// Get implements util.XapiClient.
+func (c *Client) Get(path, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Get(path, extras, ans)
+}
+
+// Delete implements util.XapiClient.
+func (c *Client) Delete(path, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Delete(path, extras, ans)
+}
+
+// Set implements util.XapiClient.
+func (c *Client) Set(path, element, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Set(path, element, extras, ans)
+}
+
+// Edit implements util.XapiClient.
+func (c *Client) Edit(path, element, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Edit(path, element, extras, ans)
+}
+
+// Move implements util.XapiClient.
+func (c *Client) Move(path interface{}, where, dst string, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Move(path, where, dst, extras, ans)
+}
+
+// Uid implements util.XapiClient.
+func (c *Client) Uid(cmd interface{}, vsys string, extras, ans interface{}) ([]byte, error) {
+	return c.Load().Uid(cmd, vsys, extras, ans)
+}
+
+// EntryListUsing implements util.XapiClient.
+func (c *Client) EntryListUsing(fn util.Retriever, path []string) ([]string, error) {
+	return c.Load().EntryListUsing(fn, path)
+}
+
+// MemberListUsing implements util.XapiClient.
+func (c *Client) MemberListUsing(fn util.Retriever, path []string) ([]string, error) {
+	return c.Load().MemberListUsing(fn, path)
+}
+
+// RequestPasswordHash implements util.XapiClient.
+func (c *Client) RequestPasswordHash(val string) (string, error) {
+	return c.Load().RequestPasswordHash(val)
+}
+
+// VsysImport implements util.XapiClient.
+func (c *Client) VsysImport(loc, tmpl, ts, vsys string, names []string) error {
+	return c.Load().VsysImport(loc, tmpl, ts, vsys, names)
+}
+
+// VsysUnimport implements util.XapiClient.
+func (c *Client) VsysUnimport(loc, tmpl, ts string, names []string) error {
+	return c.Load().VsysUnimport(loc, tmpl, ts, names)
+}
+
+// WaitForJob implements util.XapiClient.
+func (c *Client) WaitForJob(id uint, resp interface{}) error {
+	return c.Load().WaitForJob(id, resp)
+}
+
+// Commit implements util.XapiClient.
+func (c *Client) Commit(desc string, admins []string, dan, pao, force, sync bool) (uint, error) {
+	return c.Load().Commit(desc, admins, dan, pao, force, sync)
+}
+
+// PositionFirstEntity implements util.XapiClient.
+func (c *Client) PositionFirstEntity(mvt int, rel, ent string, path, elms []string) error {
+	return c.Load().PositionFirstEntity(mvt, rel, ent, path, elms)
+}
+
+// GetHighAvailabilityStatus implements util.XapiClient.
+func (c *Client) GetHighAvailabilityStatus() (*util.HighAvailability, error) {
+	return c.Load().GetHighAvailabilityStatus()
+}
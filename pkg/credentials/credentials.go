@@ -0,0 +1,105 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inwinstack/pango"
+	v1 "k8s.io/api/core/v1"
+)
+
+// These are the keys of the credentials secret.
+const (
+	HostKey     = "host"
+	UsernameKey = "username"
+	PasswordKey = "password"
+	APIKeyKey   = "api-key"
+)
+
+// Credentials represents the credentials of PAN firewall.
+type Credentials struct {
+	Host     string
+	Username string
+	Password string
+	APIKey   string
+}
+
+// ParseSecretName parses the secret name in the form of "namespace/name".
+func ParseSecretName(s string) (string, string, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("the credentials secret %q must be in the form of namespace/name", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// FromSecret reads the credentials from the secret.
+func FromSecret(secret *v1.Secret) (*Credentials, error) {
+	c := &Credentials{
+		Host:     string(secret.Data[HostKey]),
+		Username: string(secret.Data[UsernameKey]),
+		Password: string(secret.Data[PasswordKey]),
+		APIKey:   string(secret.Data[APIKeyKey]),
+	}
+
+	if c.APIKey == "" && (c.Username == "" || c.Password == "") {
+		return nil, fmt.Errorf("the secret '%s/%s' must have either the %s or both the %s and %s",
+			secret.Namespace, secret.Name, APIKeyKey, UsernameKey, PasswordKey)
+	}
+	return c, nil
+}
+
+// CheckHost returns an error when the credentials set a host other than the given one.
+func (c *Credentials) CheckHost(host string) error {
+	if c.Host != "" && c.Host != host {
+		return fmt.Errorf("the host %q of the credentials doesn't match the host %q", c.Host, host)
+	}
+	return nil
+}
+
+// Apply sets the credentials to the client. The host is kept when it's not set in the credentials.
+func (c *Credentials) Apply(client *pango.Client) {
+	if c.Host != "" {
+		client.Hostname = c.Host
+	}
+	client.Username = c.Username
+	client.Password = c.Password
+	client.ApiKey = c.APIKey
+}
+
+// Reinitialize initializes a client with the credentials, and swaps it for the current client
+// once it succeeds. The objects and policies are bound to the client, so the controllers use the
+// new client without being restarted.
+func Reinitialize(current *Client, c *Credentials) error {
+	previous := current.Load()
+	client := &pango.Client{
+		Hostname: previous.Hostname,
+		Protocol: previous.Protocol,
+		Port:     previous.Port,
+		Timeout:  previous.Timeout,
+		Target:   previous.Target,
+		Logging:  previous.Logging,
+	}
+	c.Apply(client)
+	if err := client.Initialize(); err != nil {
+		return err
+	}
+	current.Store(client)
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const resyncTime = time.Minute
+
+// Watcher watches the credentials secret, and re-initializes the PAN client when the
+// credentials rotate. The secret is resynced periodically, so a failed re-initialization is
+// retried.
+type Watcher struct {
+	client   *Client
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+
+	// host is the host which the credentials must match, and any host is allowed when it's empty.
+	host string
+
+	lock    sync.Mutex
+	current *Credentials
+	reinit  func(*Client, *Credentials) error
+}
+
+// NewWatcher creates an instance of the credentials watcher. The rotated credentials are rejected
+// when they set a host other than the given one, unless it's empty.
+func NewWatcher(kubeset kubernetes.Interface, namespace, name, host string, client *Client, current *Credentials) *Watcher {
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeset, resyncTime,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	w := &Watcher{
+		client:   client,
+		factory:  factory,
+		informer: factory.Core().V1().Secrets().Informer(),
+		host:     host,
+		current:  current,
+		reinit:   Reinitialize,
+	}
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: w.update,
+		UpdateFunc: func(old, new interface{}) {
+			w.update(new)
+		},
+	})
+	return w
+}
+
+// Run serves the credentials watcher
+func (w *Watcher) Run(ctx context.Context) error {
+	glog.Info("Starting the credentials watcher")
+	go w.factory.Start(ctx.Done())
+	if ok := cache.WaitForCacheSync(ctx.Done(), w.informer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+func (w *Watcher) update(obj interface{}) {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return
+	}
+
+	creds, err := FromSecret(secret)
+	if err != nil {
+		glog.Errorf("Failed to read the credentials: %+v.", err)
+		return
+	}
+
+	if w.host != "" {
+		if err := creds.CheckHost(w.host); err != nil {
+			glog.Errorf("Failed to rotate the credentials of secret '%s/%s', keeping the previous credentials: %+v.",
+				secret.Namespace, secret.Name, err)
+			return
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if reflect.DeepEqual(w.current, creds) {
+		return
+	}
+
+	glog.Infof("The credentials of secret '%s/%s' rotated, re-initializing PAN client.", secret.Namespace, secret.Name)
+	if err := w.reinit(w.client, creds); err != nil {
+		glog.Errorf("Failed to re-initialize PAN client, keeping the previous credentials: %+v.", err)
+		return
+	}
+	w.current = creds
+}
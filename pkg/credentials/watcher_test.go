@@ -0,0 +1,214 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/pango"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const timeout = 3 * time.Second
+
+func TestParseSecretName(t *testing.T) {
+	namespace, name, err := ParseSecretName("kube-system/pa-credentials")
+	assert.Nil(t, err)
+	assert.Equal(t, "kube-system", namespace)
+	assert.Equal(t, "pa-credentials", name)
+
+	for _, s := range []string{"pa-credentials", "/pa-credentials", "a/b/c"} {
+		_, _, err := ParseSecretName(s)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestFromSecret(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-credentials", Namespace: "kube-system"},
+		Data: map[string][]byte{
+			HostKey:     []byte("172.22.132.114"),
+			UsernameKey: []byte("api"),
+		},
+	}
+	_, err := FromSecret(secret)
+	assert.NotNil(t, err)
+
+	secret.Data[PasswordKey] = []byte("secret")
+	creds, err := FromSecret(secret)
+	assert.Nil(t, err)
+
+	client := &pango.Client{Hostname: "172.22.132.1", ApiKey: "old"}
+	creds.Apply(client)
+	assert.Equal(t, "172.22.132.114", client.Hostname)
+	assert.Equal(t, "api", client.Username)
+	assert.Equal(t, "secret", client.Password)
+	assert.Equal(t, "", client.ApiKey)
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-credentials", Namespace: "kube-system"},
+		Data:       map[string][]byte{APIKeyKey: []byte("key1")},
+	}
+	kubeset := kubefake.NewSimpleClientset(secret)
+	current, err := FromSecret(secret)
+	assert.Nil(t, err)
+
+	reinits := make(chan *Credentials, 1)
+	client := NewClient(&pango.Client{})
+	watcher := NewWatcher(kubeset, secret.Namespace, secret.Name, "", client, current)
+	watcher.reinit = func(c *Client, creds *Credentials) error {
+		assert.Equal(t, client, c)
+		reinits <- creds
+		return nil
+	}
+	assert.Nil(t, watcher.Run(ctx))
+
+	secretCopy := secret.DeepCopy()
+	secretCopy.Data[APIKeyKey] = []byte("key2")
+	_, err = kubeset.CoreV1().Secrets(secret.Namespace).Update(secretCopy)
+	assert.Nil(t, err)
+
+	select {
+	case c := <-reinits:
+		assert.Equal(t, "key2", c.APIKey)
+	case <-time.After(timeout):
+		t.Fatal("The PAN client hasn't re-initialized.")
+	}
+}
+
+func TestWatcherHost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-credentials", Namespace: "kube-system"},
+		Data:       map[string][]byte{APIKeyKey: []byte("key1")},
+	}
+	kubeset := kubefake.NewSimpleClientset(secret)
+	current, err := FromSecret(secret)
+	assert.Nil(t, err)
+
+	reinits := make(chan *Credentials, 2)
+	client := NewClient(&pango.Client{Hostname: "172.22.132.114"})
+	watcher := NewWatcher(kubeset, secret.Namespace, secret.Name, "172.22.132.114", client, current)
+	watcher.reinit = func(c *Client, creds *Credentials) error {
+		reinits <- creds
+		return nil
+	}
+	assert.Nil(t, watcher.Run(ctx))
+
+	// The credentials of another host are rejected, while the same host is allowed.
+	secretCopy := secret.DeepCopy()
+	secretCopy.Data[HostKey] = []byte("172.22.132.200")
+	secretCopy.Data[APIKeyKey] = []byte("key2")
+	_, err = kubeset.CoreV1().Secrets(secret.Namespace).Update(secretCopy)
+	assert.Nil(t, err)
+
+	secretCopy = secretCopy.DeepCopy()
+	secretCopy.Data[HostKey] = []byte("172.22.132.114")
+	secretCopy.Data[APIKeyKey] = []byte("key3")
+	_, err = kubeset.CoreV1().Secrets(secret.Namespace).Update(secretCopy)
+	assert.Nil(t, err)
+
+	select {
+	case c := <-reinits:
+		assert.Equal(t, "key3", c.APIKey)
+	case <-time.After(timeout):
+		t.Fatal("The PAN client hasn't re-initialized.")
+	}
+}
+
+func TestWatcherRotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-credentials", Namespace: "kube-system"},
+		Data:       map[string][]byte{HostKey: []byte("172.22.132.114"), APIKeyKey: []byte("key0")},
+	}
+	kubeset := kubefake.NewSimpleClientset(secret)
+	current, err := FromSecret(secret)
+	assert.Nil(t, err)
+
+	fw := &pango.Firewall{Client: pango.Client{Hostname: "172.22.132.114", ApiKey: "key0"}}
+	client := NewClient(&fw.Client)
+	client.BindFirewall(fw)
+	assert.NotNil(t, fw.Objects.Address)
+	assert.NotNil(t, fw.Policies.Security)
+
+	// The PAN client can't be initialized without a firewall, so the rotated client is
+	// swapped like Reinitialize does once it's initialized.
+	watcher := NewWatcher(kubeset, secret.Namespace, secret.Name, "", client, current)
+	watcher.reinit = func(c *Client, creds *Credentials) error {
+		rotated := *c.Load()
+		creds.Apply(&rotated)
+		c.Store(&rotated)
+		return nil
+	}
+	assert.Nil(t, watcher.Run(ctx))
+
+	// The workers keep calling the client while the credentials rotate.
+	stopCh := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stopCh:
+					return
+				default:
+					assert.Contains(t, client.String(), "Hostname:172.22.132.114")
+					assert.NotEqual(t, "", client.Load().ApiKey)
+				}
+			}
+		}()
+	}
+
+	const rotations = 10
+	for i := 1; i <= rotations; i++ {
+		secretCopy := secret.DeepCopy()
+		secretCopy.Data[APIKeyKey] = []byte(fmt.Sprintf("key%d", i))
+		_, err = kubeset.CoreV1().Secrets(secret.Namespace).Update(secretCopy)
+		assert.Nil(t, err)
+	}
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(10 * time.Millisecond) {
+		if client.Load().ApiKey == fmt.Sprintf("key%d", rotations) {
+			failed = false
+			break
+		}
+	}
+	close(stopCh)
+	wg.Wait()
+	assert.Equal(t, false, failed, "The PAN client hasn't rotated.")
+	assert.Equal(t, "key0", fw.Client.ApiKey)
+}
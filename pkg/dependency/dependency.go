@@ -0,0 +1,336 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dependency resolves the references of the Securities and NATs to the objects synced by
+// the other resources, so a rule isn't pushed before the objects it uses exist on PAN, and a
+// Service isn't deleted while it's still used.
+package dependency
+
+import (
+	"fmt"
+	"sort"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	blendedlisterv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	inwinlisterv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/thoas/go-funk"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Phase is the phase of the Securities and NATs waiting for the objects they reference.
+const Phase = "WaitingForDependencies"
+
+// Resolver resolves the references between the resources synced to a firewall. A nil resolver
+// resolves nothing, so the rules never wait and the services are always deleted.
+type Resolver struct {
+	cfg           *config.Config
+	services      blendedlisterv1.ServiceLister
+	securities    blendedlisterv1.SecurityLister
+	nats          blendedlisterv1.NATLister
+	serviceGroups inwinlisterv1.ServiceGroupLister
+	addresses     inwinlisterv1.AddressLister
+	addressGroups inwinlisterv1.AddressGroupLister
+	edls          inwinlisterv1.ExternalDynamicListLister
+	dependencies  []cache.SharedIndexInformer
+	dependents    []cache.SharedIndexInformer
+}
+
+// NewResolver creates an instance of the resolver
+func NewResolver(
+	cfg *config.Config,
+	informer blendedinformers.SharedInformerFactory,
+	inwinInformer inwininformers.SharedInformerFactory) *Resolver {
+	services := informer.Inwinstack().V1().Services()
+	securities := informer.Inwinstack().V1().Securities()
+	nats := informer.Inwinstack().V1().NATs()
+	serviceGroups := inwinInformer.Inwinstack().V1().ServiceGroups()
+	addresses := inwinInformer.Inwinstack().V1().Addresses()
+	addressGroups := inwinInformer.Inwinstack().V1().AddressGroups()
+	edls := inwinInformer.Inwinstack().V1().ExternalDynamicLists()
+	return &Resolver{
+		cfg:           cfg,
+		services:      services.Lister(),
+		securities:    securities.Lister(),
+		nats:          nats.Lister(),
+		serviceGroups: serviceGroups.Lister(),
+		addresses:     addresses.Lister(),
+		addressGroups: addressGroups.Lister(),
+		edls:          edls.Lister(),
+		dependencies: []cache.SharedIndexInformer{
+			services.Informer(),
+			serviceGroups.Informer(),
+			addresses.Informer(),
+			addressGroups.Informer(),
+			edls.Informer(),
+		},
+		dependents: []cache.SharedIndexInformer{
+			securities.Informer(),
+			nats.Informer(),
+			serviceGroups.Informer(),
+		},
+	}
+}
+
+// Synced returns the functions to check whether the caches of the resolver are synced.
+func (r *Resolver) Synced() []cache.InformerSynced {
+	if r == nil {
+		return nil
+	}
+
+	synced := []cache.InformerSynced{}
+	for _, i := range append(r.dependencies, r.dependents...) {
+		synced = append(synced, i.HasSynced)
+	}
+	return synced
+}
+
+// OnDependencyChange calls the handler with the name of a referenced object, which is a service,
+// a service group, an address, an address group or an external dynamic list, when it's changed.
+func (r *Resolver) OnDependencyChange(handler func(name string)) {
+	if r == nil {
+		return
+	}
+
+	call := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		// The services are referenced by the names of their service objects.
+		if svc, ok := obj.(*blendedv1.Service); ok {
+			handler(naming.EntryName(r.cfg.NamingScheme, svc.ObjectMeta))
+			return
+		}
+		if meta, err := apimeta.Accessor(obj); err == nil {
+			handler(meta.GetName())
+		}
+	}
+	for _, i := range r.dependencies {
+		i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    call,
+			UpdateFunc: func(old, new interface{}) { call(new) },
+			DeleteFunc: call,
+		})
+	}
+}
+
+// OnServiceReleased calls the handler with the names of the services which a Security, a NAT or
+// a service group stopped referencing, because it's changed or deleted.
+func (r *Resolver) OnServiceReleased(handler func(names []string)) {
+	if r == nil {
+		return
+	}
+
+	call := func(old, new interface{}) {
+		if tombstone, ok := old.(cache.DeletedFinalStateUnknown); ok {
+			old = tombstone.Obj
+		}
+
+		released := []string{}
+		for _, name := range referencedServices(old) {
+			if !funk.ContainsString(referencedServices(new), name) {
+				released = append(released, name)
+			}
+		}
+		if len(released) != 0 {
+			handler(released)
+		}
+	}
+	for _, i := range r.dependents {
+		i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: call,
+			DeleteFunc: func(obj interface{}) { call(obj, nil) },
+		})
+	}
+}
+
+// Resolve returns the names with the members of the service groups and the static members of
+// the address groups among them, recursively, so a rule also waits for the objects in the
+// groups it references. The names are unique, and each group is resolved once.
+func (r *Resolver) Resolve(names []string) []string {
+	if r == nil {
+		return names
+	}
+
+	resolved := []string{}
+	seen := map[string]bool{}
+	var resolve func(names []string)
+	resolve = func(names []string) {
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			resolved = append(resolved, name)
+
+			if grp, err := r.serviceGroups.Get(name); err == nil && firewall.Ref(grp.ObjectMeta, grp.Spec.FirewallRef) == r.cfg.Firewall {
+				resolve(grp.Spec.Services)
+			}
+			if grp, err := r.addressGroups.Get(name); err == nil && firewall.Ref(grp.ObjectMeta, grp.Spec.FirewallRef) == r.cfg.Firewall {
+				resolve(grp.Spec.StaticAddresses)
+			}
+		}
+	}
+	resolve(names)
+	return resolved
+}
+
+// Service returns the Service whose service object on PAN has the name, or nil when there is none.
+func (r *Resolver) Service(name string) *blendedv1.Service {
+	if r == nil {
+		return nil
+	}
+	return FindService(r.services, r.cfg.NamingScheme, name)
+}
+
+// FindService returns the Service whose service object on PAN has the name, or nil when there is
+// none. An imported Service may be named differently from its service object.
+func FindService(lister blendedlisterv1.ServiceLister, scheme, name string) *blendedv1.Service {
+	if svc, err := lister.Get(name); err == nil && naming.EntryName(scheme, svc.ObjectMeta) == name {
+		return svc
+	}
+
+	svcs, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	for _, svc := range svcs {
+		if naming.EntryName(scheme, svc.ObjectMeta) == name {
+			return svc
+		}
+	}
+	return nil
+}
+
+// Waiting returns the references which are synced by a resource of the firewall but aren't active
+// yet, such as "Service/k8s-tcp80", including the members of the referenced groups. The names
+// without a resource are objects on PAN or literals. Nothing is pushed in the dry-run mode, so
+// nothing waits there.
+func (r *Resolver) Waiting(names []string) []string {
+	if r == nil || r.cfg.DryRun {
+		return nil
+	}
+
+	waiting := []string{}
+	add := func(kind, name string, meta metav1.ObjectMeta, ref string, active bool) {
+		if firewall.Ref(meta, ref) == r.cfg.Firewall && !active {
+			waiting = append(waiting, fmt.Sprintf("%s/%s", kind, name))
+		}
+	}
+	for _, name := range r.Resolve(names) {
+		if svc := r.Service(name); svc != nil {
+			add("Service", name, svc.ObjectMeta, "", svc.Status.Phase == blendedv1.ServiceActive)
+		}
+		if grp, err := r.serviceGroups.Get(name); err == nil {
+			add("ServiceGroup", name, grp.ObjectMeta, grp.Spec.FirewallRef, grp.Status.Phase == inwinv1.ServiceGroupActive)
+		}
+		if addr, err := r.addresses.Get(name); err == nil {
+			add("Address", name, addr.ObjectMeta, addr.Spec.FirewallRef, addr.Status.Phase == inwinv1.AddressActive)
+		}
+		if grp, err := r.addressGroups.Get(name); err == nil {
+			add("AddressGroup", name, grp.ObjectMeta, grp.Spec.FirewallRef, grp.Status.Phase == inwinv1.AddressGroupActive)
+		}
+		if edl, err := r.edls.Get(name); err == nil {
+			add("ExternalDynamicList", name, edl.ObjectMeta, edl.Spec.FirewallRef, edl.Status.Phase == inwinv1.ExternalDynamicListActive)
+		}
+	}
+	return waiting
+}
+
+// ServiceReferences returns the Securities, NATs and service groups of the firewall which still
+// reference a service, such as "Security default/allow-web".
+func (r *Resolver) ServiceReferences(name string) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	refs := []string{}
+	secs, err := r.securities.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, sec := range secs {
+		if firewall.Ref(sec.ObjectMeta, "") == r.cfg.Firewall && funk.ContainsString(sec.Spec.Services, name) {
+			refs = append(refs, fmt.Sprintf("Security %s/%s", sec.Namespace, sec.Name))
+		}
+	}
+
+	nats, err := r.nats.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, nat := range nats {
+		if firewall.Ref(nat.ObjectMeta, "") == r.cfg.Firewall && nat.Spec.Service == name {
+			refs = append(refs, fmt.Sprintf("NAT %s/%s", nat.Namespace, nat.Name))
+		}
+	}
+
+	groups, err := r.serviceGroups.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, grp := range groups {
+		if firewall.Ref(grp.ObjectMeta, grp.Spec.FirewallRef) == r.cfg.Firewall && funk.ContainsString(grp.Spec.Services, name) {
+			refs = append(refs, fmt.Sprintf("ServiceGroup %s", grp.Name))
+		}
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// SecurityRefs returns the names of the objects which a Security references.
+func SecurityRefs(spec blendedv1.SecuritySpec) []string {
+	refs := append([]string{}, spec.Services...)
+	refs = append(refs, spec.SourceAddresses...)
+	return append(refs, spec.DestinationAddresses...)
+}
+
+// NATRefs returns the names of the objects which a NAT references.
+func NATRefs(spec blendedv1.NATSpec) []string {
+	refs := append([]string{}, spec.SourceAddresses...)
+	refs = append(refs, spec.DestinationAddresses...)
+	refs = append(refs, spec.SatTranslatedAddresses...)
+	refs = append(refs, spec.SatFallbackTranslatedAddresses...)
+	if spec.Service != "" {
+		refs = append(refs, spec.Service)
+	}
+	if spec.DatAddress != "" {
+		refs = append(refs, spec.DatAddress)
+	}
+	return refs
+}
+
+func referencedServices(obj interface{}) []string {
+	switch o := obj.(type) {
+	case *blendedv1.Security:
+		return o.Spec.Services
+	case *blendedv1.NAT:
+		if o.Spec.Service != "" {
+			return []string{o.Spec.Service}
+		}
+	case *inwinv1.ServiceGroup:
+		return o.Spec.Services
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency
+
+import (
+	"context"
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newResolver(t *testing.T, ctx context.Context, cfg *config.Config) *Resolver {
+	blendedset := blendedfake.NewSimpleClientset(
+		&blendedv1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "k8s-tcp80"},
+			Status:     blendedv1.ServiceStatus{Phase: blendedv1.ServicePending},
+		},
+		&blendedv1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "k8s-tcp443"},
+			Status:     blendedv1.ServiceStatus{Phase: blendedv1.ServiceActive},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+			Spec:       blendedv1.SecuritySpec{Services: []string{"k8s-tcp80", "k8s-tcp443"}},
+		},
+		&blendedv1.NAT{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       blendedv1.NATSpec{Service: "k8s-tcp80"},
+		},
+	)
+	inwinset := inwinfake.NewSimpleClientset(
+		&inwinv1.ServiceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec:       inwinv1.ServiceGroupSpec{Services: []string{"k8s-tcp443"}},
+			Status:     inwinv1.ServiceGroupStatus{Phase: inwinv1.ServiceGroupActive},
+		},
+		&inwinv1.Address{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-vip"},
+			Status:     inwinv1.AddressStatus{Phase: inwinv1.AddressFailed},
+		},
+		&inwinv1.Address{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-vip"},
+			Spec:       inwinv1.AddressSpec{FirewallRef: "dc2"},
+		},
+		&inwinv1.Address{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache-vip"},
+			Status:     inwinv1.AddressStatus{Phase: inwinv1.AddressPending},
+		},
+		&inwinv1.ServiceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "all"},
+			Spec:       inwinv1.ServiceGroupSpec{Services: []string{"web", "k8s-tcp80"}},
+			Status:     inwinv1.ServiceGroupStatus{Phase: inwinv1.ServiceGroupActive},
+		},
+		&inwinv1.AddressGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backends"},
+			Spec:       inwinv1.AddressGroupSpec{StaticAddresses: []string{"cache-vip", "frontends", "10.0.0.1"}},
+			Status:     inwinv1.AddressGroupStatus{Phase: inwinv1.AddressGroupActive},
+		},
+		&inwinv1.AddressGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontends"},
+			Spec:       inwinv1.AddressGroupSpec{StaticAddresses: []string{"backends", "web-vip"}},
+			Status:     inwinv1.AddressGroupStatus{Phase: inwinv1.AddressGroupPending},
+		},
+	)
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	r := NewResolver(cfg, informer, inwinInformer)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	assert.True(t, cache.WaitForCacheSync(ctx.Done(), r.Synced()...))
+	return r
+}
+
+func TestWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := newResolver(t, ctx, &config.Config{})
+
+	refs := SecurityRefs(blendedv1.SecuritySpec{
+		Services:             []string{"k8s-tcp80", "k8s-tcp443", "web", "application-default"},
+		SourceAddresses:      []string{"any"},
+		DestinationAddresses: []string{"web-vip", "db-vip", "10.0.0.0/24"},
+	})
+	assert.Equal(t, []string{"Service/k8s-tcp80", "Address/web-vip"}, r.Waiting(refs))
+
+	// The members of the groups are waited for as well, and the groups referencing each other
+	// are resolved once.
+	refs = SecurityRefs(blendedv1.SecuritySpec{
+		Services:             []string{"all"},
+		DestinationAddresses: []string{"backends"},
+	})
+	assert.Equal(t, []string{"all", "web", "k8s-tcp443", "k8s-tcp80", "backends", "cache-vip", "frontends", "web-vip", "10.0.0.1"}, r.Resolve(refs))
+	assert.Equal(t, []string{"Service/k8s-tcp80", "Address/cache-vip", "AddressGroup/frontends", "Address/web-vip"}, r.Waiting(refs))
+
+	// Nothing waits in the dry-run mode, or without a resolver.
+	dry := newResolver(t, ctx, &config.Config{DryRun: true})
+	assert.Nil(t, dry.Waiting(refs))
+	assert.Nil(t, (*Resolver)(nil).Waiting(refs))
+}
+
+func TestServiceReferences(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := newResolver(t, ctx, &config.Config{})
+
+	refs, err := r.ServiceReferences("k8s-tcp80")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NAT default/web", "Security default/allow-web", "ServiceGroup all"}, refs)
+
+	refs, err = r.ServiceReferences("k8s-tcp443")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Security default/allow-web", "ServiceGroup web"}, refs)
+
+	refs, err = r.ServiceReferences("k8s-udp53")
+	assert.Nil(t, err)
+	assert.Empty(t, refs)
+}
+
+func TestNATRefs(t *testing.T) {
+	refs := NATRefs(blendedv1.NATSpec{
+		SourceAddresses:        []string{"any"},
+		DestinationAddresses:   []string{"web-vip"},
+		SatTranslatedAddresses: []string{"snat-pool"},
+		Service:                "k8s-tcp80",
+	})
+	assert.Equal(t, []string{"any", "web-vip", "snat-pool", "k8s-tcp80"}, refs)
+}
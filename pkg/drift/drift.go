@@ -0,0 +1,79 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift handles the entries changed on PAN out of the controller, such as by the
+// admins on the web interface.
+package drift
+
+import (
+	"strings"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// These are the valid drift policies.
+const (
+	// PolicyReapply re-applies the spec of the resource to the drifted entry.
+	PolicyReapply = "reapply"
+	// PolicyReport reports the drifted fields on the resource, and keeps the entry.
+	PolicyReport = "report"
+)
+
+// Policy returns the drift policy of a resource, and the default policy is used when the
+// resource doesn't set a valid one.
+func Policy(meta metav1.ObjectMeta, def string) string {
+	switch p := meta.Annotations[constants.DriftPolicyKey]; p {
+	case PolicyReapply, PolicyReport:
+		return p
+	}
+
+	if def == PolicyReport {
+		return PolicyReport
+	}
+	return PolicyReapply
+}
+
+// Fields returns the names of the fields in the changes.
+func Fields(changes []plan.Change) []string {
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	return fields
+}
+
+// Record records the drifted fields on the annotations of a resource, and the annotation
+// is removed when no field drifts. It returns false when the resource is unchanged.
+func Record(meta *metav1.ObjectMeta, fields []string) bool {
+	old, ok := meta.Annotations[constants.DriftedKey]
+	if len(fields) == 0 {
+		delete(meta.Annotations, constants.DriftedKey)
+		return ok
+	}
+
+	s := strings.Join(fields, ",")
+	if ok && old == s {
+		return false
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[constants.DriftedKey] = s
+	return true
+}
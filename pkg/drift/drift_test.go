@@ -0,0 +1,52 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPolicy(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "test"}
+	assert.Equal(t, PolicyReapply, Policy(meta, ""))
+	assert.Equal(t, PolicyReport, Policy(meta, PolicyReport))
+
+	meta.Annotations = map[string]string{constants.DriftPolicyKey: PolicyReapply}
+	assert.Equal(t, PolicyReapply, Policy(meta, PolicyReport))
+
+	meta.Annotations[constants.DriftPolicyKey] = "unknown"
+	assert.Equal(t, PolicyReport, Policy(meta, PolicyReport))
+}
+
+func TestRecord(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "test"}
+	assert.False(t, Record(&meta, nil))
+
+	fields := Fields([]plan.Change{{Field: "Action"}, {Field: "SourceZones"}})
+	assert.True(t, Record(&meta, fields))
+	assert.Equal(t, "Action,SourceZones", meta.Annotations[constants.DriftedKey])
+	assert.False(t, Record(&meta, fields))
+
+	assert.True(t, Record(&meta, nil))
+	_, ok := meta.Annotations[constants.DriftedKey]
+	assert.False(t, ok)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	inwinlisterv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/thoas/go-funk"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Server serves the IPs selected by the external dynamic lists as plain text.
+type Server struct {
+	addr      string
+	lister    inwinlisterv1.ExternalDynamicListLister
+	podLister listerv1.PodLister
+	nodLister listerv1.NodeLister
+	svcLister listerv1.ServiceLister
+	nsLister  listerv1.NamespaceLister
+	synced    []cache.InformerSynced
+	server    *http.Server
+}
+
+// NewServer creates an instance of the external dynamic list server
+func NewServer(addr string, kubeInformer informers.SharedInformerFactory, inwinInformer inwininformers.SharedInformerFactory) *Server {
+	edlInformer := inwinInformer.Inwinstack().V1().ExternalDynamicLists()
+	podInformer := kubeInformer.Core().V1().Pods()
+	nodInformer := kubeInformer.Core().V1().Nodes()
+	svcInformer := kubeInformer.Core().V1().Services()
+	nsInformer := kubeInformer.Core().V1().Namespaces()
+	s := &Server{
+		addr:      addr,
+		lister:    edlInformer.Lister(),
+		podLister: podInformer.Lister(),
+		nodLister: nodInformer.Lister(),
+		svcLister: svcInformer.Lister(),
+		nsLister:  nsInformer.Lister(),
+		synced: []cache.InformerSynced{
+			edlInformer.Informer().HasSynced,
+			podInformer.Informer().HasSynced,
+			nodInformer.Informer().HasSynced,
+			svcInformer.Informer().HasSynced,
+			nsInformer.Informer().HasSynced,
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/edl/", s.handle)
+	s.server = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// Run serves the external dynamic list server
+func (s *Server) Run(ctx context.Context) error {
+	glog.Info("Starting the external dynamic list server")
+	glog.Info("Waiting for the external dynamic list informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), s.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	go func() {
+		glog.Infof("Serving external dynamic lists on %s", s.addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			glog.Errorf("Failed to serve external dynamic lists: %+v.", err)
+		}
+	}()
+	return nil
+}
+
+// Stop stops the external dynamic list server
+func (s *Server) Stop() {
+	glog.Info("Stopping the external dynamic list server")
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		glog.Errorf("Failed to stop the external dynamic list server: %+v.", err)
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/edl/")
+	list, err := s.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ips, err := s.IPs(list)
+	if err != nil {
+		glog.Errorf("ExternalDynamicList '%s' can't be served: %+v.", name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, ip := range ips {
+		fmt.Fprintln(w, ip)
+	}
+}
+
+func selectorOf(sel *metav1.LabelSelector) (labels.Selector, error) {
+	if sel == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(sel)
+}
+
+// namespaces returns the namespaces selected by the list.
+func (s *Server) namespaces(list *inwinv1.ExternalDynamicList) ([]string, error) {
+	selector, err := selectorOf(list.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	nss, err := s.nsLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, ns := range nss {
+		if len(list.Spec.Namespaces) == 0 || funk.ContainsString(list.Spec.Namespaces, ns.Name) {
+			names = append(names, ns.Name)
+		}
+	}
+	return names, nil
+}
+
+// IPs returns the sorted IPs selected by the external dynamic list.
+func (s *Server) IPs(list *inwinv1.ExternalDynamicList) ([]string, error) {
+	selector, err := selectorOf(list.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []string{}
+	switch list.Spec.Source {
+	case inwinv1.ExternalDynamicListNode:
+		nodes, err := s.nodLister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			for _, addr := range node.Status.Addresses {
+				if addr.Type == v1.NodeInternalIP || addr.Type == v1.NodeExternalIP {
+					ips = append(ips, addr.Address)
+				}
+			}
+		}
+	case inwinv1.ExternalDynamicListPod, inwinv1.ExternalDynamicListService:
+		namespaces, err := s.namespaces(list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ns := range namespaces {
+			var nsIPs []string
+			if list.Spec.Source == inwinv1.ExternalDynamicListPod {
+				nsIPs, err = s.podIPs(ns, selector)
+			} else {
+				nsIPs, err = s.serviceIPs(ns, selector)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+			ips = append(ips, nsIPs...)
+		}
+	default:
+		return nil, fmt.Errorf("the source %s is not supported", list.Spec.Source)
+	}
+
+	ips = funk.UniqString(ips)
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (s *Server) podIPs(namespace string, selector labels.Selector) ([]string, error) {
+	pods, err := s.podLister.Pods(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []string{}
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || pod.Status.PodIP == "" {
+			continue
+		}
+
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		ips = append(ips, pod.Status.PodIP)
+	}
+	return ips, nil
+}
+
+func (s *Server) serviceIPs(namespace string, selector labels.Selector) ([]string, error) {
+	svcs, err := s.svcLister.Services(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []string{}
+	for _, svc := range svcs {
+		if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != v1.ClusterIPNone {
+			ips = append(ips, svc.Spec.ClusterIP)
+		}
+
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				ips = append(ips, ingress.IP)
+			}
+		}
+		ips = append(ips, svc.Spec.ExternalIPs...)
+	}
+	return ips, nil
+}
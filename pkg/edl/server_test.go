@@ -0,0 +1,106 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	kubeset := kubefake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"env": "prod"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.244.1.11"},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.244.1.10"},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-3", Namespace: "test", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.244.2.10"},
+		},
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status: v1.NodeStatus{Addresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "172.22.132.11"},
+				{Type: v1.NodeHostName, Address: "node1"},
+			}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+			Spec:       v1.ServiceSpec{ClusterIP: "10.96.0.10", ExternalIPs: []string{"172.22.132.100"}},
+		},
+	)
+	inwinset := inwinfake.NewSimpleClientset(&inwinv1.ExternalDynamicList{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pods"},
+		Spec: inwinv1.ExternalDynamicListSpec{
+			Source:            inwinv1.ExternalDynamicListPod,
+			Selector:          &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	})
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	server := NewServer("127.0.0.1:0", kubeInformer, inwinInformer)
+	go kubeInformer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	assert.Nil(t, server.Run(ctx))
+
+	rec := httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/edl/web-pods", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10.244.1.10\n10.244.1.11\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	server.server.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/edl/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	nodes := &inwinv1.ExternalDynamicList{Spec: inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListNode}}
+	ips, err := server.IPs(nodes)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"172.22.132.11"}, ips)
+
+	services := &inwinv1.ExternalDynamicList{
+		Spec: inwinv1.ExternalDynamicListSpec{
+			Source:     inwinv1.ExternalDynamicListService,
+			Namespaces: []string{"test"},
+		},
+	}
+	ips, err = server.IPs(services)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.96.0.10", "172.22.132.100"}, ips)
+
+	cancel()
+	server.Stop()
+}
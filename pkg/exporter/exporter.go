@@ -0,0 +1,219 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exporter renders the resources into the PAN configuration, as the set commands of
+// the CLI and as the XML config, so the changes can be reviewed and applied offline.
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blended "github.com/inwinstack/blended/generated/clientset/versioned"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	natctrl "github.com/inwinstack/pa-controller/pkg/operator/pan/nat"
+	secctrl "github.com/inwinstack/pa-controller/pkg/operator/pan/security"
+	svcctrl "github.com/inwinstack/pa-controller/pkg/operator/pan/service"
+	"github.com/inwinstack/pa-controller/pkg/panorama"
+	"github.com/inwinstack/pa-controller/pkg/placement"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/poli/nat"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/inwinstack/pango/util"
+	"github.com/inwinstack/pango/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// edit is an entry edited on the xpath.
+type edit struct {
+	xpath   []string
+	element interface{}
+}
+
+// recorder records the edits of the pango clients instead of sending them to PAN, so the
+// entries are rendered by pango as the ones of the controller. Only the methods used by
+// the edits are implemented.
+type recorder struct {
+	util.XapiClient
+	version version.Number
+	edits   []edit
+	index   map[string]int
+}
+
+func (r *recorder) Versioning() version.Number       { return r.version }
+func (r *recorder) LogAction(string, ...interface{}) {}
+func (r *recorder) Edit(xpath, element, _, _ interface{}) ([]byte, error) {
+	path, ok := xpath.([]string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported xpath %v", xpath)
+	}
+
+	// The entry of the same xpath is replaced, as it's edited on PAN.
+	key := strings.Join(path, "/")
+	if i, ok := r.index[key]; ok {
+		r.edits[i].element = element
+		return nil, nil
+	}
+	r.index[key] = len(r.edits)
+	r.edits = append(r.edits, edit{xpath: path, element: element})
+	return nil, nil
+}
+
+type natClient interface {
+	Edit(vsys string, e nat.Entry) error
+}
+
+type securityClient interface {
+	Edit(vsys string, e security.Entry) error
+}
+
+type serviceClient interface {
+	Edit(vsys string, e srvc.Entry) error
+}
+
+// Exporter renders the NATs, Securities and Services into the configuration of a firewall
+// or a device group of Panorama.
+type Exporter struct {
+	cfg      *config.Config
+	rec      *recorder
+	nat      natClient
+	security securityClient
+	service  serviceClient
+}
+
+// New creates an exporter for the PAN-OS version. The location of the entries is set by the
+// vsys, or by the device group and rulebase of Panorama in the config.
+func New(cfg *config.Config, v version.Number) *Exporter {
+	rec := &recorder{version: v, index: map[string]int{}}
+	e := &Exporter{cfg: cfg, rec: rec}
+	if cfg.Panorama {
+		location := cfg.DeviceGroup
+		if cfg.SharedObjects {
+			location = panorama.Shared
+		}
+
+		pnat, psec, psvc := &nat.PanoNat{}, &security.PanoSecurity{}, &srvc.PanoSrvc{}
+		pnat.Initialize(rec)
+		psec.Initialize(rec)
+		psvc.Initialize(rec)
+		e.nat = panorama.NewNat(pnat, cfg.DeviceGroup, cfg.Rulebase)
+		e.security = panorama.NewSecurity(psec, cfg.DeviceGroup, cfg.Rulebase)
+		e.service = panorama.NewServices(psvc, location)
+		return e
+	}
+
+	fnat, fsec, fsvc := &nat.FwNat{}, &security.FwSecurity{}, &srvc.FwSrvc{}
+	fnat.Initialize(rec)
+	fsec.Initialize(rec)
+	fsvc.Initialize(rec)
+	e.nat, e.security, e.service = fnat, fsec, fsvc
+	return e
+}
+
+// Add renders a NAT, Security or Service resource.
+func (e *Exporter) Add(obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *blendedv1.NAT:
+		return e.nat.Edit(e.cfg.Vsys, *natctrl.NewPolicy(e.cfg, o))
+	case *blendedv1.Security:
+		return e.security.Edit(e.cfg.Vsys, *secctrl.NewPolicy(e.cfg, o))
+	case *blendedv1.Service:
+		return e.service.Edit(e.cfg.Vsys, *svcctrl.NewObject(e.cfg, o))
+	}
+	return fmt.Errorf("unknown resource %T", obj)
+}
+
+// List returns the Services, NATs and Securities synced to the firewall of the config, and an
+// empty firewall means the default one. Only the active resources, which are synced on PAN,
+// are listed unless all is set, and the ones being deleted are excluded. The Services and NATs
+// are sorted by their keys, and the Securities are in the order of their placements.
+func List(cfg *config.Config, client blended.Interface, all bool) ([]runtime.Object, error) {
+	opts := metav1.ListOptions{}
+	objs := []runtime.Object{}
+
+	svcs, err := client.InwinstackV1().Services().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(svcs.Items, func(i, j int) bool { return svcs.Items[i].Name < svcs.Items[j].Name })
+	for i := range svcs.Items {
+		active := svcs.Items[i].Status.Phase == blendedv1.ServiceActive
+		if isSynced(svcs.Items[i].ObjectMeta, cfg.Firewall) && (all || active) {
+			objs = append(objs, &svcs.Items[i])
+		}
+	}
+
+	nats, err := client.InwinstackV1().NATs(metav1.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nats.Items, func(i, j int) bool { return key(nats.Items[i].ObjectMeta) < key(nats.Items[j].ObjectMeta) })
+	for i := range nats.Items {
+		active := nats.Items[i].Status.Phase == blendedv1.NATActive
+		if isSynced(nats.Items[i].ObjectMeta, cfg.Firewall) && (all || active) {
+			objs = append(objs, &nats.Items[i])
+		}
+	}
+
+	secs, err := client.InwinstackV1().Securities(metav1.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(secs.Items, func(i, j int) bool { return key(secs.Items[i].ObjectMeta) < key(secs.Items[j].ObjectMeta) })
+
+	// The policies are created at the bottom in the order of their keys, and then moved to
+	// their placements as the controller does.
+	names := []string{}
+	placements := map[string]placement.Placement{}
+	byName := map[string]*blendedv1.Security{}
+	for i := range secs.Items {
+		sec := &secs.Items[i]
+		active := sec.Status.Phase == blendedv1.SecurityActive
+		if !isSynced(sec.ObjectMeta, cfg.Firewall) || !(all || active) {
+			continue
+		}
+
+		// An entry is owned by one resource, and the others of the same entry name fail on
+		// the controller, so the entry is rendered once.
+		name := naming.EntryName(cfg.NamingScheme, sec.ObjectMeta)
+		if _, ok := byName[name]; ok {
+			continue
+		}
+
+		if p, err := placement.Of(sec.ObjectMeta, name, secctrl.DefaultPlacement(cfg, sec)); err == nil {
+			placements[name] = p
+		}
+		names = append(names, name)
+		byName[name] = sec
+	}
+	for _, name := range placement.Order(names, placements) {
+		objs = append(objs, byName[name])
+	}
+	return objs, nil
+}
+
+func isSynced(meta metav1.ObjectMeta, firewallName string) bool {
+	return meta.DeletionTimestamp.IsZero() && firewall.Ref(meta, "") == firewallName
+}
+
+func key(meta metav1.ObjectMeta) string {
+	return meta.Namespace + "/" + meta.Name
+}
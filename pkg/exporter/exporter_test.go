@@ -0,0 +1,151 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango/version"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExport(t *testing.T) {
+	blendedset := blendedfake.NewSimpleClientset(
+		&blendedv1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec:       blendedv1.ServiceSpec{Protocol: "tcp", DestinationPort: "80,443"},
+			Status:     blendedv1.ServiceStatus{Phase: blendedv1.ServiceActive},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+			Spec: blendedv1.SecuritySpec{
+				Description:          "allow the web",
+				SourceZones:          []string{"untrust"},
+				SourceAddresses:      []string{"any"},
+				DestinationZones:     []string{"trust"},
+				DestinationAddresses: []string{"10.0.0.1", "10.0.0.2"},
+				Services:             []string{"web"},
+				Action:               "allow",
+			},
+			Status: blendedv1.SecurityStatus{Phase: blendedv1.SecurityActive},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "deny-all",
+				Namespace:   "default",
+				Annotations: map[string]string{constants.PositionKey: "bottom"},
+			},
+			Spec:   blendedv1.SecuritySpec{Action: "deny"},
+			Status: blendedv1.SecurityStatus{Phase: blendedv1.SecurityActive},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "z-first",
+				Namespace:   "default",
+				Annotations: map[string]string{constants.PositionKey: "top"},
+			},
+			Spec:   blendedv1.SecuritySpec{Action: "allow"},
+			Status: blendedv1.SecurityStatus{Phase: blendedv1.SecurityActive},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+			Status:     blendedv1.SecurityStatus{Phase: blendedv1.SecurityPending},
+		},
+		&blendedv1.Security{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "default",
+				Labels:    map[string]string{constants.FirewallKey: "fw2"},
+			},
+		},
+	)
+
+	cfg := &config.Config{ClusterID: "k8s-a"}
+	objs, err := List(cfg, blendedset, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(objs))
+
+	objs, err = List(cfg, blendedset, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(objs))
+
+	v, _ := version.New("8.1.0")
+	e := New(cfg, v)
+	for _, obj := range objs {
+		assert.Nil(t, e.Add(obj))
+	}
+
+	cmds, err := e.SetCommands()
+	assert.Nil(t, err)
+	out := string(cmds)
+	assert.Contains(t, out, "set service web protocol tcp port 80,443\n")
+	assert.Contains(t, out, "set service web tag [ pa-controller pa-cluster-k8s-a ]\n")
+	assert.Contains(t, out, "set rulebase security rules allow-web from untrust\n")
+	assert.Contains(t, out, "set rulebase security rules allow-web destination [ 10.0.0.1 10.0.0.2 ]\n")
+	assert.Contains(t, out, "set rulebase security rules allow-web description \"allow the web\"\n")
+	assert.Contains(t, out, "set rulebase security rules allow-web action allow\n")
+	assert.NotContains(t, out, "other")
+	assert.NotContains(t, out, "pending")
+	assert.True(t, strings.Index(out, "rules z-first") < strings.Index(out, "rules allow-web"))
+	assert.True(t, strings.Index(out, "rules allow-web") < strings.Index(out, "rules deny-all"))
+
+	b, err := e.XML()
+	assert.Nil(t, err)
+	x := string(b)
+	assert.Contains(t, x, "<config>\n  <devices>\n    <entry name=\"localhost.localdomain\">\n      <vsys>\n        <entry name=\"vsys1\">\n")
+	assert.Contains(t, x, "          <service>\n            <entry name=\"web\">\n")
+	assert.Contains(t, x, "          <rulebase>\n            <security>\n              <rules>\n                <entry name=\"z-first\">\n")
+	assert.Contains(t, x, "<member>10.0.0.2</member>")
+}
+
+func TestExportPanorama(t *testing.T) {
+	v, _ := version.New("8.1.0")
+	cfg := &config.Config{Panorama: true, DeviceGroup: "dg 1", Rulebase: "post-rulebase", SharedObjects: true}
+	e := New(cfg, v)
+	assert.Nil(t, e.Add(&blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec:       blendedv1.ServiceSpec{Protocol: "udp", DestinationPort: "53"},
+	}))
+	assert.Nil(t, e.Add(&blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "default"},
+		Spec: blendedv1.NATSpec{
+			SourceZones:     []string{"untrust"},
+			DestinationZone: "untrust",
+			DatAddress:      "10.0.0.53",
+			DatType:         blendedv1.NATDatStatic,
+		},
+	}))
+
+	cmds, err := e.SetCommands()
+	assert.Nil(t, err)
+	out := string(cmds)
+	assert.Contains(t, out, "set shared service web protocol udp port 53\n")
+	assert.Contains(t, out, "set device-group \"dg 1\" post-rulebase nat rules dns to untrust\n")
+	assert.Contains(t, out, "set device-group \"dg 1\" post-rulebase nat rules dns destination-translation translated-address 10.0.0.53\n")
+
+	b, err := e.XML()
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "<config>\n  <shared>\n    <service>\n")
+	assert.NotContains(t, out, "description")
+	assert.Contains(t, string(b), "<device-group>\n        <entry name=\"dg 1\">\n          <post-rulebase>\n")
+}
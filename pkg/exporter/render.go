@@ -0,0 +1,217 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// node is an element of the XML config.
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []node     `xml:",any"`
+}
+
+func (n *node) name() string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == "name" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// SetCommands returns the set commands of the CLI in configuration mode. The commands of
+// a firewall without the vsys in the config are for the single vsys mode.
+func (e *Exporter) SetCommands() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, ed := range e.rec.edits {
+		b, err := xml.Marshal(ed.element)
+		if err != nil {
+			return nil, err
+		}
+
+		var n node
+		if err := xml.Unmarshal(b, &n); err != nil {
+			return nil, err
+		}
+		writeSetCommands(&buf, e.setPrefix(ed.xpath[:len(ed.xpath)-1]), n)
+	}
+	return buf.Bytes(), nil
+}
+
+// setPrefix converts the xpath of a container into the words of the set commands.
+func (e *Exporter) setPrefix(xpath []string) []string {
+	words := []string{}
+	for i := 0; i < len(xpath); i++ {
+		switch {
+		case i == 0 && xpath[i] == "config":
+		case xpath[i] == "devices" && i+1 < len(xpath):
+			// The local device is implied by the CLI.
+			i++
+		case xpath[i] == "vsys" && i+1 < len(xpath) && !e.cfg.Panorama && e.cfg.Vsys == "":
+			i++
+		default:
+			if name, ok := entryName(xpath[i]); ok {
+				words = append(words, quote(name))
+				continue
+			}
+			words = append(words, xpath[i])
+		}
+	}
+	return words
+}
+
+func writeSetCommands(buf *bytes.Buffer, prefix []string, n node) {
+	word := n.XMLName.Local
+	if word == "entry" {
+		word = quote(n.name())
+	}
+
+	words := append(append([]string{}, prefix...), word)
+	switch {
+	case len(n.Nodes) == 0:
+		// The empty elements are the defaults, such as an empty description.
+		text := strings.TrimSpace(n.Text)
+		if text == "" {
+			return
+		}
+		words = append(words, quote(text))
+	case isMemberList(n.Nodes):
+		members := []string{}
+		for _, m := range n.Nodes {
+			members = append(members, quote(strings.TrimSpace(m.Text)))
+		}
+
+		if len(members) == 1 {
+			words = append(words, members[0])
+		} else {
+			words = append(words, "[", strings.Join(members, " "), "]")
+		}
+	default:
+		for _, c := range n.Nodes {
+			writeSetCommands(buf, words, c)
+		}
+		return
+	}
+
+	buf.WriteString("set ")
+	buf.WriteString(strings.Join(words, " "))
+	buf.WriteString("\n")
+}
+
+func isMemberList(nodes []node) bool {
+	for _, n := range nodes {
+		if n.XMLName.Local != "member" {
+			return false
+		}
+	}
+	return true
+}
+
+// quote quotes a word of the set commands when it has spaces or special characters.
+func quote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\"'|;<>[]{}#") {
+		return word
+	}
+	return `"` + strings.Replace(word, `"`, `\"`, -1) + `"`
+}
+
+// entryName returns the name of an entry in the xpath, such as entry[@name='vsys1'].
+func entryName(word string) (string, bool) {
+	const prefix, suffix = "entry[@name='", "']"
+	if strings.HasPrefix(word, prefix) && strings.HasSuffix(word, suffix) {
+		return word[len(prefix) : len(word)-len(suffix)], true
+	}
+	return "", false
+}
+
+// xmlTree is the XML config of the entries, where the containers are keyed by their xpath.
+type xmlTree struct {
+	word     string
+	children []*xmlTree
+	entries  []interface{}
+}
+
+func (t *xmlTree) child(word string) *xmlTree {
+	for _, c := range t.children {
+		if c.word == word {
+			return c
+		}
+	}
+
+	c := &xmlTree{word: word}
+	t.children = append(t.children, c)
+	return c
+}
+
+// XML returns the XML config of the entries from the root of the config, which can be
+// loaded partially or diffed with the running config.
+func (e *Exporter) XML() ([]byte, error) {
+	root := &xmlTree{}
+	for _, ed := range e.rec.edits {
+		t := root
+		for _, word := range ed.xpath[:len(ed.xpath)-1] {
+			t = t.child(word)
+		}
+		t.entries = append(t.entries, ed.element)
+	}
+
+	var buf bytes.Buffer
+	for _, c := range root.children {
+		if err := writeXML(&buf, c, ""); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func writeXML(buf *bytes.Buffer, t *xmlTree, indent string) error {
+	tag := t.word
+	buf.WriteString(indent)
+	if name, ok := entryName(t.word); ok {
+		tag = "entry"
+		buf.WriteString(`<entry name="`)
+		xml.EscapeText(buf, []byte(name))
+		buf.WriteString(`">`)
+	} else {
+		buf.WriteString("<" + tag + ">")
+	}
+	buf.WriteString("\n")
+
+	for _, c := range t.children {
+		if err := writeXML(buf, c, indent+"  "); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range t.entries {
+		b, err := xml.MarshalIndent(entry, indent+"  ", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(indent + "</" + tag + ">\n")
+	return nil
+}
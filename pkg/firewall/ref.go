@@ -0,0 +1,31 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ref returns the name of the Firewall that a resource is synced to. The ref field
+// takes precedence over the label, and an empty name means the default firewall.
+func Ref(meta metav1.ObjectMeta, ref string) string {
+	if ref != "" {
+		return ref
+	}
+	return meta.Labels[constants.FirewallKey]
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	scheme "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// AddressesGetter has a method to return a AddressInterface.
+// A group's client should implement this interface.
+type AddressesGetter interface {
+	Addresses() AddressInterface
+}
+
+// AddressInterface has methods to work with Address resources.
+type AddressInterface interface {
+	Create(*v1.Address) (*v1.Address, error)
+	Update(*v1.Address) (*v1.Address, error)
+	UpdateStatus(*v1.Address) (*v1.Address, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.Address, error)
+	List(opts metav1.ListOptions) (*v1.AddressList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.Address, err error)
+	AddressExpansion
+}
+
+// addresses implements AddressInterface
+type addresses struct {
+	client rest.Interface
+}
+
+// newAddresses returns a Addresses
+func newAddresses(c *InwinstackV1Client) *addresses {
+	return &addresses{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the address, and returns the corresponding address object, and an error if there is any.
+func (c *addresses) Get(name string, options metav1.GetOptions) (result *v1.Address, err error) {
+	result = &v1.Address{}
+	err = c.client.Get().
+		Resource("addresses").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Addresses that match those selectors.
+func (c *addresses) List(opts metav1.ListOptions) (result *v1.AddressList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.AddressList{}
+	err = c.client.Get().
+		Resource("addresses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested addresses.
+func (c *addresses) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("addresses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a address and creates it.  Returns the server's representation of the address, and an error, if there is any.
+func (c *addresses) Create(address *v1.Address) (result *v1.Address, err error) {
+	result = &v1.Address{}
+	err = c.client.Post().
+		Resource("addresses").
+		Body(address).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a address and updates it. Returns the server's representation of the address, and an error, if there is any.
+func (c *addresses) Update(address *v1.Address) (result *v1.Address, err error) {
+	result = &v1.Address{}
+	err = c.client.Put().
+		Resource("addresses").
+		Name(address.Name).
+		Body(address).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *addresses) UpdateStatus(address *v1.Address) (result *v1.Address, err error) {
+	result = &v1.Address{}
+	err = c.client.Put().
+		Resource("addresses").
+		Name(address.Name).
+		SubResource("status").
+		Body(address).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the address and deletes it. Returns an error if one occurs.
+func (c *addresses) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("addresses").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *addresses) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("addresses").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched address.
+func (c *addresses) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.Address, err error) {
+	result = &v1.Address{}
+	err = c.client.Patch(pt).
+		Resource("addresses").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	scheme "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// AddressGroupsGetter has a method to return a AddressGroupInterface.
+// A group's client should implement this interface.
+type AddressGroupsGetter interface {
+	AddressGroups() AddressGroupInterface
+}
+
+// AddressGroupInterface has methods to work with AddressGroup resources.
+type AddressGroupInterface interface {
+	Create(*v1.AddressGroup) (*v1.AddressGroup, error)
+	Update(*v1.AddressGroup) (*v1.AddressGroup, error)
+	UpdateStatus(*v1.AddressGroup) (*v1.AddressGroup, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.AddressGroup, error)
+	List(opts metav1.ListOptions) (*v1.AddressGroupList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AddressGroup, err error)
+	AddressGroupExpansion
+}
+
+// addressGroups implements AddressGroupInterface
+type addressGroups struct {
+	client rest.Interface
+}
+
+// newAddressGroups returns a AddressGroups
+func newAddressGroups(c *InwinstackV1Client) *addressGroups {
+	return &addressGroups{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the addressGroup, and returns the corresponding addressGroup object, and an error if there is any.
+func (c *addressGroups) Get(name string, options metav1.GetOptions) (result *v1.AddressGroup, err error) {
+	result = &v1.AddressGroup{}
+	err = c.client.Get().
+		Resource("addressgroups").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of AddressGroups that match those selectors.
+func (c *addressGroups) List(opts metav1.ListOptions) (result *v1.AddressGroupList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.AddressGroupList{}
+	err = c.client.Get().
+		Resource("addressgroups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested addressGroups.
+func (c *addressGroups) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("addressgroups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a addressGroup and creates it.  Returns the server's representation of the addressGroup, and an error, if there is any.
+func (c *addressGroups) Create(addressGroup *v1.AddressGroup) (result *v1.AddressGroup, err error) {
+	result = &v1.AddressGroup{}
+	err = c.client.Post().
+		Resource("addressgroups").
+		Body(addressGroup).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a addressGroup and updates it. Returns the server's representation of the addressGroup, and an error, if there is any.
+func (c *addressGroups) Update(addressGroup *v1.AddressGroup) (result *v1.AddressGroup, err error) {
+	result = &v1.AddressGroup{}
+	err = c.client.Put().
+		Resource("addressgroups").
+		Name(addressGroup.Name).
+		Body(addressGroup).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *addressGroups) UpdateStatus(addressGroup *v1.AddressGroup) (result *v1.AddressGroup, err error) {
+	result = &v1.AddressGroup{}
+	err = c.client.Put().
+		Resource("addressgroups").
+		Name(addressGroup.Name).
+		SubResource("status").
+		Body(addressGroup).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the addressGroup and deletes it. Returns an error if one occurs.
+func (c *addressGroups) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("addressgroups").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *addressGroups) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("addressgroups").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched addressGroup.
+func (c *addressGroups) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AddressGroup, err error) {
+	result = &v1.AddressGroup{}
+	err = c.client.Patch(pt).
+		Resource("addressgroups").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
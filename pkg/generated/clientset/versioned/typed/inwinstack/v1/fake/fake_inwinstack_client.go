@@ -0,0 +1,63 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/typed/inwinstack/v1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeInwinstackV1 struct {
+	*testing.Fake
+}
+
+func (c *FakeInwinstackV1) Addresses() v1.AddressInterface {
+	return &FakeAddresses{c}
+}
+
+func (c *FakeInwinstackV1) AddressGroups() v1.AddressGroupInterface {
+	return &FakeAddressGroups{c}
+}
+
+func (c *FakeInwinstackV1) ExternalDynamicLists() v1.ExternalDynamicListInterface {
+	return &FakeExternalDynamicLists{c}
+}
+
+func (c *FakeInwinstackV1) Firewalls() v1.FirewallInterface {
+	return &FakeFirewalls{c}
+}
+
+func (c *FakeInwinstackV1) FirewallPolicies() v1.FirewallPolicyInterface {
+	return &FakeFirewallPolicies{c}
+}
+
+func (c *FakeInwinstackV1) SecurityDefaultses() v1.SecurityDefaultsInterface {
+	return &FakeSecurityDefaultses{c}
+}
+
+func (c *FakeInwinstackV1) ServiceGroups() v1.ServiceGroupInterface {
+	return &FakeServiceGroups{c}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeInwinstackV1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,32 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+type AddressExpansion interface{}
+
+type AddressGroupExpansion interface{}
+
+type ExternalDynamicListExpansion interface{}
+
+type FirewallExpansion interface{}
+
+type FirewallPolicyExpansion interface{}
+
+type SecurityDefaultsExpansion interface{}
+
+type ServiceGroupExpansion interface{}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	scheme "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SecurityDefaultsesGetter has a method to return a SecurityDefaultsInterface.
+// A group's client should implement this interface.
+type SecurityDefaultsesGetter interface {
+	SecurityDefaultses() SecurityDefaultsInterface
+}
+
+// SecurityDefaultsInterface has methods to work with SecurityDefaults resources.
+type SecurityDefaultsInterface interface {
+	Create(*v1.SecurityDefaults) (*v1.SecurityDefaults, error)
+	Update(*v1.SecurityDefaults) (*v1.SecurityDefaults, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.SecurityDefaults, error)
+	List(opts metav1.ListOptions) (*v1.SecurityDefaultsList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.SecurityDefaults, err error)
+	SecurityDefaultsExpansion
+}
+
+// securityDefaultses implements SecurityDefaultsInterface
+type securityDefaultses struct {
+	client rest.Interface
+}
+
+// newSecurityDefaultses returns a SecurityDefaultses
+func newSecurityDefaultses(c *InwinstackV1Client) *securityDefaultses {
+	return &securityDefaultses{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the securityDefaults, and returns the corresponding securityDefaults object, and an error if there is any.
+func (c *securityDefaultses) Get(name string, options metav1.GetOptions) (result *v1.SecurityDefaults, err error) {
+	result = &v1.SecurityDefaults{}
+	err = c.client.Get().
+		Resource("securitydefaults").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SecurityDefaultses that match those selectors.
+func (c *securityDefaultses) List(opts metav1.ListOptions) (result *v1.SecurityDefaultsList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.SecurityDefaultsList{}
+	err = c.client.Get().
+		Resource("securitydefaults").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested securityDefaultses.
+func (c *securityDefaultses) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("securitydefaults").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a securityDefaults and creates it.  Returns the server's representation of the securityDefaults, and an error, if there is any.
+func (c *securityDefaultses) Create(securityDefaults *v1.SecurityDefaults) (result *v1.SecurityDefaults, err error) {
+	result = &v1.SecurityDefaults{}
+	err = c.client.Post().
+		Resource("securitydefaults").
+		Body(securityDefaults).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a securityDefaults and updates it. Returns the server's representation of the securityDefaults, and an error, if there is any.
+func (c *securityDefaultses) Update(securityDefaults *v1.SecurityDefaults) (result *v1.SecurityDefaults, err error) {
+	result = &v1.SecurityDefaults{}
+	err = c.client.Put().
+		Resource("securitydefaults").
+		Name(securityDefaults.Name).
+		Body(securityDefaults).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the securityDefaults and deletes it. Returns an error if one occurs.
+func (c *securityDefaultses) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("securitydefaults").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *securityDefaultses) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("securitydefaults").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched securityDefaults.
+func (c *securityDefaultses) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.SecurityDefaults, err error) {
+	result = &v1.SecurityDefaults{}
+	err = c.client.Patch(pt).
+		Resource("securitydefaults").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	inwinstackv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	versioned "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions/internalinterfaces"
+	v1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// AddressGroupInformer provides access to a shared informer and lister for
+// AddressGroups.
+type AddressGroupInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.AddressGroupLister
+}
+
+type addressGroupInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewAddressGroupInformer constructs a new informer for AddressGroup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewAddressGroupInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredAddressGroupInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredAddressGroupInformer constructs a new informer for AddressGroup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredAddressGroupInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.InwinstackV1().AddressGroups().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.InwinstackV1().AddressGroups().Watch(options)
+			},
+		},
+		&inwinstackv1.AddressGroup{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *addressGroupInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredAddressGroupInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *addressGroupInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&inwinstackv1.AddressGroup{}, f.defaultInformer)
+}
+
+func (f *addressGroupInformer) Lister() v1.AddressGroupLister {
+	return v1.NewAddressGroupLister(f.Informer().GetIndexer())
+}
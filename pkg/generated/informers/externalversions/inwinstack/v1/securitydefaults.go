@@ -0,0 +1,87 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	inwinstackv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	versioned "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions/internalinterfaces"
+	v1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// SecurityDefaultsInformer provides access to a shared informer and lister for
+// SecurityDefaultses.
+type SecurityDefaultsInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.SecurityDefaultsLister
+}
+
+type securityDefaultsInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewSecurityDefaultsInformer constructs a new informer for SecurityDefaults type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewSecurityDefaultsInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredSecurityDefaultsInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredSecurityDefaultsInformer constructs a new informer for SecurityDefaults type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredSecurityDefaultsInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.InwinstackV1().SecurityDefaultses().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.InwinstackV1().SecurityDefaultses().Watch(options)
+			},
+		},
+		&inwinstackv1.SecurityDefaults{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *securityDefaultsInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredSecurityDefaultsInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *securityDefaultsInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&inwinstackv1.SecurityDefaults{}, f.defaultInformer)
+}
+
+func (f *securityDefaultsInformer) Lister() v1.SecurityDefaultsLister {
+	return v1.NewSecurityDefaultsLister(f.Informer().GetIndexer())
+}
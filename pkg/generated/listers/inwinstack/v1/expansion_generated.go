@@ -0,0 +1,46 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+// AddressListerExpansion allows custom methods to be added to
+// AddressLister.
+type AddressListerExpansion interface{}
+
+// AddressGroupListerExpansion allows custom methods to be added to
+// AddressGroupLister.
+type AddressGroupListerExpansion interface{}
+
+// ExternalDynamicListListerExpansion allows custom methods to be added to
+// ExternalDynamicListLister.
+type ExternalDynamicListListerExpansion interface{}
+
+// FirewallListerExpansion allows custom methods to be added to
+// FirewallLister.
+type FirewallListerExpansion interface{}
+
+// FirewallPolicyListerExpansion allows custom methods to be added to
+// FirewallPolicyLister.
+type FirewallPolicyListerExpansion interface{}
+
+// SecurityDefaultsListerExpansion allows custom methods to be added to
+// SecurityDefaultsLister.
+type SecurityDefaultsListerExpansion interface{}
+
+// ServiceGroupListerExpansion allows custom methods to be added to
+// ServiceGroupLister.
+type ServiceGroupListerExpansion interface{}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FirewallPolicyLister helps list FirewallPolicies.
+type FirewallPolicyLister interface {
+	// List lists all FirewallPolicies in the indexer.
+	List(selector labels.Selector) (ret []*v1.FirewallPolicy, err error)
+	// Get retrieves the FirewallPolicy from the index for a given name.
+	Get(name string) (*v1.FirewallPolicy, error)
+	FirewallPolicyListerExpansion
+}
+
+// firewallPolicyLister implements the FirewallPolicyLister interface.
+type firewallPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewFirewallPolicyLister returns a new FirewallPolicyLister.
+func NewFirewallPolicyLister(indexer cache.Indexer) FirewallPolicyLister {
+	return &firewallPolicyLister{indexer: indexer}
+}
+
+// List lists all FirewallPolicies in the indexer.
+func (s *firewallPolicyLister) List(selector labels.Selector) (ret []*v1.FirewallPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.FirewallPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the FirewallPolicy from the index for a given name.
+func (s *firewallPolicyLister) Get(name string) (*v1.FirewallPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("firewallpolicy"), name)
+	}
+	return obj.(*v1.FirewallPolicy), nil
+}
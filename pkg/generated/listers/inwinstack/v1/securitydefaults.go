@@ -0,0 +1,64 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecurityDefaultsLister helps list SecurityDefaultses.
+type SecurityDefaultsLister interface {
+	// List lists all SecurityDefaultses in the indexer.
+	List(selector labels.Selector) (ret []*v1.SecurityDefaults, err error)
+	// Get retrieves the SecurityDefaults from the index for a given name.
+	Get(name string) (*v1.SecurityDefaults, error)
+	SecurityDefaultsListerExpansion
+}
+
+// securityDefaultsLister implements the SecurityDefaultsLister interface.
+type securityDefaultsLister struct {
+	indexer cache.Indexer
+}
+
+// NewSecurityDefaultsLister returns a new SecurityDefaultsLister.
+func NewSecurityDefaultsLister(indexer cache.Indexer) SecurityDefaultsLister {
+	return &securityDefaultsLister{indexer: indexer}
+}
+
+// List lists all SecurityDefaultses in the indexer.
+func (s *securityDefaultsLister) List(selector labels.Selector) (ret []*v1.SecurityDefaults, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.SecurityDefaults))
+	})
+	return ret, err
+}
+
+// Get retrieves the SecurityDefaults from the index for a given name.
+func (s *securityDefaultsLister) Get(name string) (*v1.SecurityDefaults, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("securitydefaults"), name)
+	}
+	return obj.(*v1.SecurityDefaults), nil
+}
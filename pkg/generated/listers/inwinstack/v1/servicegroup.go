@@ -0,0 +1,64 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ServiceGroupLister helps list ServiceGroups.
+type ServiceGroupLister interface {
+	// List lists all ServiceGroups in the indexer.
+	List(selector labels.Selector) (ret []*v1.ServiceGroup, err error)
+	// Get retrieves the ServiceGroup from the index for a given name.
+	Get(name string) (*v1.ServiceGroup, error)
+	ServiceGroupListerExpansion
+}
+
+// serviceGroupLister implements the ServiceGroupLister interface.
+type serviceGroupLister struct {
+	indexer cache.Indexer
+}
+
+// NewServiceGroupLister returns a new ServiceGroupLister.
+func NewServiceGroupLister(indexer cache.Indexer) ServiceGroupLister {
+	return &serviceGroupLister{indexer: indexer}
+}
+
+// List lists all ServiceGroups in the indexer.
+func (s *serviceGroupLister) List(selector labels.Selector) (ret []*v1.ServiceGroup, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ServiceGroup))
+	})
+	return ret, err
+}
+
+// Get retrieves the ServiceGroup from the index for a given name.
+func (s *serviceGroupLister) Get(name string) (*v1.ServiceGroup, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("servicegroup"), name)
+	}
+	return obj.(*v1.ServiceGroup), nil
+}
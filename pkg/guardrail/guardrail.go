@@ -0,0 +1,187 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package guardrail enforces the FirewallPolicies, which constrain the zones, the destinations,
+// the services and the actions that the Securities and NATs of the namespaces may use.
+package guardrail
+
+import (
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	inwinlisterv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/thoas/go-funk"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/informers"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Phase is the phase of the Securities and NATs violating the firewall policies of their
+// namespaces.
+const Phase = "PolicyViolation"
+
+// Guard checks the resources against the firewall policies of their namespaces. A nil guard
+// allows everything.
+type Guard struct {
+	nsLister       listerv1.NamespaceLister
+	policyLister   inwinlisterv1.FirewallPolicyLister
+	addrLister     inwinlisterv1.AddressLister
+	policyInformer cache.SharedIndexInformer
+	synced         []cache.InformerSynced
+}
+
+// NewGuard creates an instance of the guard
+func NewGuard(kubeInformer informers.SharedInformerFactory, inwinInformer inwininformers.SharedInformerFactory) *Guard {
+	nsInformer := kubeInformer.Core().V1().Namespaces()
+	policyInformer := inwinInformer.Inwinstack().V1().FirewallPolicies()
+	addrInformer := inwinInformer.Inwinstack().V1().Addresses()
+	return &Guard{
+		nsLister:       nsInformer.Lister(),
+		policyLister:   policyInformer.Lister(),
+		addrLister:     addrInformer.Lister(),
+		policyInformer: policyInformer.Informer(),
+		synced: []cache.InformerSynced{
+			nsInformer.Informer().HasSynced,
+			policyInformer.Informer().HasSynced,
+			addrInformer.Informer().HasSynced,
+		},
+	}
+}
+
+// Synced returns the functions to check whether the caches of the guard are synced.
+func (g *Guard) Synced() []cache.InformerSynced {
+	if g == nil {
+		return nil
+	}
+
+	return g.synced
+}
+
+// OnPolicyChange calls the handler with the namespaces selected by a firewall policy before or
+// after it's changed, so all the resources of the namespaces are checked again, including the
+// ones pushed before the policy.
+func (g *Guard) OnPolicyChange(handler func(namespaces []string)) {
+	if g == nil {
+		return
+	}
+
+	call := func(objs ...interface{}) {
+		namespaces := []string{}
+		for _, obj := range objs {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			p, ok := obj.(*inwinv1.FirewallPolicy)
+			if !ok {
+				continue
+			}
+
+			selected, err := g.namespaces(p)
+			if err != nil {
+				utilruntime.HandleError(err)
+				continue
+			}
+			namespaces = append(namespaces, selected...)
+		}
+
+		if len(namespaces) != 0 {
+			handler(funk.UniqString(namespaces))
+		}
+	}
+	g.policyInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { call(obj) },
+		UpdateFunc: func(old, new interface{}) { call(old, new) },
+		DeleteFunc: func(obj interface{}) { call(obj) },
+	})
+}
+
+// Security returns the violations of a Security against the firewall policies of its namespace.
+func (g *Guard) Security(namespace string, spec blendedv1.SecuritySpec) (field.ErrorList, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	policies, err := g.policies(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return CheckSecurity(policies, spec, g.address), nil
+}
+
+// NAT returns the violations of a NAT against the firewall policies of its namespace.
+func (g *Guard) NAT(namespace string, spec blendedv1.NATSpec) (field.ErrorList, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	policies, err := g.policies(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return CheckNAT(policies, spec, g.address), nil
+}
+
+func (g *Guard) policies(namespace string) ([]*inwinv1.FirewallPolicy, error) {
+	ns, err := g.nsLister.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := g.policyLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return SelectPolicies(policies, ns)
+}
+
+// namespaces returns the names of the namespaces selected by a firewall policy.
+func (g *Guard) namespaces(p *inwinv1.FirewallPolicy) ([]string, error) {
+	nss, err := g.nsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, ns := range nss {
+		selected, err := SelectPolicies([]*inwinv1.FirewallPolicy{p}, ns)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(selected) != 0 {
+			names = append(names, ns.Name)
+		}
+	}
+	return names, nil
+}
+
+// address resolves the Address resources of IPs and IP ranges.
+func (g *Guard) address(name string) (string, bool) {
+	addr, err := g.addrLister.Get(name)
+	if err != nil {
+		return "", false
+	}
+
+	switch addr.Spec.Type {
+	case inwinv1.AddressIPNetmask, inwinv1.AddressIPRange:
+		return addr.Spec.Value, true
+	}
+	return "", false
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/thoas/go-funk"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const anyName = "any"
+
+// AddressFunc returns the IP, CIDR or IP range of an address object, and false when the object
+// isn't known.
+type AddressFunc func(name string) (string, bool)
+
+// SelectPolicies returns the firewall policies selecting a namespace.
+func SelectPolicies(policies []*inwinv1.FirewallPolicy, ns *v1.Namespace) ([]*inwinv1.FirewallPolicy, error) {
+	selected := []*inwinv1.FirewallPolicy{}
+	for _, p := range policies {
+		selector := labels.Everything()
+		if p.Spec.NamespaceSelector != nil {
+			s, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, err
+			}
+			selector = s
+		}
+
+		if selector.Matches(labels.Set(ns.Labels)) {
+			selected = append(selected, p)
+		}
+	}
+	return selected, nil
+}
+
+// CheckSecurity returns the violations of a Security against the firewall policies.
+func CheckSecurity(policies []*inwinv1.FirewallPolicy, spec blendedv1.SecuritySpec, addr AddressFunc) field.ErrorList {
+	path := field.NewPath("spec")
+	errs := field.ErrorList{}
+	for _, p := range policies {
+		errs = append(errs, checkZones(p, path.Child("sourceZones"), spec.SourceZones)...)
+		errs = append(errs, checkZones(p, path.Child("destinationZones"), spec.DestinationZones)...)
+		errs = append(errs, checkSources(p, path.Child("sourceAddresses"), spec.SourceAddresses, spec.NegateSource)...)
+		errs = append(errs, checkDestinations(p, path.Child("destinationAddresses"), spec.DestinationAddresses, spec.NegateDestination, addr)...)
+		errs = append(errs, checkServices(p, path.Child("services"), spec.Services)...)
+		if len(p.Spec.AllowedActions) != 0 && !funk.ContainsString(p.Spec.AllowedActions, spec.Action) {
+			errs = append(errs, forbidden(p, path.Child("action"), "action", spec.Action))
+		}
+	}
+	return errs
+}
+
+// CheckNAT returns the violations of a NAT against the firewall policies.
+func CheckNAT(policies []*inwinv1.FirewallPolicy, spec blendedv1.NATSpec, addr AddressFunc) field.ErrorList {
+	path := field.NewPath("spec")
+	errs := field.ErrorList{}
+	for _, p := range policies {
+		errs = append(errs, checkZones(p, path.Child("sourceZones"), spec.SourceZones)...)
+		errs = append(errs, checkZone(p, path.Child("destinationZone"), spec.DestinationZone)...)
+		errs = append(errs, checkSources(p, path.Child("sourceAddresses"), spec.SourceAddresses, false)...)
+		errs = append(errs, checkDestinations(p, path.Child("destinationAddresses"), spec.DestinationAddresses, false, addr)...)
+		if spec.DatAddress != "" {
+			errs = append(errs, checkDestinations(p, path.Child("datAddress"), []string{spec.DatAddress}, false, addr)...)
+		}
+
+		service := []string{}
+		if spec.Service != "" {
+			service = append(service, spec.Service)
+		}
+		errs = append(errs, checkServices(p, path.Child("service"), service)...)
+	}
+	return errs
+}
+
+// checkZones checks the zones are allowed, and the empty zones mean any, which is only allowed
+// when the allowed zones have it.
+func checkZones(p *inwinv1.FirewallPolicy, path *field.Path, zones []string) field.ErrorList {
+	if len(zones) == 0 {
+		return checkZone(p, path, anyName)
+	}
+
+	errs := field.ErrorList{}
+	for i, z := range zones {
+		errs = append(errs, checkZone(p, path.Index(i), z)...)
+	}
+	return errs
+}
+
+// checkZone checks the zone is allowed, and the empty zone means any.
+func checkZone(p *inwinv1.FirewallPolicy, path *field.Path, zone string) field.ErrorList {
+	if zone == "" {
+		zone = anyName
+	}
+	if len(p.Spec.AllowedZones) == 0 || funk.ContainsString(p.Spec.AllowedZones, zone) {
+		return nil
+	}
+	return field.ErrorList{forbidden(p, path, "zone", zone)}
+}
+
+// checkSources checks any in the source addresses, and the empty addresses mean any. The negated
+// addresses match everything but them, so they're checked as any.
+func checkSources(p *inwinv1.FirewallPolicy, path *field.Path, addrs []string, negate bool) field.ErrorList {
+	errs := field.ErrorList{}
+	if !p.Spec.ForbidAnySource {
+		return errs
+	}
+
+	if len(addrs) == 0 || negate {
+		return append(errs, forbidden(p, path, "source", anyName))
+	}
+
+	for i, a := range addrs {
+		if a == anyName {
+			errs = append(errs, forbidden(p, path.Index(i), "source", a))
+		}
+	}
+	return errs
+}
+
+// checkDestinations checks the destination addresses are within the allowed networks. The names
+// are resolved by the address function, and the empty addresses mean any. The negated addresses
+// match everything but them, so they're checked as any.
+func checkDestinations(p *inwinv1.FirewallPolicy, path *field.Path, addrs []string, negate bool, addr AddressFunc) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(p.Spec.AllowedDestinationCIDRs) == 0 {
+		return errs
+	}
+
+	if len(addrs) == 0 || negate {
+		return append(errs, forbidden(p, path, "destination", anyName))
+	}
+
+	for i, a := range addrs {
+		value := a
+		if !isIPLike(a) && addr != nil {
+			if v, ok := addr(a); ok {
+				value = v
+			}
+		}
+
+		if !within(value, p.Spec.AllowedDestinationCIDRs) {
+			errs = append(errs, forbidden(p, path.Index(i), "destination", a))
+		}
+	}
+	return errs
+}
+
+// checkServices checks the services, and the empty services mean any.
+func checkServices(p *inwinv1.FirewallPolicy, path *field.Path, services []string) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(p.Spec.AllowedServices) == 0 {
+		return errs
+	}
+
+	if len(services) == 0 {
+		if !funk.ContainsString(p.Spec.AllowedServices, anyName) {
+			errs = append(errs, forbidden(p, path, "service", anyName))
+		}
+		return errs
+	}
+
+	for i, s := range services {
+		if !funk.ContainsString(p.Spec.AllowedServices, s) {
+			errs = append(errs, forbidden(p, path.Index(i), "service", s))
+		}
+	}
+	return errs
+}
+
+func forbidden(p *inwinv1.FirewallPolicy, path *field.Path, what, value string) *field.Error {
+	return field.Forbidden(path, fmt.Sprintf("%s '%s' isn't allowed by the FirewallPolicy '%s'", what, value, p.Name))
+}
+
+// within tells whether an IP, a CIDR or an IP range is within one of the networks.
+func within(value string, cidrs []string) bool {
+	first, last := bounds(value)
+	if first == nil || last == nil {
+		return false
+	}
+
+	for _, c := range cidrs {
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			continue
+		}
+
+		if network.Contains(first) && network.Contains(last) {
+			return true
+		}
+	}
+	return false
+}
+
+// bounds returns the first and last IPs of an IP, a CIDR or an IP range.
+func bounds(value string) (net.IP, net.IP) {
+	switch {
+	case strings.Contains(value, "-"):
+		parts := strings.Split(value, "-")
+		if len(parts) != 2 {
+			return nil, nil
+		}
+
+		first, last := normalize(net.ParseIP(parts[0])), normalize(net.ParseIP(parts[1]))
+		if first == nil || last == nil || len(first) != len(last) || bytes.Compare(first, last) > 0 {
+			return nil, nil
+		}
+		return first, last
+	case strings.Contains(value, "/"):
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, nil
+		}
+
+		last := make(net.IP, len(network.IP))
+		for i := range network.IP {
+			last[i] = network.IP[i] | ^network.Mask[i]
+		}
+		return network.IP, last
+	}
+
+	ip := normalize(net.ParseIP(value))
+	return ip, ip
+}
+
+func normalize(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip
+}
+
+// isIPLike tells whether a value is meant to be an IP rather than the name of an address object.
+func isIPLike(addr string) bool {
+	if strings.Contains(addr, ":") {
+		return true
+	}
+	return strings.Contains(addr, ".") && strings.Trim(addr, "0123456789./-") == ""
+}
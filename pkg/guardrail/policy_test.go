@@ -0,0 +1,170 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func fields(errs field.ErrorList) []string {
+	fs := []string{}
+	for _, e := range errs {
+		fs = append(fs, e.Field)
+	}
+	return fs
+}
+
+func TestSelectPolicies(t *testing.T) {
+	all := &inwinv1.FirewallPolicy{ObjectMeta: metav1.ObjectMeta{Name: "org"}}
+	tenants := &inwinv1.FirewallPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+		Spec: inwinv1.FirewallPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		},
+	}
+	policies := []*inwinv1.FirewallPolicy{all, tenants}
+
+	selected, err := SelectPolicies(policies, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "true"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, policies, selected)
+
+	selected, err = SelectPolicies(policies, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*inwinv1.FirewallPolicy{all}, selected)
+}
+
+func TestCheckSecurity(t *testing.T) {
+	policies := []*inwinv1.FirewallPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+		Spec: inwinv1.FirewallPolicySpec{
+			AllowedZones:            []string{"trust", "dmz"},
+			AllowedDestinationCIDRs: []string{"10.10.0.0/16"},
+			AllowedServices:         []string{"k8s-tcp80", "k8s-tcp443"},
+			AllowedActions:          []string{"allow"},
+			ForbidAnySource:         true,
+		},
+	}}
+	addrs := map[string]string{"web-vip": "10.10.1.10", "db-range": "10.10.2.1-10.11.0.1"}
+	addr := func(name string) (string, bool) {
+		v, ok := addrs[name]
+		return v, ok
+	}
+
+	spec := blendedv1.SecuritySpec{
+		SourceZones:          []string{"trust"},
+		DestinationZones:     []string{"dmz"},
+		SourceAddresses:      []string{"10.20.0.0/24"},
+		DestinationAddresses: []string{"10.10.1.0/24", "web-vip", "10.10.3.1-10.10.3.9"},
+		Services:             []string{"k8s-tcp80"},
+		Action:               blendedv1.SecurityAllow,
+	}
+	assert.Empty(t, CheckSecurity(policies, spec, addr))
+
+	spec = blendedv1.SecuritySpec{
+		SourceZones:          []string{"trust", "untrust"},
+		DestinationZones:     []string{"dmz"},
+		SourceAddresses:      []string{"any"},
+		DestinationAddresses: []string{"10.0.0.0/8", "db-range", "unknown", "10.10.0.1"},
+		Services:             []string{"any"},
+		Action:               "deny",
+	}
+	errs := CheckSecurity(policies, spec, addr)
+	assert.Equal(t, []string{
+		"spec.sourceZones[1]",
+		"spec.sourceAddresses[0]",
+		"spec.destinationAddresses[0]",
+		"spec.destinationAddresses[1]",
+		"spec.destinationAddresses[2]",
+		"spec.services[0]",
+		"spec.action",
+	}, fields(errs))
+	assert.Equal(t, "zone 'untrust' isn't allowed by the FirewallPolicy 'tenants'", errs[0].Detail)
+
+	// The empty zones, sources and destinations are any.
+	errs = CheckSecurity(policies, blendedv1.SecuritySpec{Services: []string{"k8s-tcp443"}, Action: blendedv1.SecurityAllow}, addr)
+	assert.Equal(t, []string{"spec.sourceZones", "spec.destinationZones", "spec.sourceAddresses", "spec.destinationAddresses"}, fields(errs))
+	assert.Equal(t, "zone 'any' isn't allowed by the FirewallPolicy 'tenants'", errs[0].Detail)
+
+	// The negated sources and destinations are everything but them, so they're any.
+	spec = blendedv1.SecuritySpec{
+		SourceZones:          []string{"trust"},
+		DestinationZones:     []string{"dmz"},
+		SourceAddresses:      []string{"10.20.0.0/24"},
+		NegateSource:         true,
+		DestinationAddresses: []string{"web-vip"},
+		NegateDestination:    true,
+		Services:             []string{"k8s-tcp443"},
+		Action:               blendedv1.SecurityAllow,
+	}
+	errs = CheckSecurity(policies, spec, addr)
+	assert.Equal(t, []string{"spec.sourceAddresses", "spec.destinationAddresses"}, fields(errs))
+	assert.Equal(t, "destination 'any' isn't allowed by the FirewallPolicy 'tenants'", errs[1].Detail)
+
+	// A policy without constraints allows everything.
+	assert.Empty(t, CheckSecurity([]*inwinv1.FirewallPolicy{{}}, spec, addr))
+}
+
+func TestCheckNAT(t *testing.T) {
+	policies := []*inwinv1.FirewallPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+		Spec: inwinv1.FirewallPolicySpec{
+			AllowedZones:            []string{"untrust", "dmz"},
+			AllowedDestinationCIDRs: []string{"140.23.110.0/24", "10.10.0.0/16"},
+			AllowedServices:         []string{"k8s-tcp80"},
+		},
+	}}
+
+	spec := blendedv1.NATSpec{
+		SourceZones:          []string{"untrust"},
+		DestinationZone:      "dmz",
+		SourceAddresses:      []string{"any"},
+		DestinationAddresses: []string{"140.23.110.10"},
+		Service:              "k8s-tcp80",
+		DatAddress:           "10.10.1.10",
+	}
+	assert.Empty(t, CheckNAT(policies, spec, nil))
+
+	spec.DestinationZone = "trust"
+	spec.Service = ""
+	spec.DatAddress = "192.168.1.10"
+	assert.Equal(t, []string{"spec.destinationZone", "spec.datAddress[0]", "spec.service"}, fields(CheckNAT(policies, spec, nil)))
+
+	// The empty zones are any, which is only allowed when the allowed zones have it.
+	spec = blendedv1.NATSpec{DestinationAddresses: []string{"140.23.110.10"}, Service: "k8s-tcp80"}
+	assert.Equal(t, []string{"spec.sourceZones", "spec.destinationZone"}, fields(CheckNAT(policies, spec, nil)))
+	policies[0].Spec.AllowedZones = append(policies[0].Spec.AllowedZones, "any")
+	assert.Empty(t, CheckNAT(policies, spec, nil))
+}
+
+func TestWithin(t *testing.T) {
+	cidrs := []string{"10.10.0.0/16", "2001:db8::/32"}
+	assert.True(t, within("10.10.255.255", cidrs))
+	assert.True(t, within("10.10.128.0/17", cidrs))
+	assert.True(t, within("10.10.0.1-10.10.0.9", cidrs))
+	assert.True(t, within("2001:db8::1", cidrs))
+	assert.False(t, within("10.10.0.0/15", cidrs))
+	assert.False(t, within("10.10.0.9-10.10.0.1", cidrs))
+	assert.False(t, within("10.11.0.1", cidrs))
+	assert.False(t, within("web-vip", cidrs))
+}
@@ -0,0 +1,352 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer converts the existing entries on PAN into resources, so the entries
+// made by hand are adopted by the controller without deleting and recreating them.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blended "github.com/inwinstack/blended/generated/clientset/versioned"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/poli/nat"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/thoas/go-funk"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// NATClient represents the NAT policies of a firewall or a device group of Panorama.
+type NATClient interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (nat.Entry, error)
+}
+
+// SecurityClient represents the security policies of a firewall or a device group of Panorama.
+type SecurityClient interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (security.Entry, error)
+}
+
+// ServiceClient represents the service objects of a firewall or a device group of Panorama.
+type ServiceClient interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (srvc.Entry, error)
+}
+
+// Options are the options of the imported resources.
+type Options struct {
+	// Vsys is the vsys of the entries.
+	Vsys string
+	// Namespace is the namespace of the NATs and Securities.
+	Namespace string
+	// Firewall is the name of the Firewall set in the label of the resources, and the
+	// label isn't set when it's empty.
+	Firewall string
+}
+
+// Importer reads the NAT policies, security policies and service objects on PAN, and
+// converts them into resources.
+type Importer struct {
+	nat      NATClient
+	security SecurityClient
+	service  ServiceClient
+	opts     Options
+}
+
+// Result is the resources converted from the entries, and the entries skipped with the
+// reasons.
+type Result struct {
+	Objects []runtime.Object
+	Skipped []string
+}
+
+// New creates an importer of the entries.
+func New(nat NATClient, security SecurityClient, service ServiceClient, opts Options) *Importer {
+	return &Importer{nat: nat, security: security, service: service, opts: opts}
+}
+
+// IsAdopted returns true when a resource is imported from an existing entry on PAN.
+func IsAdopted(meta metav1.ObjectMeta) bool {
+	return meta.Annotations[constants.AdoptKey] == "true"
+}
+
+// Import converts the entries into resources. The services come first since they're
+// referenced by the policies, and the security policies are in the order of the rulebase.
+// The entries already managed by a controller are skipped, and the ones whose names aren't
+// valid resource names are imported under sanitized names.
+func (i *Importer) Import() (*Result, error) {
+	result := &Result{}
+	if err := i.importServices(result); err != nil {
+		return nil, err
+	}
+
+	if err := i.importNATs(result); err != nil {
+		return nil, err
+	}
+
+	if err := i.importSecurities(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *Importer) importServices(result *Result) error {
+	names, err := i.service.GetList(i.opts.Vsys)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		e, err := i.service.Get(i.opts.Vsys, name)
+		if err != nil {
+			return err
+		}
+
+		if !i.canImport(result, "Service", name, e.Tags) {
+			continue
+		}
+		result.Objects = append(result.Objects, &blendedv1.Service{
+			TypeMeta:   typeMeta("Service"),
+			ObjectMeta: i.objectMeta(name, ""),
+			Spec: blendedv1.ServiceSpec{
+				Description:     e.Description,
+				Protocol:        e.Protocol,
+				SourcePort:      e.SourcePort,
+				DestinationPort: e.DestinationPort,
+				Tags:            e.Tags,
+			},
+		})
+	}
+	return nil
+}
+
+func (i *Importer) importNATs(result *Result) error {
+	names, err := i.nat.GetList(i.opts.Vsys)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		e, err := i.nat.Get(i.opts.Vsys, name)
+		if err != nil {
+			return err
+		}
+
+		if !i.canImport(result, "NAT", name, e.Tags) {
+			continue
+		}
+		result.Objects = append(result.Objects, &blendedv1.NAT{
+			TypeMeta:   typeMeta("NAT"),
+			ObjectMeta: i.objectMeta(name, i.opts.Namespace),
+			Spec: blendedv1.NATSpec{
+				Type:                           e.Type,
+				Description:                    e.Description,
+				SourceZones:                    e.SourceZones,
+				SourceAddresses:                e.SourceAddresses,
+				DestinationAddresses:           e.DestinationAddresses,
+				DestinationZone:                e.DestinationZone,
+				ToInterface:                    e.ToInterface,
+				Service:                        e.Service,
+				SatType:                        e.SatType,
+				SatAddressType:                 e.SatAddressType,
+				SatTranslatedAddresses:         e.SatTranslatedAddresses,
+				SatInterface:                   e.SatInterface,
+				SatIPAddress:                   e.SatIpAddress,
+				SatFallbackType:                e.SatFallbackType,
+				SatFallbackTranslatedAddresses: e.SatFallbackTranslatedAddresses,
+				SatFallbackInterface:           e.SatFallbackInterface,
+				SatFallbackIPType:              e.SatFallbackIpType,
+				SatFallbackIPAddress:           e.SatFallbackIpAddress,
+				SatStaticTranslatedAddress:     e.SatStaticTranslatedAddress,
+				SatStaticBiDirectional:         e.SatStaticBiDirectional,
+				DatType:                        e.DatType,
+				DatAddress:                     e.DatAddress,
+				DatPort:                        int32(e.DatPort),
+				DatDynamicDistribution:         e.DatDynamicDistribution,
+				Disabled:                       e.Disabled,
+				Targets:                        e.Targets,
+				NegateTarget:                   e.NegateTarget,
+				Tags:                           e.Tags,
+			},
+		})
+	}
+	return nil
+}
+
+func (i *Importer) importSecurities(result *Result) error {
+	names, err := i.security.GetList(i.opts.Vsys)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		e, err := i.security.Get(i.opts.Vsys, name)
+		if err != nil {
+			return err
+		}
+
+		if !i.canImport(result, "Security", name, e.Tags) {
+			continue
+		}
+		result.Objects = append(result.Objects, &blendedv1.Security{
+			TypeMeta:   typeMeta("Security"),
+			ObjectMeta: i.objectMeta(name, i.opts.Namespace),
+			Spec: blendedv1.SecuritySpec{
+				Type:                            e.Type,
+				Description:                     e.Description,
+				SourceZones:                     e.SourceZones,
+				SourceAddresses:                 e.SourceAddresses,
+				SourceUsers:                     e.SourceUsers,
+				HipProfiles:                     e.HipProfiles,
+				DestinationZones:                e.DestinationZones,
+				DestinationAddresses:            e.DestinationAddresses,
+				Applications:                    e.Applications,
+				Services:                        e.Services,
+				Categories:                      e.Categories,
+				Action:                          e.Action,
+				IcmpUnreachable:                 e.IcmpUnreachable,
+				DisableServerResponseInspection: e.DisableServerResponseInspection,
+				NegateDestination:               e.NegateDestination,
+				NegateSource:                    e.NegateSource,
+				NegateTarget:                    e.NegateTarget,
+				LogSetting:                      e.LogSetting,
+				LogStart:                        e.LogStart,
+				LogEnd:                          e.LogEnd,
+				Disabled:                        e.Disabled,
+				Schedule:                        e.Schedule,
+				Group:                           e.Group,
+				Targets:                         e.Targets,
+				Virus:                           e.Virus,
+				Spyware:                         e.Spyware,
+				Vulnerability:                   e.Vulnerability,
+				URLFiltering:                    e.UrlFiltering,
+				FileBlocking:                    e.FileBlocking,
+				WildFireAnalysis:                e.WildFireAnalysis,
+				DataFiltering:                   e.DataFiltering,
+				Tags:                            e.Tags,
+			},
+		})
+	}
+	return nil
+}
+
+// canImport tells whether an entry can be imported, and records the reason when it can't.
+func (i *Importer) canImport(result *Result, kind, name string, tags []string) bool {
+	if funk.ContainsString(tags, ownership.Tag) {
+		result.Skipped = append(result.Skipped, fmt.Sprintf("%s '%s': already managed by a controller", kind, name))
+		return false
+	}
+	return true
+}
+
+// objectMeta returns the metadata of the resource of an entry. The name of the entry is
+// recorded on the resource when the resource is named differently, so the entry is never
+// renamed.
+func (i *Importer) objectMeta(name, namespace string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:        resourceName(name),
+		Namespace:   namespace,
+		Annotations: map[string]string{constants.AdoptKey: "true"},
+	}
+
+	if i.opts.Firewall != "" {
+		meta.Labels = map[string]string{constants.FirewallKey: i.opts.Firewall}
+	}
+
+	// The namespaced entries keep their names under any naming scheme.
+	if namespace != "" || meta.Name != name {
+		naming.Record(&meta, name)
+	}
+	return meta
+}
+
+// resourceName returns a valid resource name of an entry. The invalid names, such as
+// "Allow_Web", are lowercased with the invalid characters replaced by "-", and suffixed
+// with the hash of the entry name, so two entries never get the same resource name.
+func resourceName(name string) string {
+	if len(validation.IsDNS1123Subdomain(name)) == 0 {
+		return name
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name))
+	sanitized = strings.Trim(sanitized, "-")
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:8]
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: blendedv1.SchemeGroupVersion.String(), Kind: kind}
+}
+
+// YAML returns the resources as a multi-document YAML, without their status.
+func YAML(objs []runtime.Object) ([]byte, error) {
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(u, "status")
+		if meta, ok := u["metadata"].(map[string]interface{}); ok {
+			delete(meta, "creationTimestamp")
+		}
+
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(b))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
+
+// Create creates a resource, and returns an already exists error when it exists.
+func Create(client blended.Interface, obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *blendedv1.Service:
+		_, err := client.InwinstackV1().Services().Create(o)
+		return err
+	case *blendedv1.NAT:
+		_, err := client.InwinstackV1().NATs(o.Namespace).Create(o)
+		return err
+	case *blendedv1.Security:
+		_, err := client.InwinstackV1().Securities(o.Namespace).Create(o)
+		return err
+	}
+	return fmt.Errorf("unknown resource %T", obj)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/poli/nat"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type fakeNat struct{ entries []nat.Entry }
+
+func (f *fakeNat) GetList(_ string) ([]string, error) {
+	names := []string{}
+	for _, e := range f.entries {
+		names = append(names, e.Name)
+	}
+	return names, nil
+}
+
+func (f *fakeNat) Get(_, name string) (nat.Entry, error) {
+	for _, e := range f.entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return nat.Entry{}, nil
+}
+
+type fakeSecurity struct{ entries []security.Entry }
+
+func (f *fakeSecurity) GetList(_ string) ([]string, error) {
+	names := []string{}
+	for _, e := range f.entries {
+		names = append(names, e.Name)
+	}
+	return names, nil
+}
+
+func (f *fakeSecurity) Get(_, name string) (security.Entry, error) {
+	for _, e := range f.entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return security.Entry{}, nil
+}
+
+type fakeService struct{ entries []srvc.Entry }
+
+func (f *fakeService) GetList(_ string) ([]string, error) {
+	names := []string{}
+	for _, e := range f.entries {
+		names = append(names, e.Name)
+	}
+	return names, nil
+}
+
+func (f *fakeService) Get(_, name string) (srvc.Entry, error) {
+	for _, e := range f.entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return srvc.Entry{}, nil
+}
+
+func TestImport(t *testing.T) {
+	fnat := &fakeNat{entries: []nat.Entry{
+		{Name: "web-nat", Type: "ipv4", SourceZones: []string{"untrust"}, DestinationZone: "untrust", DatPort: 8080},
+	}}
+	fsec := &fakeSecurity{entries: []security.Entry{
+		{Name: "allow-web", Action: "allow", Services: []string{"web"}, Tags: []string{"web"}},
+		{Name: "Allow SSH", Action: "allow"},
+		{Name: "k8s-web", Action: "allow", Tags: ownership.Tags("k8s-a")},
+	}}
+	fsvc := &fakeService{entries: []srvc.Entry{
+		{Name: "web", Protocol: "tcp", DestinationPort: "80,443"},
+		{Name: "Web_TLS", Protocol: "tcp", DestinationPort: "443"},
+	}}
+
+	imp := New(fnat, fsec, fsvc, Options{Vsys: "vsys1", Namespace: "fw", Firewall: "fw1"})
+	result, err := imp.Import()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(result.Objects))
+	assert.Equal(t, []string{"Security 'k8s-web': already managed by a controller"}, result.Skipped)
+
+	svc := result.Objects[0].(*blendedv1.Service)
+	assert.Equal(t, "Service", svc.Kind)
+	assert.Equal(t, "inwinstack.com/v1", svc.APIVersion)
+	assert.Equal(t, "", svc.Namespace)
+	assert.Equal(t, "80,443", svc.Spec.DestinationPort)
+	assert.True(t, IsAdopted(svc.ObjectMeta))
+	assert.Equal(t, "fw1", svc.Labels[constants.FirewallKey])
+	assert.Equal(t, "", svc.Annotations[constants.EntryNameKey])
+
+	svc = result.Objects[1].(*blendedv1.Service)
+	assert.Equal(t, resourceName("Web_TLS"), svc.Name)
+	assert.Equal(t, "Web_TLS", naming.EntryName(naming.SchemeNamespace, svc.ObjectMeta))
+
+	n := result.Objects[2].(*blendedv1.NAT)
+	assert.Equal(t, "fw", n.Namespace)
+	assert.Equal(t, int32(8080), n.Spec.DatPort)
+	assert.Equal(t, []string{"untrust"}, n.Spec.SourceZones)
+
+	sec := result.Objects[3].(*blendedv1.Security)
+	assert.Equal(t, "allow-web", sec.Name)
+	assert.Equal(t, "allow-web", naming.EntryName(naming.SchemeNamespace, sec.ObjectMeta))
+	assert.Equal(t, []string{"web"}, sec.Spec.Services)
+	assert.Equal(t, []string{"web"}, sec.Spec.Tags)
+
+	sec = result.Objects[4].(*blendedv1.Security)
+	assert.True(t, strings.HasPrefix(sec.Name, "allow-ssh-"))
+	assert.Equal(t, "Allow SSH", naming.EntryName(naming.SchemeNamespace, sec.ObjectMeta))
+
+	out, err := YAML(result.Objects)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(strings.Split(string(out), "---\n")))
+	assert.Contains(t, string(out), "inwinstack.com/pa-adopt: \"true\"")
+	assert.NotContains(t, string(out), "status")
+	assert.NotContains(t, string(out), "creationTimestamp")
+
+	blendedset := blendedfake.NewSimpleClientset()
+	for _, obj := range result.Objects {
+		assert.Nil(t, Create(blendedset, obj))
+	}
+	assert.True(t, errors.IsAlreadyExists(Create(blendedset, result.Objects[3])))
+
+	created, err := blendedset.InwinstackV1().Securities("fw").Get("allow-web", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.True(t, IsAdopted(created.ObjectMeta))
+}
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, "allow-web", resourceName("allow-web"))
+	assert.Equal(t, "web.tls", resourceName("web.tls"))
+
+	for _, name := range []string{"Allow SSH", "allow_ssh", "_Web.TLS_", "---", "DNS (UDP)"} {
+		r := resourceName(name)
+		assert.Empty(t, validation.IsDNS1123Subdomain(r), name)
+		assert.NotEqual(t, name, r)
+	}
+	assert.NotEqual(t, resourceName("Allow SSH"), resourceName("allow_ssh"))
+	assert.Equal(t, resourceName("Allow SSH"), resourceName("Allow SSH"))
+}
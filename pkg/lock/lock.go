@@ -0,0 +1,281 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lock detects the config and commit locks held by the admins of PAN, and takes the
+// locks of the controller around the edits and commits.
+package lock
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sync"
+
+	"github.com/inwinstack/pango/util"
+)
+
+// Comment is the prefix of the comments of the locks taken by the controller, which tells them
+// apart from the locks of the admins.
+const Comment = "pa-controller"
+
+// These are the types of the locks.
+const (
+	TypeConfig = "config"
+	TypeCommit = "commit"
+)
+
+// Error is returned when a lock is held by another admin.
+type Error struct {
+	Type  string
+	Admin string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("blocked by lock held by %s", e.Admin)
+}
+
+// IsLocked returns true when the error is caused by a lock of another admin.
+func IsLocked(err error) bool {
+	_, ok := err.(*Error)
+	return ok
+}
+
+type showRequest struct {
+	XMLName     xml.Name  `xml:"show"`
+	ConfigLocks *struct{} `xml:"config-locks"`
+	CommitLocks *struct{} `xml:"commit-locks"`
+}
+
+type showResponse struct {
+	XMLName     xml.Name    `xml:"response"`
+	ConfigLocks []util.Lock `xml:"result>config-locks>entry"`
+	CommitLocks []util.Lock `xml:"result>commit-locks>entry"`
+}
+
+type lockRequest struct {
+	XMLName xml.Name     `xml:"request"`
+	Config  *lockCommand `xml:"config-lock"`
+	Commit  *lockCommand `xml:"commit-lock"`
+}
+
+type lockCommand struct {
+	Add    *lockAdd  `xml:"add"`
+	Remove *struct{} `xml:"remove"`
+}
+
+type lockAdd struct {
+	Comment string `xml:"comment,omitempty"`
+}
+
+// Locks returns the config and commit locks of a scope.
+func Locks(client util.XapiClient, scope string) ([]util.Lock, []util.Lock, error) {
+	config := showResponse{}
+	if _, err := client.Op(showRequest{ConfigLocks: &struct{}{}}, scope, nil, &config); err != nil {
+		return nil, nil, err
+	}
+
+	commit := showResponse{}
+	if _, err := client.Op(showRequest{CommitLocks: &struct{}{}}, scope, nil, &commit); err != nil {
+		return nil, nil, err
+	}
+	return config.ConfigLocks, commit.CommitLocks, nil
+}
+
+// Locker detects the locks of the other admins, and takes the locks of the controller when
+// it's enabled. It also tracks the edits in flight, so the locks aren't released and the
+// candidate config isn't reverted under an edit. The methods of a nil locker do nothing.
+type Locker struct {
+	client  util.XapiClient
+	scope   string
+	comment string
+	take    bool
+
+	// edits is held for reading by the edits from Acquire to Done, and for writing while
+	// the edits are paused.
+	edits sync.RWMutex
+
+	lock    sync.Mutex
+	held    bool
+	holders int
+}
+
+// NewLocker creates a locker for the scope, which is a vsys of a firewall or a device group
+// of Panorama. The id is added to the comment of the locks, so the locks of the controllers
+// of other clusters or firewalls on the same PAN aren't taken as its own. The locks are only
+// taken when take is set.
+func NewLocker(client util.XapiClient, scope, id string, take bool) *Locker {
+	comment := Comment
+	if id != "" {
+		comment = fmt.Sprintf("%s %s", Comment, id)
+	}
+	return &Locker{client: client, scope: scope, comment: comment, take: take}
+}
+
+// Check returns an error when a config or commit lock is held by another admin.
+func (l *Locker) Check() error {
+	if l == nil {
+		return nil
+	}
+
+	configLocks, commitLocks, err := Locks(l.client, l.scope)
+	if err != nil {
+		return err
+	}
+
+	if lock := foreign(configLocks, l.comment); lock != nil {
+		return &Error{Type: TypeConfig, Admin: lock.Owner}
+	}
+	if lock := foreign(commitLocks, l.comment); lock != nil {
+		return &Error{Type: TypeCommit, Admin: lock.Owner}
+	}
+	return nil
+}
+
+// Explain returns the lock error when the edit fails while a lock is held by another admin,
+// so the resources report the owner of the lock instead of the error of PAN.
+func (l *Locker) Explain(err error) error {
+	if l == nil || err == nil {
+		return err
+	}
+
+	if lerr := l.Check(); IsLocked(lerr) {
+		return lerr
+	}
+	return err
+}
+
+// Acquire starts an edit, and takes the config and commit locks for the edits and the commit
+// when they're enabled, which are kept until Release. The locks held by another admin are
+// returned as an error. Every edit started by Acquire must be finished by Done once its change
+// is queued for the commit.
+func (l *Locker) Acquire() error {
+	if l == nil {
+		return nil
+	}
+
+	l.edits.RLock()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := l.lockAll(); err != nil {
+		l.edits.RUnlock()
+		return err
+	}
+	l.holders++
+	return nil
+}
+
+// Done finishes an edit started by Acquire.
+func (l *Locker) Done() {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	l.holders--
+	l.lock.Unlock()
+	l.edits.RUnlock()
+}
+
+// Pause waits for the edits in flight to finish, and holds the new edits off until Resume.
+func (l *Locker) Pause() {
+	if l == nil {
+		return
+	}
+	l.edits.Lock()
+}
+
+// Resume lets the edits paused by Pause go on.
+func (l *Locker) Resume() {
+	if l == nil {
+		return
+	}
+	l.edits.Unlock()
+}
+
+func (l *Locker) lockAll() error {
+	if !l.take || l.held {
+		return nil
+	}
+
+	if err := l.Check(); err != nil {
+		return err
+	}
+
+	add := &lockCommand{Add: &lockAdd{Comment: l.comment}}
+	if _, err := l.client.Op(lockRequest{Config: add}, l.scope, nil, nil); err != nil {
+		return l.Explain(err)
+	}
+
+	if _, err := l.client.Op(lockRequest{Commit: add}, l.scope, nil, nil); err != nil {
+		l.remove(TypeConfig)
+		return l.Explain(err)
+	}
+	l.held = true
+	return nil
+}
+
+// Release releases the locks taken by Acquire.
+func (l *Locker) Release() error {
+	return l.ReleaseIdle(func() int { return 0 })
+}
+
+// ReleaseIdle releases the locks taken by Acquire when no edit is in flight and no change is
+// pending for the commit, which pending returns. The locks are kept otherwise, since the
+// changes aren't committed yet.
+func (l *Locker) ReleaseIdle(pending func() int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.held || l.holders != 0 || pending() != 0 {
+		return nil
+	}
+
+	// The config lock is released even if the commit lock isn't.
+	commitErr := l.remove(TypeCommit)
+	if err := l.remove(TypeConfig); err != nil {
+		return err
+	}
+	if commitErr != nil {
+		return commitErr
+	}
+	l.held = false
+	return nil
+}
+
+func (l *Locker) remove(t string) error {
+	req := lockRequest{}
+	switch t {
+	case TypeConfig:
+		req.Config = &lockCommand{Remove: &struct{}{}}
+	case TypeCommit:
+		req.Commit = &lockCommand{Remove: &struct{}{}}
+	}
+	_, err := l.client.Op(req, l.scope, nil, nil)
+	return err
+}
+
+// foreign returns the first lock which isn't taken by the controller, whose locks have the
+// comment.
+func foreign(locks []util.Lock, comment string) *util.Lock {
+	for i := range locks {
+		if locks[i].Comment.Text != comment {
+			return &locks[i]
+		}
+	}
+	return nil
+}
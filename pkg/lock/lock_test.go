@@ -0,0 +1,165 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/pango/testdata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<config-locks><entry name="admin1"><name>vsys1</name><type>vsys</type><comment><![CDATA[maintenance]]></comment></entry></config-locks>`)
+	mc.AddResp(`<commit-locks></commit-locks>`)
+
+	locker := NewLocker(mc, "vsys1", "", false)
+	err := locker.Check()
+	assert.True(t, IsLocked(err))
+	assert.Equal(t, "blocked by lock held by admin1", err.Error())
+	assert.Equal(t, TypeConfig, err.(*Error).Type)
+	assert.Equal(t, "<show><commit-locks></commit-locks></show>", mc.Elm)
+
+	editErr := fmt.Errorf("edit failed")
+	assert.Equal(t, "blocked by lock held by admin1", locker.Explain(editErr).Error())
+
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<config-locks><entry name="api"><comment><![CDATA[pa-controller]]></comment></entry></config-locks>`)
+	mc.AddResp(`<commit-locks><entry name="admin2"><comment/></entry></commit-locks>`)
+	err = NewLocker(mc, "vsys1", "", false).Check()
+	assert.Equal(t, &Error{Type: TypeCommit, Admin: "admin2"}, err)
+
+	// The locks of the controller of another cluster on the same firewall are foreign.
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<config-locks><entry name="api"><comment><![CDATA[pa-controller prod]]></comment></entry></config-locks>`)
+	mc.AddResp(`<commit-locks/>`)
+	err = NewLocker(mc, "vsys1", "staging", false).Check()
+	assert.Equal(t, &Error{Type: TypeConfig, Admin: "api"}, err)
+
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<config-locks><entry name="api"><comment><![CDATA[pa-controller staging]]></comment></entry></config-locks>`)
+	mc.AddResp(`<commit-locks/>`)
+	assert.Nil(t, NewLocker(mc, "vsys1", "staging", false).Check())
+
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<config-locks/>`)
+	locker = NewLocker(mc, "vsys1", "", false)
+	assert.Nil(t, locker.Check())
+	assert.Equal(t, editErr, locker.Explain(editErr))
+
+	var nilLocker *Locker
+	assert.Nil(t, nilLocker.Check())
+	assert.Nil(t, nilLocker.Acquire())
+	assert.Nil(t, nilLocker.Release())
+	assert.Equal(t, editErr, nilLocker.Explain(editErr))
+}
+
+func TestAcquireAndRelease(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+
+	// The locks aren't taken unless it's enabled.
+	locker := NewLocker(mc, "vsys1", "", false)
+	assert.Nil(t, locker.Acquire())
+	locker.Done()
+	assert.Equal(t, 0, mc.Called)
+
+	locker = NewLocker(mc, "vsys1", "", true)
+	assert.Nil(t, locker.Acquire())
+	assert.Equal(t, 4, mc.Called)
+	assert.Equal(t, "<request><commit-lock><add><comment>pa-controller</comment></add></commit-lock></request>", mc.Elm)
+	assert.Equal(t, "vsys1", mc.Vsys)
+
+	// The locks are kept until they're released.
+	assert.Nil(t, locker.Acquire())
+	assert.Equal(t, 4, mc.Called)
+
+	// The locks aren't released while an edit is in flight or a change is pending.
+	locker.Done()
+	assert.Nil(t, locker.Release())
+	assert.Equal(t, 4, mc.Called)
+	locker.Done()
+	assert.Nil(t, locker.ReleaseIdle(func() int { return 1 }))
+	assert.Equal(t, 4, mc.Called)
+
+	assert.Nil(t, locker.ReleaseIdle(func() int { return 0 }))
+	assert.Equal(t, 6, mc.Called)
+	assert.Equal(t, "<request><config-lock><remove></remove></config-lock></request>", mc.Elm)
+
+	assert.Nil(t, locker.Release())
+	assert.Equal(t, 6, mc.Called)
+
+	mc = &testdata.MockClient{}
+	mc.AddResp("")
+	locker = NewLocker(mc, "vsys1", "prod/site-a", true)
+	assert.Nil(t, locker.Acquire())
+	assert.Equal(t, "<request><commit-lock><add><comment>pa-controller prod/site-a</comment></add></commit-lock></request>", mc.Elm)
+
+	mc = &testdata.MockClient{}
+	mc.AddResp(`<config-locks><entry name="admin1"><comment/></entry></config-locks>`)
+	locker = NewLocker(mc, "vsys1", "", true)
+	assert.Equal(t, &Error{Type: TypeConfig, Admin: "admin1"}, locker.Acquire())
+	assert.Nil(t, locker.Release())
+	assert.Equal(t, 2, mc.Called)
+}
+
+func TestPause(t *testing.T) {
+	locker := NewLocker(&testdata.MockClient{}, "vsys1", "", false)
+	assert.Nil(t, locker.Acquire())
+
+	// Pause waits for the edit in flight, and holds the new edits off until Resume.
+	paused := make(chan struct{})
+	go func() {
+		locker.Pause()
+		close(paused)
+	}()
+
+	select {
+	case <-paused:
+		t.Fatal("The edits were paused while an edit is in flight.")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	locker.Done()
+	<-paused
+
+	acquired := make(chan struct{})
+	go func() {
+		assert.Nil(t, locker.Acquire())
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("An edit was started while the edits are paused.")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	locker.Resume()
+	<-acquired
+	locker.Done()
+
+	// The methods of a nil locker do nothing.
+	var none *Locker
+	assert.Nil(t, none.Acquire())
+	none.Done()
+	none.Pause()
+	none.Resume()
+}
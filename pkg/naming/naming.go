@@ -0,0 +1,112 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming names the entries on PAN of the namespaced resources, so the resources of
+// the same name in different namespaces don't overwrite each other's entry.
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// These are the valid naming schemes.
+const (
+	// SchemeName names an entry by the name of its resource.
+	SchemeName = "name"
+	// SchemeNamespace names an entry by the namespace and name of its resource, such as
+	// default-allow-web.
+	SchemeNamespace = "namespace"
+)
+
+// MaxLength is the max length of the names on PAN.
+const MaxLength = 63
+
+// EntryName returns the name of the entry on PAN of a resource. The name recorded on the
+// resource is kept, so the entries aren't renamed when the naming scheme changes, and the
+// new resources are named by the scheme.
+func EntryName(scheme string, meta metav1.ObjectMeta) string {
+	if name := meta.Annotations[constants.EntryNameKey]; name != "" {
+		return name
+	}
+
+	if scheme != SchemeNamespace || meta.Namespace == "" {
+		return meta.Name
+	}
+
+	return Shorten(meta.Namespace+"-"+meta.Name, meta.Namespace+"/"+meta.Name)
+}
+
+// Shorten returns a name fitting the max length of the names on PAN. The long names are
+// truncated with the hash of a key, such as the resource key, to keep them unique.
+func Shorten(name, key string) string {
+	if len(name) <= MaxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])[:8]
+	return name[:MaxLength-len(hash)-1] + "-" + hash
+}
+
+// Record records the name of the entry on the annotations of a resource, and returns true
+// when the annotation changes.
+func Record(meta *metav1.ObjectMeta, name string) bool {
+	if meta.Annotations[constants.EntryNameKey] == name {
+		return false
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[constants.EntryNameKey] = name
+	return true
+}
+
+// Conflict returns an error when the entry of a resource is owned by another resource. The
+// resource which has recorded the name owns the entry, or otherwise the older one does, so
+// the later resource fails instead of overwriting the entry.
+func Conflict(scheme string, meta metav1.ObjectMeta, others []metav1.ObjectMeta) error {
+	name := EntryName(scheme, meta)
+	for _, o := range others {
+		if o.Namespace == meta.Namespace && o.Name == meta.Name {
+			continue
+		}
+
+		if EntryName(scheme, o) == name && owns(o, meta, name) {
+			return fmt.Errorf("the entry '%s' on PAN is owned by '%s/%s'", name, o.Namespace, o.Name)
+		}
+	}
+	return nil
+}
+
+// owns returns true when a resource owns the entry before another.
+func owns(a, b metav1.ObjectMeta, name string) bool {
+	recordedA := a.Annotations[constants.EntryNameKey] == name
+	recordedB := b.Annotations[constants.EntryNameKey] == name
+	if recordedA != recordedB {
+		return recordedA
+	}
+
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEntryName(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "allow-web", Namespace: "default"}
+	assert.Equal(t, "allow-web", EntryName(SchemeName, meta))
+	assert.Equal(t, "allow-web", EntryName("", meta))
+	assert.Equal(t, "default-allow-web", EntryName(SchemeNamespace, meta))
+
+	long := metav1.ObjectMeta{Name: strings.Repeat("a", 60), Namespace: "team-a"}
+	name := EntryName(SchemeNamespace, long)
+	assert.Equal(t, MaxLength, len(name))
+	assert.True(t, strings.HasPrefix(name, "team-a-aaaa"))
+
+	other := metav1.ObjectMeta{Name: strings.Repeat("a", 60), Namespace: "team-b"}
+	assert.NotEqual(t, name, EntryName(SchemeNamespace, other))
+	assert.Equal(t, name, EntryName(SchemeNamespace, long))
+
+	// The recorded name is kept under any scheme.
+	assert.True(t, Record(&meta, "allow-web"))
+	assert.False(t, Record(&meta, "allow-web"))
+	assert.Equal(t, "allow-web", meta.Annotations[constants.EntryNameKey])
+	assert.Equal(t, "allow-web", EntryName(SchemeNamespace, meta))
+}
+
+func TestConflict(t *testing.T) {
+	now := metav1.Now()
+	older := metav1.ObjectMeta{Name: "web", Namespace: "team-b", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}
+	newer := metav1.ObjectMeta{Name: "web", Namespace: "team-a", CreationTimestamp: now}
+	others := []metav1.ObjectMeta{older, newer}
+
+	assert.Nil(t, Conflict(SchemeName, older, others))
+	assert.EqualError(t, Conflict(SchemeName, newer, others), "the entry 'web' on PAN is owned by 'team-b/web'")
+	assert.Nil(t, Conflict(SchemeNamespace, newer, others))
+
+	// The resource which has recorded the name owns the entry.
+	Record(&others[1], "web")
+	assert.EqualError(t, Conflict(SchemeName, older, others), "the entry 'web' on PAN is owned by 'team-a/web'")
+	assert.Nil(t, Conflict(SchemeName, others[1], others))
+
+	// The key decides between the resources of the same age.
+	a := metav1.ObjectMeta{Name: "web", Namespace: "team-a"}
+	b := metav1.ObjectMeta{Name: "web", Namespace: "team-b"}
+	assert.Nil(t, Conflict(SchemeName, a, []metav1.ObjectMeta{a, b}))
+	assert.NotNil(t, Conflict(SchemeName, b, []metav1.ObjectMeta{a, b}))
+}
@@ -0,0 +1,274 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	blended "github.com/inwinstack/blended/generated/clientset/versioned"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	listerv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller represents the controller of firewall. It runs a PAN controller with its
+// own commit pipeline and informers for each firewall.
+type Controller struct {
+	cfg        *config.Config
+	blendedset blended.Interface
+	inwinset   inwin.Interface
+	kubeset    kubernetes.Interface
+	lister     listerv1.FirewallLister
+	synced     cache.InformerSynced
+	queue      workqueue.RateLimitingInterface
+
+	ctx       context.Context
+	lock      sync.Mutex
+	instances map[string]*instance
+
+	// initialize initializes the PAN client of a firewall, it is replaced in testing.
+	initialize func(initializer) error
+}
+
+const defaultSyncTime = time.Second * 30
+
+// resyncTime returns the resync period of the informers of the PAN controllers, which is the
+// same as the one of the operator.
+func (c *Controller) resyncTime() time.Duration {
+	if c.cfg.SyncSec > 30 {
+		return time.Second * time.Duration(c.cfg.SyncSec)
+	}
+	return defaultSyncTime
+}
+
+// initializer represents a firewall or Panorama to be initialized.
+type initializer interface {
+	Initialize() error
+}
+
+// NewController creates an instance of the firewall controller
+func NewController(
+	cfg *config.Config,
+	blendedset blended.Interface,
+	inwinset inwin.Interface,
+	kubeset kubernetes.Interface,
+	inwinInformer inwininformers.SharedInformerFactory) *Controller {
+	fwInformer := inwinInformer.Inwinstack().V1().Firewalls()
+	controller := &Controller{
+		cfg:        cfg,
+		blendedset: blendedset,
+		inwinset:   inwinset,
+		kubeset:    kubeset,
+		lister:     fwInformer.Lister(),
+		synced:     fwInformer.Informer().HasSynced,
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Firewalls"),
+		instances:  map[string]*instance{},
+		initialize: func(device initializer) error {
+			return device.Initialize()
+		},
+	}
+	fwInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueue(new)
+		},
+		DeleteFunc: controller.enqueue,
+	})
+	return controller
+}
+
+// Run serves the firewall controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the firewall controller")
+	glog.Info("Waiting for the firewall informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	c.ctx = ctx
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the firewall controller and the PAN controllers of all firewalls
+func (c *Controller) Stop() {
+	glog.Info("Stopping the firewall controller")
+	c.queue.ShutDown()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for name, inst := range c.instances {
+		inst.stop()
+		delete(c.instances, name)
+	}
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("Firewall expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("Firewall error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("Firewall successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) reconcile(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	fw, err := c.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.stopInstance(name)
+			return nil
+		}
+		return err
+	}
+
+	if !fw.ObjectMeta.DeletionTimestamp.IsZero() {
+		c.stopInstance(name)
+		return nil
+	}
+
+	c.lock.Lock()
+	inst, ok := c.instances[name]
+	c.lock.Unlock()
+	if ok && reflect.DeepEqual(inst.spec, fw.Spec) {
+		return nil
+	}
+
+	c.stopInstance(name)
+	if err := c.startInstance(fw); err != nil {
+		return c.updateStatus(name, inwinv1.FirewallFailed, err.Error(), "")
+	}
+	return nil
+}
+
+func (c *Controller) stopInstance(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if inst, ok := c.instances[name]; ok {
+		glog.Infof("Stopping the PAN controller of firewall '%s'.", name)
+		inst.stop()
+		delete(c.instances, name)
+	}
+}
+
+func (c *Controller) updateStatus(name string, phase inwinv1.FirewallPhase, reason, version string) error {
+	fw, err := c.inwinset.InwinstackV1().Firewalls().Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	fwCopy := fw.DeepCopy()
+	fwCopy.Status.Phase = phase
+	fwCopy.Status.Reason = reason
+	if version != "" {
+		fwCopy.Status.Version = version
+	}
+	fwCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.inwinset.InwinstackV1().Firewalls().Update(fwCopy); err != nil {
+		return err
+	}
+
+	if phase == inwinv1.FirewallFailed {
+		glog.Errorf("Firewall '%s' got an error:%+v.", name, reason)
+	}
+	return nil
+}
+
+// updateDevices updates the push results of the devices, and the reason is set when the push
+// fails.
+func (c *Controller) updateDevices(name string, devices []inwinv1.FirewallDeviceStatus, pushErr error) error {
+	fw, err := c.inwinset.InwinstackV1().Firewalls().Get(name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	fwCopy := fw.DeepCopy()
+	fwCopy.Status.Devices = devices
+	fwCopy.Status.Reason = ""
+	if pushErr != nil {
+		fwCopy.Status.Reason = fmt.Sprintf("failed to push to the devices: %s", pushErr.Error())
+	}
+	fwCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	_, err = c.inwinset.InwinstackV1().Firewalls().Update(fwCopy)
+	return err
+}
@@ -0,0 +1,230 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pango"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const timeout = 3 * time.Second
+
+func TestFirewallController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{Threads: 2, Retry: 5, Vsys: "vsys1", FirewallSecretNamespace: "kube-system"}
+	kubeset := kubefake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-a", Namespace: "kube-system"},
+		Data:       map[string][]byte{credentials.APIKeyKey: []byte("key")},
+	}, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-c", Namespace: "kube-system"},
+		Data: map[string][]byte{
+			credentials.HostKey:   []byte("172.22.132.200"),
+			credentials.APIKeyKey: []byte("key"),
+		},
+	}, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-d", Namespace: "default"},
+		Data:       map[string][]byte{credentials.APIKeyKey: []byte("key")},
+	})
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// The namespaces are bound to the client of the firewall once it's initialized.
+	controller := NewController(cfg, blendedset, inwinset, kubeset, inwinInformer)
+	controller.initialize = func(device initializer) error {
+		if fw, ok := device.(*pango.Firewall); ok {
+			assert.Equal(t, "172.22.132.114", fw.Client.Hostname)
+			assert.Equal(t, "key", fw.Client.ApiKey)
+		}
+		return nil
+	}
+
+	go inwinInformer.Start(ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	force := true
+	fw := &inwinv1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-a"},
+		Spec: inwinv1.FirewallSpec{
+			Host:              "172.22.132.114",
+			CredentialsSecret: "kube-system/site-a",
+			Vsys:              "vsys2",
+			Commit:            inwinv1.FirewallCommitSpec{Force: &force},
+		},
+	}
+	_, err := inwinset.InwinstackV1().Firewalls().Create(fw)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gfw, err := inwinset.InwinstackV1().Firewalls().Get(fw.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gfw.Status.Phase == inwinv1.FirewallActive {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The firewall hasn't become active.")
+
+	controller.lock.Lock()
+	inst, ok := controller.instances[fw.Name]
+	controller.lock.Unlock()
+	assert.True(t, ok)
+	assert.Equal(t, fw.Name, inst.cfg.Firewall)
+	assert.Equal(t, "vsys2", inst.cfg.Vsys)
+	assert.True(t, inst.cfg.Force)
+	assert.Equal(t, "", cfg.Firewall)
+	assert.Equal(t, "vsys1", cfg.Vsys)
+	assert.NotNil(t, inst.fw.Policies.Security)
+
+	// The PAN controller is stopped when the firewall becomes passive, and a new one is run
+	// with its own informers once it becomes active again.
+	previous := inst.controller
+	assert.NotNil(t, previous)
+	inst.stopController()
+	assert.Nil(t, inst.controller)
+	assert.Nil(t, controller.runController(inst))
+	assert.NotNil(t, inst.controller)
+	assert.False(t, previous == inst.controller)
+
+	assert.Nil(t, inwinset.InwinstackV1().Firewalls().Delete(fw.Name, nil))
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		controller.lock.Lock()
+		_, ok := controller.instances[fw.Name]
+		controller.lock.Unlock()
+		if !ok {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The PAN controller of firewall hasn't stopped.")
+
+	pano := &inwinv1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "panorama"},
+		Spec: inwinv1.FirewallSpec{
+			Host:              "172.22.132.120",
+			CredentialsSecret: "kube-system/site-a",
+			Panorama:          &inwinv1.FirewallPanoramaSpec{DeviceGroup: "k8s", Rulebase: "post"},
+		},
+	}
+	_, err = inwinset.InwinstackV1().Firewalls().Create(pano)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gfw, err := inwinset.InwinstackV1().Firewalls().Get(pano.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gfw.Status.Phase == inwinv1.FirewallActive {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The Panorama hasn't become active.")
+
+	controller.lock.Lock()
+	inst, ok = controller.instances[pano.Name]
+	controller.lock.Unlock()
+	assert.True(t, ok)
+	assert.NotNil(t, inst.pano)
+	assert.True(t, inst.cfg.Panorama)
+	assert.Equal(t, "k8s", inst.cfg.DeviceGroup)
+	assert.Equal(t, "post", inst.cfg.Rulebase)
+
+	devices := []inwinv1.FirewallDeviceStatus{{Serial: "0001", Name: "fw-a", Result: "OK"}}
+	assert.Nil(t, controller.updateDevices(pano.Name, devices, nil))
+	gpano, err := inwinset.InwinstackV1().Firewalls().Get(pano.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, devices, gpano.Status.Devices)
+	assert.Equal(t, inwinv1.FirewallActive, gpano.Status.Phase)
+
+	broken := &inwinv1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-b"},
+		Spec: inwinv1.FirewallSpec{
+			Host:              "172.22.132.115",
+			CredentialsSecret: "kube-system/unknown",
+		},
+	}
+	_, err = inwinset.InwinstackV1().Firewalls().Create(broken)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gfw, err := inwinset.InwinstackV1().Firewalls().Get(broken.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gfw.Status.Phase == inwinv1.FirewallFailed {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The firewall hasn't failed.")
+
+	// The secret can't redirect the firewall to another host, and the secrets out of the
+	// namespace granted to the controller aren't read.
+	mismatched := &inwinv1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-c"},
+		Spec: inwinv1.FirewallSpec{
+			Host:              "172.22.132.116",
+			CredentialsSecret: "kube-system/site-c",
+		},
+	}
+	outside := &inwinv1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-d"},
+		Spec: inwinv1.FirewallSpec{
+			Host:              "172.22.132.117",
+			CredentialsSecret: "default/site-d",
+		},
+	}
+	reasons := map[string]string{
+		mismatched.Name: `the host "172.22.132.200" of the credentials doesn't match the host "172.22.132.116"`,
+		outside.Name:    `the credentials secret "default/site-d" must be in the namespace "kube-system"`,
+	}
+	for _, f := range []*inwinv1.Firewall{mismatched, outside} {
+		_, err = inwinset.InwinstackV1().Firewalls().Create(f)
+		assert.Nil(t, err)
+
+		failed = true
+		for start := time.Now(); time.Since(start) < timeout; {
+			gfw, err := inwinset.InwinstackV1().Firewalls().Get(f.Name, metav1.GetOptions{})
+			assert.Nil(t, err)
+			if gfw.Status.Phase == inwinv1.FirewallFailed {
+				assert.Equal(t, reasons[f.Name], gfw.Status.Reason)
+				failed = false
+				break
+			}
+		}
+		assert.Equal(t, false, failed, "The firewall %s hasn't failed.", f.Name)
+	}
+
+	controller.Stop()
+}
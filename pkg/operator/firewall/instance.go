@@ -0,0 +1,295 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/ha"
+	"github.com/inwinstack/pa-controller/pkg/operator/pan"
+	"github.com/inwinstack/pango"
+	"github.com/inwinstack/pango/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+)
+
+// instance represents the running PAN controller of a firewall. Either the fw or the pano is
+// set, depending on whether the host is a Panorama, and its namespaces are bound to the client.
+type instance struct {
+	spec   inwinv1.FirewallSpec
+	cfg    *config.Config
+	fw     *pango.Firewall
+	pano   *pango.Panorama
+	client *credentials.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock       sync.Mutex
+	controller *pan.Controller
+	stopRun    context.CancelFunc
+}
+
+// Config returns the config of a firewall, the options of the base config are used when
+// they aren't set in the firewall.
+func Config(base *config.Config, f *inwinv1.Firewall) *config.Config {
+	cfg := *base
+	cfg.Firewall = f.Name
+	cfg.Host = f.Spec.Host
+	cfg.Username = ""
+	cfg.Password = ""
+	cfg.APIKey = ""
+	if f.Spec.Vsys != "" {
+		cfg.Vsys = f.Spec.Vsys
+	}
+
+	if f.Spec.DryRun != nil {
+		cfg.DryRun = *f.Spec.DryRun
+	}
+
+	commit := f.Spec.Commit
+	if len(commit.Admins) != 0 {
+		cfg.Admins = commit.Admins
+	}
+
+	if commit.DaNPartial != nil {
+		cfg.DaNPartial = *commit.DaNPartial
+	}
+
+	if commit.PaOPartial != nil {
+		cfg.PaOPartial = *commit.PaOPartial
+	}
+
+	if commit.Force != nil {
+		cfg.Force = *commit.Force
+	}
+
+	if commit.Sync != nil {
+		cfg.Sync = *commit.Sync
+	}
+
+	if commit.Lock != nil {
+		cfg.Lock = *commit.Lock
+	}
+
+	if commit.WaitTime > 0 {
+		cfg.CommitWaitTime = commit.WaitTime
+	}
+
+	if commit.MaxDelay > 0 {
+		cfg.CommitMaxDelay = commit.MaxDelay
+	}
+
+	if commit.MaxBatch > 0 {
+		cfg.CommitMaxBatch = commit.MaxBatch
+	}
+
+	if commit.Retry > 0 {
+		cfg.Retry = commit.Retry
+	}
+
+	cfg.Panorama = f.Spec.Panorama != nil
+	cfg.DeviceGroup = ""
+	cfg.Rulebase = ""
+	cfg.SharedObjects = false
+	if f.Spec.Panorama != nil {
+		cfg.DeviceGroup = f.Spec.Panorama.DeviceGroup
+		cfg.Rulebase = f.Spec.Panorama.Rulebase
+		cfg.SharedObjects = f.Spec.Panorama.SharedObjects
+	}
+	return &cfg
+}
+
+func (c *Controller) startInstance(f *inwinv1.Firewall) error {
+	namespace, name, err := credentials.ParseSecretName(f.Spec.CredentialsSecret)
+	if err != nil {
+		return err
+	}
+
+	// The controller is only granted to read the secrets in one namespace.
+	if ns := c.cfg.FirewallSecretNamespace; ns != "" && namespace != ns {
+		return fmt.Errorf("the credentials secret %q must be in the namespace %q", f.Spec.CredentialsSecret, ns)
+	}
+
+	secret, err := c.kubeset.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	creds, err := credentials.FromSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	// The host of the secret must not silently redirect the firewall to another host.
+	if err := creds.CheckHost(f.Spec.Host); err != nil {
+		return err
+	}
+
+	client := pango.Client{
+		Hostname: f.Spec.Host,
+		Logging:  pango.LogAction | pango.LogOp,
+	}
+	creds.Apply(&client)
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	inst := &instance{
+		spec:   *f.Spec.DeepCopy(),
+		cfg:    Config(c.cfg, f),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	var device initializer
+	if f.Spec.Panorama != nil {
+		inst.pano = &pango.Panorama{Client: client}
+		device = inst.pano
+	} else {
+		inst.fw = &pango.Firewall{Client: client}
+		device = inst.fw
+	}
+
+	if err := c.initialize(device); err != nil {
+		cancel()
+		return err
+	}
+
+	if inst.pano != nil {
+		inst.client = credentials.NewClient(&inst.pano.Client)
+		inst.client.BindPanorama(inst.pano)
+	} else {
+		inst.client = credentials.NewClient(&inst.fw.Client)
+		inst.client.BindFirewall(inst.fw)
+	}
+
+	watcher := credentials.NewWatcher(c.kubeset, namespace, name, f.Spec.Host, inst.client, creds)
+	if err := watcher.Run(ctx); err != nil {
+		cancel()
+		return err
+	}
+
+	c.lock.Lock()
+	c.instances[f.Name] = inst
+	c.lock.Unlock()
+
+	version := inst.client.Versioning().String()
+	if !f.Spec.HighAvailability {
+		if err := c.runController(inst); err != nil {
+			c.stopInstance(f.Name)
+			return err
+		}
+		return c.updateStatus(f.Name, inwinv1.FirewallActive, "", version)
+	}
+
+	callbacks := &ha.Callbacks{
+		OnActive: func(status *util.HighAvailability) {
+			stateSync := status.Group.Local.StateSync
+			syncEnabled := status.Group.RunningSyncEnabled
+			runningSync := status.Group.RunningSync
+			if stateSync == "Complete" && syncEnabled == "yes" && runningSync == "synchronized" {
+				if err := c.runController(inst); err != nil {
+					c.updateStatus(f.Name, inwinv1.FirewallFailed, err.Error(), version)
+					return
+				}
+				c.updateStatus(f.Name, inwinv1.FirewallActive, "", version)
+				return
+			}
+			inst.stopController()
+			c.updateStatus(f.Name, inwinv1.FirewallPassive, "the active firewall isn't synchronized", version)
+		},
+		OnPassive: func() {
+			inst.stopController()
+			c.updateStatus(f.Name, inwinv1.FirewallPassive, "", version)
+		},
+		OnFail: func(err error) {
+			inst.stopController()
+			c.updateStatus(f.Name, inwinv1.FirewallFailed, err.Error(), version)
+		},
+	}
+	inspector := ha.NewInspector(inst.client, f.Spec.InspectorSeconds, callbacks)
+	if err := inspector.Run(ctx); err != nil {
+		c.stopInstance(f.Name)
+		return err
+	}
+	return nil
+}
+
+// runController runs the PAN controller of the firewall if it isn't running.
+func (c *Controller) runController(inst *instance) error {
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+	if inst.controller != nil {
+		return nil
+	}
+
+	glog.Infof("Starting the PAN controller of firewall '%s'.", inst.cfg.Firewall)
+
+	// The informers are created for each run of the PAN controller, so their event handlers
+	// go away with the controller when the firewall becomes passive or is deleted.
+	ctx, cancel := context.WithCancel(inst.ctx)
+	informer := blendedinformers.NewSharedInformerFactory(c.blendedset, c.resyncTime())
+	inwinInformer := inwininformers.NewSharedInformerFactory(c.inwinset, c.resyncTime())
+	kubeInformer := informers.NewSharedInformerFactory(c.kubeset, c.resyncTime())
+
+	var controller *pan.Controller
+	if inst.pano != nil {
+		onPush := func(devices []inwinv1.FirewallDeviceStatus, err error) {
+			if err := c.updateDevices(inst.cfg.Firewall, devices, err); err != nil {
+				glog.Errorf("Failed to update the devices of firewall '%s': %+v.", inst.cfg.Firewall, err)
+			}
+		}
+		controller = pan.NewPanoramaController(inst.cfg, inst.pano, inst.client, onPush, c.blendedset, c.inwinset, informer, inwinInformer, kubeInformer)
+	} else {
+		controller = pan.NewController(inst.cfg, inst.fw, inst.client, c.blendedset, c.inwinset, informer, inwinInformer, kubeInformer)
+	}
+
+	informer.Start(ctx.Done())
+	inwinInformer.Start(ctx.Done())
+	kubeInformer.Start(ctx.Done())
+	if err := controller.Run(ctx, inst.cfg.Threads); err != nil {
+		controller.Stop()
+		cancel()
+		return err
+	}
+	inst.controller = controller
+	inst.stopRun = cancel
+	return nil
+}
+
+// stopController stops the PAN controller, and a new one will be run once the firewall
+// becomes active again.
+func (inst *instance) stopController() {
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+	if inst.controller != nil {
+		inst.controller.Stop()
+		inst.stopRun()
+		inst.controller = nil
+	}
+}
+
+func (inst *instance) stop() {
+	inst.stopController()
+	inst.cancel()
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	blended "github.com/inwinstack/blended/generated/clientset/versioned"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	blendedlisterv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	informerv1 "k8s.io/client-go/informers/core/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller represents the controller of Kubernetes load balancer services
+type Controller struct {
+	cfg        *config.Config
+	blendedset blended.Interface
+	lister     listerv1.ServiceLister
+	svcLister  blendedlisterv1.ServiceLister
+	natLister  blendedlisterv1.NATLister
+	secLister  blendedlisterv1.SecurityLister
+	synced     []cache.InformerSynced
+	queue      workqueue.RateLimitingInterface
+}
+
+// NewController creates an instance of the load balancer controller
+func NewController(
+	cfg *config.Config,
+	blendedset blended.Interface,
+	informer informerv1.ServiceInformer,
+	blendedInformer blendedinformers.SharedInformerFactory) *Controller {
+	svcInformer := blendedInformer.Inwinstack().V1().Services()
+	natInformer := blendedInformer.Inwinstack().V1().NATs()
+	secInformer := blendedInformer.Inwinstack().V1().Securities()
+	controller := &Controller{
+		cfg:        cfg,
+		blendedset: blendedset,
+		lister:     informer.Lister(),
+		svcLister:  svcInformer.Lister(),
+		natLister:  natInformer.Lister(),
+		secLister:  secInformer.Lister(),
+		synced: []cache.InformerSynced{
+			informer.Informer().HasSynced,
+			svcInformer.Informer().HasSynced,
+			natInformer.Informer().HasSynced,
+			secInformer.Informer().HasSynced,
+		},
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LoadBalancers"),
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueue(new)
+		},
+		DeleteFunc: controller.enqueue,
+	})
+	return controller
+}
+
+// Run serves the load balancer controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the load balancer controller")
+	glog.Info("Waiting for the load balancer informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the load balancer controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the load balancer controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("LoadBalancer expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("LoadBalancer error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("LoadBalancer successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) reconcile(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	svc, err := c.lister.Services(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.cleanup(namespace, name)
+		}
+		return err
+	}
+
+	if !svc.ObjectMeta.DeletionTimestamp.IsZero() || !c.isNeedToSync(svc) {
+		return c.cleanup(namespace, name)
+	}
+	return c.sync(svc)
+}
+
+func (c *Controller) isNeedToSync(svc *v1.Service) bool {
+	switch svc.Annotations[constants.SyncKey] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return svc.Spec.Type == v1.ServiceTypeLoadBalancer
+}
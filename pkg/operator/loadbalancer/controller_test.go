@@ -0,0 +1,152 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const timeout = 3 * time.Second
+
+func TestLoadBalancerController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &config.Config{Threads: 2, LBSourceZone: "untrust", LBDestinationZone: "trust"}
+	blendedset := blendedfake.NewSimpleClientset()
+	kubeset := kubefake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+
+	controller := NewController(cfg, blendedset, kubeInformer.Core().V1().Services(), informer)
+	go informer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	namespace := "default"
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   namespace,
+			Annotations: map[string]string{constants.PublicIPKey: "140.23.110.10"},
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeLoadBalancer,
+			Ports: []v1.ServicePort{
+				{Protocol: v1.ProtocolTCP, Port: 80},
+				{Protocol: v1.ProtocolTCP, Port: 443},
+			},
+		},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "172.22.132.10"}},
+			},
+		},
+	}
+	_, err := kubeset.CoreV1().Services(namespace).Create(svc)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		nat, err := blendedset.InwinstackV1().NATs(namespace).Get("web-lb", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		sec, err := blendedset.InwinstackV1().Securities(namespace).Get("web-lb", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		svcList, err := blendedset.InwinstackV1().Services().List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(svcList.Items))
+		for _, s := range svcList.Items {
+			assert.Equal(t, "default.web", s.Labels[constants.OwnerKey])
+		}
+
+		assert.Equal(t, []string{"140.23.110.10"}, nat.Spec.DestinationAddresses)
+		assert.Equal(t, blendedv1.NATDatStatic, nat.Spec.DatType)
+		assert.Equal(t, "172.22.132.10", nat.Spec.DatAddress)
+		assert.Equal(t, svc.Name, nat.OwnerReferences[0].Name)
+		assert.Equal(t, []string{"140.23.110.10"}, sec.Spec.DestinationAddresses)
+		assert.Equal(t, []string{"trust"}, sec.Spec.DestinationZones)
+		assert.Equal(t, []string{"k8s-default-web-tcp80", "k8s-default-web-tcp443"}, sec.Spec.Services)
+		assert.Equal(t, blendedv1.SecurityAllow, sec.Spec.Action)
+		failed = false
+		break
+	}
+	assert.Equal(t, false, failed, "The load balancer policies haven't created.")
+
+	assert.Nil(t, kubeset.CoreV1().Services(namespace).Delete(svc.Name, nil))
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		natList, err := blendedset.InwinstackV1().NATs(namespace).List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		secList, err := blendedset.InwinstackV1().Securities(namespace).List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		svcList, err := blendedset.InwinstackV1().Services().List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		if len(natList.Items) == 0 && len(secList.Items) == 0 && len(svcList.Items) == 0 {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The load balancer policies haven't deleted.")
+
+	cancel()
+	controller.Stop()
+}
+
+func TestLoadBalancerLongNames(t *testing.T) {
+	controller := &Controller{cfg: &config.Config{}}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("a", 50),
+			Namespace: "team-a",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Protocol: v1.ProtocolTCP, Port: 80},
+				{Protocol: v1.ProtocolTCP, Port: 8080},
+			},
+		},
+	}
+
+	services := controller.newServices(svc)
+	assert.Equal(t, 2, len(services))
+	for _, s := range services {
+		assert.True(t, len(s.Name) <= naming.MaxLength, s.Name)
+	}
+	assert.NotEqual(t, services[0].Name, services[1].Name)
+
+	svc.Name = strings.Repeat("a", 63)
+	assert.Equal(t, naming.MaxLength, len(policyName(svc.Name)))
+}
@@ -0,0 +1,313 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/thoas/go-funk"
+
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func ownerValue(namespace, name string) string {
+	return fmt.Sprintf("%s.%s", namespace, name)
+}
+
+func policyName(name string) string {
+	return naming.Shorten(fmt.Sprintf("%s-lb", name), name)
+}
+
+func externalIPs(svc *v1.Service) []string {
+	ips := []string{}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			ips = append(ips, ingress.IP)
+		}
+	}
+	return append(ips, svc.Spec.ExternalIPs...)
+}
+
+func (c *Controller) newObjectMeta(svc *v1.Service, name string, owned bool) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{constants.OwnerKey: ownerValue(svc.Namespace, svc.Name)},
+	}
+	if owned {
+		meta.Namespace = svc.Namespace
+		meta.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(svc, v1.SchemeGroupVersion.WithKind("Service")),
+		}
+	}
+	return meta
+}
+
+func (c *Controller) newServices(svc *v1.Service) []*blendedv1.Service {
+	services := []*blendedv1.Service{}
+	for _, port := range svc.Spec.Ports {
+		protocol := strings.ToLower(string(port.Protocol))
+		if port.Protocol != v1.ProtocolTCP && port.Protocol != v1.ProtocolUDP {
+			glog.Warningf("LoadBalancer '%s/%s' port %d uses an unsupported protocol %s.", svc.Namespace, svc.Name, port.Port, port.Protocol)
+			continue
+		}
+
+		name := fmt.Sprintf("k8s-%s-%s-%s%d", svc.Namespace, svc.Name, protocol, port.Port)
+		name = naming.Shorten(name, fmt.Sprintf("%s/%s/%s%d", svc.Namespace, svc.Name, protocol, port.Port))
+		services = append(services, &blendedv1.Service{
+			ObjectMeta: c.newObjectMeta(svc, name, false),
+			Spec: blendedv1.ServiceSpec{
+				Protocol:        protocol,
+				DestinationPort: fmt.Sprintf("%d", port.Port),
+				Description:     fmt.Sprintf("Synced from the Kubernetes service %s/%s", svc.Namespace, svc.Name),
+			},
+		})
+	}
+	return services
+}
+
+func (c *Controller) newNAT(svc *v1.Service, publicIP, externalIP string) *blendedv1.NAT {
+	return &blendedv1.NAT{
+		ObjectMeta: c.newObjectMeta(svc, policyName(svc.Name), true),
+		Spec: blendedv1.NATSpec{
+			Type:                 blendedv1.NATIPv4,
+			SourceZones:          []string{c.cfg.LBSourceZone},
+			SourceAddresses:      []string{"any"},
+			DestinationAddresses: []string{publicIP},
+			DestinationZone:      c.cfg.LBSourceZone,
+			ToInterface:          "any",
+			Service:              "any",
+			SatType:              blendedv1.NATSatNone,
+			DatType:              blendedv1.NATDatStatic,
+			DatAddress:           externalIP,
+			Description:          fmt.Sprintf("Synced from the Kubernetes service %s/%s", svc.Namespace, svc.Name),
+		},
+	}
+}
+
+func (c *Controller) newSecurity(svc *v1.Service, destinations []string, services []*blendedv1.Service) *blendedv1.Security {
+	names := []string{}
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+
+	return &blendedv1.Security{
+		ObjectMeta: c.newObjectMeta(svc, policyName(svc.Name), true),
+		Spec: blendedv1.SecuritySpec{
+			SourceZones:          []string{c.cfg.LBSourceZone},
+			SourceAddresses:      []string{"any"},
+			SourceUsers:          []string{"any"},
+			HipProfiles:          []string{"any"},
+			DestinationZones:     []string{c.cfg.LBDestinationZone},
+			DestinationAddresses: destinations,
+			Applications:         []string{"any"},
+			Categories:           []string{"any"},
+			Services:             names,
+			Action:               blendedv1.SecurityAllow,
+			Description:          fmt.Sprintf("Synced from the Kubernetes service %s/%s", svc.Namespace, svc.Name),
+		},
+	}
+}
+
+func (c *Controller) sync(svc *v1.Service) error {
+	ips := externalIPs(svc)
+	if len(ips) == 0 {
+		glog.V(3).Infof("LoadBalancer '%s/%s' is waiting for the external IP.", svc.Namespace, svc.Name)
+		return c.cleanup(svc.Namespace, svc.Name)
+	}
+
+	services := c.newServices(svc)
+	if len(services) == 0 {
+		return c.cleanup(svc.Namespace, svc.Name)
+	}
+
+	for _, s := range services {
+		if err := c.applyService(s); err != nil {
+			return err
+		}
+	}
+
+	if err := c.cleanupServices(svc.Namespace, svc.Name, services); err != nil {
+		return err
+	}
+
+	destinations := ips
+	publicIP := svc.Annotations[constants.PublicIPKey]
+	if publicIP != "" {
+		destinations = []string{publicIP}
+		if err := c.applyNAT(c.newNAT(svc, publicIP, ips[0])); err != nil {
+			return err
+		}
+	} else if err := c.deleteNAT(svc.Namespace, svc.Name); err != nil {
+		return err
+	}
+	return c.applySecurity(c.newSecurity(svc, destinations, services))
+}
+
+func (c *Controller) applyService(svc *blendedv1.Service) error {
+	current, err := c.svcLister.Get(svc.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.blendedset.InwinstackV1().Services().Create(svc)
+			return err
+		}
+		return err
+	}
+
+	if reflect.DeepEqual(current.Spec, svc.Spec) && reflect.DeepEqual(current.Labels, svc.Labels) {
+		return nil
+	}
+
+	svcCopy := current.DeepCopy()
+	svcCopy.Labels = svc.Labels
+	svcCopy.Spec = svc.Spec
+	_, err = c.blendedset.InwinstackV1().Services().Update(svcCopy)
+	return err
+}
+
+func (c *Controller) applyNAT(nat *blendedv1.NAT) error {
+	current, err := c.natLister.NATs(nat.Namespace).Get(nat.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.blendedset.InwinstackV1().NATs(nat.Namespace).Create(nat)
+			return err
+		}
+		return err
+	}
+
+	if current.Labels[constants.OwnerKey] != nat.Labels[constants.OwnerKey] {
+		return fmt.Errorf("nat '%s/%s' already exists and isn't owned by the service", nat.Namespace, nat.Name)
+	}
+
+	if reflect.DeepEqual(current.Spec, nat.Spec) {
+		return nil
+	}
+
+	natCopy := current.DeepCopy()
+	natCopy.Spec = nat.Spec
+	_, err = c.blendedset.InwinstackV1().NATs(nat.Namespace).Update(natCopy)
+	return err
+}
+
+func (c *Controller) applySecurity(sec *blendedv1.Security) error {
+	current, err := c.secLister.Securities(sec.Namespace).Get(sec.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.blendedset.InwinstackV1().Securities(sec.Namespace).Create(sec)
+			return err
+		}
+		return err
+	}
+
+	if current.Labels[constants.OwnerKey] != sec.Labels[constants.OwnerKey] {
+		return fmt.Errorf("security '%s/%s' already exists and isn't owned by the service", sec.Namespace, sec.Name)
+	}
+
+	if reflect.DeepEqual(current.Spec, sec.Spec) {
+		return nil
+	}
+
+	secCopy := current.DeepCopy()
+	secCopy.Spec = sec.Spec
+	_, err = c.blendedset.InwinstackV1().Securities(sec.Namespace).Update(secCopy)
+	return err
+}
+
+// cleanupServices deletes the services owned by the Kubernetes service except the expected ones.
+func (c *Controller) cleanupServices(namespace, name string, expected []*blendedv1.Service) error {
+	selector := labels.SelectorFromSet(labels.Set{constants.OwnerKey: ownerValue(namespace, name)})
+	services, err := c.blendedset.InwinstackV1().Services().List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, e := range expected {
+		names = append(names, e.Name)
+	}
+
+	for _, s := range services.Items {
+		if !funk.ContainsString(names, s.Name) {
+			err := c.blendedset.InwinstackV1().Services().Delete(s.Name, nil)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Controller) deleteNAT(namespace, name string) error {
+	nat, err := c.blendedset.InwinstackV1().NATs(namespace).Get(policyName(name), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if nat.Labels[constants.OwnerKey] != ownerValue(namespace, name) {
+		return nil
+	}
+
+	err = c.blendedset.InwinstackV1().NATs(namespace).Delete(nat.Name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) deleteSecurity(namespace, name string) error {
+	sec, err := c.blendedset.InwinstackV1().Securities(namespace).Get(policyName(name), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if sec.Labels[constants.OwnerKey] != ownerValue(namespace, name) {
+		return nil
+	}
+
+	err = c.blendedset.InwinstackV1().Securities(namespace).Delete(sec.Name, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// cleanup deletes all resources that were created for the Kubernetes service. The resources are got
+// from the API server, since the caches might not have the resources that were just created.
+func (c *Controller) cleanup(namespace, name string) error {
+	if err := c.deleteSecurity(namespace, name); err != nil {
+		return err
+	}
+
+	if err := c.deleteNAT(namespace, name); err != nil {
+		return err
+	}
+	return c.cleanupServices(namespace, name, nil)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+)
+
+// subtract returns the CIDRs covering the given CIDR without the excepted ones, so an
+// ipBlock with except can be allowed as the addresses without a deny rule.
+func subtract(cidr string, excepts []string) ([]string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []*net.IPNet{network}
+	for _, except := range excepts {
+		_, excluded, err := net.ParseCIDR(except)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(excluded.IP) != len(network.IP) {
+			return nil, fmt.Errorf("the except %s is not in the CIDR %s", except, cidr)
+		}
+
+		result := []*net.IPNet{}
+		for _, block := range blocks {
+			result = append(result, exclude(block, excluded)...)
+		}
+		blocks = result
+	}
+
+	cidrs := []string{}
+	for _, block := range blocks {
+		cidrs = append(cidrs, block.String())
+	}
+	return cidrs, nil
+}
+
+// exclude returns the blocks of the network without the excluded network. The network
+// is halved down to the excluded one, and the halves not containing it are kept.
+func exclude(network, excluded *net.IPNet) []*net.IPNet {
+	ones, bits := network.Mask.Size()
+	excludedOnes, _ := excluded.Mask.Size()
+	if !network.Contains(excluded.IP) && !excluded.Contains(network.IP) {
+		return []*net.IPNet{network}
+	}
+
+	if excludedOnes <= ones {
+		return nil
+	}
+
+	blocks := []*net.IPNet{}
+	for i := ones + 1; i <= excludedOnes; i++ {
+		mask := net.CIDRMask(i, bits)
+		ip := excluded.IP.Mask(mask)
+		ip[(i-1)/8] ^= 0x80 >> uint((i-1)%8)
+		blocks = append(blocks, &net.IPNet{IP: ip, Mask: mask})
+	}
+	return blocks
+}
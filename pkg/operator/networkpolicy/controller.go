@@ -0,0 +1,186 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	blended "github.com/inwinstack/blended/generated/clientset/versioned"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	blendedlisterv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	inwinlisterv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/tags"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	informerv1 "k8s.io/client-go/informers/networking/v1"
+	listerv1 "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller represents the controller of Kubernetes network policies
+type Controller struct {
+	cfg        *config.Config
+	blendedset blended.Interface
+	inwinset   inwin.Interface
+	lister     listerv1.NetworkPolicyLister
+	svcLister  blendedlisterv1.ServiceLister
+	secLister  blendedlisterv1.SecurityLister
+	grpLister  inwinlisterv1.AddressGroupLister
+	tagger     tags.Tagger
+	synced     []cache.InformerSynced
+	queue      workqueue.RateLimitingInterface
+}
+
+// NewController creates an instance of the network policy controller
+func NewController(
+	cfg *config.Config,
+	blendedset blended.Interface,
+	inwinset inwin.Interface,
+	informer informerv1.NetworkPolicyInformer,
+	blendedInformer blendedinformers.SharedInformerFactory,
+	inwinInformer inwininformers.SharedInformerFactory) *Controller {
+	svcInformer := blendedInformer.Inwinstack().V1().Services()
+	secInformer := blendedInformer.Inwinstack().V1().Securities()
+	grpInformer := inwinInformer.Inwinstack().V1().AddressGroups()
+	controller := &Controller{
+		cfg:        cfg,
+		blendedset: blendedset,
+		inwinset:   inwinset,
+		lister:     informer.Lister(),
+		svcLister:  svcInformer.Lister(),
+		secLister:  secInformer.Lister(),
+		grpLister:  grpInformer.Lister(),
+		tagger:     tags.New(cfg.ClusterID),
+		synced: []cache.InformerSynced{
+			informer.Informer().HasSynced,
+			svcInformer.Informer().HasSynced,
+			secInformer.Informer().HasSynced,
+			grpInformer.Informer().HasSynced,
+		},
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NetworkPolicies"),
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueue(new)
+		},
+		DeleteFunc: controller.enqueue,
+	})
+	return controller
+}
+
+// Run serves the network policy controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the network policy controller")
+	glog.Info("Waiting for the network policy informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the network policy controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the network policy controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("NetworkPolicy expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("NetworkPolicy error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("NetworkPolicy successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) reconcile(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	np, err := c.lister.NetworkPolicies(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.cleanup(ownerValue(namespace, name), &translation{})
+		}
+		return err
+	}
+
+	if !np.ObjectMeta.DeletionTimestamp.IsZero() {
+		return c.cleanup(ownerValue(namespace, name), &translation{})
+	}
+
+	t, err := c.translate(np)
+	if err != nil {
+		glog.Errorf("NetworkPolicy '%s' can't be translated: %+v.", key, err)
+		return nil
+	}
+	return c.sync(np, t)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/tags"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const timeout = 3 * time.Second
+
+func TestTranslate(t *testing.T) {
+	port := intstr.FromInt(80)
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}},
+					},
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+				},
+			},
+		},
+	}
+
+	c := &Controller{tagger: tags.New("")}
+	tr, err := c.translate(np)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(tr.groups))
+	assert.Equal(t, "'k8s.ns.default' and 'k8s.label.app.web'", tr.groups[0].Spec.DynamicMatch)
+	assert.Equal(t, "'k8s.ns.default' and 'k8s.label.app.client'", tr.groups[1].Spec.DynamicMatch)
+	assert.Equal(t, 1, len(tr.services))
+	assert.Equal(t, "k8s-default-web-tcp80", tr.services[0].Name)
+	assert.Equal(t, 1, len(tr.securities))
+	assert.Equal(t, "web-ingress-0", tr.securities[0].Name)
+	assert.Equal(t, []string{"k8s-default-web-ingress-0-0", "10.0.0.0/8"}, tr.securities[0].Spec.SourceAddresses)
+	assert.Equal(t, []string{"k8s-default-web-pods"}, tr.securities[0].Spec.DestinationAddresses)
+	assert.Equal(t, []string{"k8s-default-web-tcp80"}, tr.securities[0].Spec.Services)
+
+	// The excepted ranges are left out of the allowed addresses.
+	np.Spec.Ingress[0].From[1].IPBlock.Except = []string{"10.0.0.0/10", "10.128.0.0/9"}
+	tr, err = c.translate(np)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"k8s-default-web-ingress-0-0", "10.64.0.0/10"}, tr.securities[0].Spec.SourceAddresses)
+
+	np.Spec.Ingress[0].From[1].IPBlock.Except = []string{"fd00::/8"}
+	_, err = c.translate(np)
+	assert.NotNil(t, err)
+	np.Spec.Ingress[0].From[1].IPBlock.Except = nil
+
+	// The long names are shortened to fit PAN.
+	long := np.DeepCopy()
+	long.Name = strings.Repeat("a", 63)
+	tr, err = c.translate(long)
+	assert.Nil(t, err)
+	for _, grp := range tr.groups {
+		assert.True(t, len(grp.Name) <= naming.MaxLength, grp.Name)
+	}
+	for _, svc := range tr.services {
+		assert.True(t, len(svc.Name) <= naming.MaxLength, svc.Name)
+	}
+	for _, sec := range tr.securities {
+		assert.True(t, len(sec.Name) <= naming.MaxLength, sec.Name)
+	}
+	assert.NotEqual(t, tr.groups[0].Name, tr.groups[1].Name)
+
+	named := intstr.FromString("http")
+	np.Spec.Ingress[0].Ports = []networkingv1.NetworkPolicyPort{{Port: &named}}
+	_, err = c.translate(np)
+	assert.NotNil(t, err)
+}
+
+func TestSubtract(t *testing.T) {
+	cidrs, err := subtract("10.0.0.0/8", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8"}, cidrs)
+
+	cidrs, err = subtract("10.0.0.0/8", []string{"10.1.0.0/16"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"10.128.0.0/9", "10.64.0.0/10", "10.32.0.0/11", "10.16.0.0/12",
+		"10.8.0.0/13", "10.4.0.0/14", "10.2.0.0/15", "10.0.0.0/16",
+	}, cidrs)
+
+	cidrs, err = subtract("192.168.0.0/24", []string{"192.168.0.0/24"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, cidrs)
+
+	cidrs, err = subtract("192.168.0.0/24", []string{"172.16.0.0/12"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.0.0/24"}, cidrs)
+
+	cidrs, err = subtract("fd00::/8", []string{"fd00::/9"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"fd80::/9"}, cidrs)
+
+	_, err = subtract("10.0.0.0/8", []string{"invalid"})
+	assert.NotNil(t, err)
+}
+
+func TestNetworkPolicyController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &config.Config{Threads: 2}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	kubeset := kubefake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+
+	controller := NewController(cfg, blendedset, inwinset, kubeInformer.Networking().V1().NetworkPolicies(), informer, inwinInformer)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	namespace := "default"
+	port := intstr.FromInt(443)
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{Ports: []networkingv1.NetworkPolicyPort{{Port: &port}}},
+			},
+		},
+	}
+	_, err := kubeset.NetworkingV1().NetworkPolicies(namespace).Create(np)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		sec, err := blendedset.InwinstackV1().Securities(namespace).Get("web-ingress-0", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		grp, err := inwinset.InwinstackV1().AddressGroups().Get("k8s-default-web-pods", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		svc, err := blendedset.InwinstackV1().Services().Get("k8s-default-web-tcp443", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, "default.web", grp.Labels[constants.NetworkPolicyKey])
+		assert.Equal(t, "'k8s.ns.default' and 'k8s.label.app.web'", grp.Spec.DynamicMatch)
+		assert.Equal(t, "443", svc.Spec.DestinationPort)
+		assert.Equal(t, []string{"any"}, sec.Spec.SourceAddresses)
+		assert.Equal(t, []string{grp.Name}, sec.Spec.DestinationAddresses)
+		assert.Equal(t, []string{svc.Name}, sec.Spec.Services)
+		assert.Equal(t, blendedv1.SecurityAllow, sec.Spec.Action)
+		assert.Equal(t, np.Name, sec.OwnerReferences[0].Name)
+		failed = false
+		break
+	}
+	assert.Equal(t, false, failed, "The network policy resources haven't created.")
+
+	assert.Nil(t, kubeset.NetworkingV1().NetworkPolicies(namespace).Delete(np.Name, nil))
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		secList, err := blendedset.InwinstackV1().Securities(namespace).List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		svcList, err := blendedset.InwinstackV1().Services().List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		grpList, err := inwinset.InwinstackV1().AddressGroups().List(metav1.ListOptions{})
+		assert.Nil(t, err)
+		if len(secList.Items) == 0 && len(svcList.Items) == 0 && len(grpList.Items) == 0 {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The network policy resources haven't deleted.")
+
+	cancel()
+	controller.Stop()
+}
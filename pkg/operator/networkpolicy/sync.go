@@ -0,0 +1,182 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"fmt"
+	"reflect"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (c *Controller) sync(np *networkingv1.NetworkPolicy, t *translation) error {
+	for _, grp := range t.groups {
+		if err := c.applyAddressGroup(grp); err != nil {
+			return err
+		}
+	}
+
+	for _, svc := range t.services {
+		if err := c.applyService(svc); err != nil {
+			return err
+		}
+	}
+
+	for _, sec := range t.securities {
+		if err := c.applySecurity(sec); err != nil {
+			return err
+		}
+	}
+	return c.cleanup(ownerValue(np.Namespace, np.Name), t)
+}
+
+func (c *Controller) applyAddressGroup(grp *inwinv1.AddressGroup) error {
+	current, err := c.grpLister.Get(grp.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.inwinset.InwinstackV1().AddressGroups().Create(grp)
+			return err
+		}
+		return err
+	}
+
+	if current.Labels[constants.NetworkPolicyKey] != grp.Labels[constants.NetworkPolicyKey] {
+		return fmt.Errorf("address group '%s' already exists and isn't owned by the network policy", grp.Name)
+	}
+
+	if reflect.DeepEqual(current.Spec, grp.Spec) {
+		return nil
+	}
+
+	grpCopy := current.DeepCopy()
+	grpCopy.Spec = grp.Spec
+	_, err = c.inwinset.InwinstackV1().AddressGroups().Update(grpCopy)
+	return err
+}
+
+func (c *Controller) applyService(svc *blendedv1.Service) error {
+	current, err := c.svcLister.Get(svc.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.blendedset.InwinstackV1().Services().Create(svc)
+			return err
+		}
+		return err
+	}
+
+	if current.Labels[constants.NetworkPolicyKey] != svc.Labels[constants.NetworkPolicyKey] {
+		return fmt.Errorf("service '%s' already exists and isn't owned by the network policy", svc.Name)
+	}
+
+	if reflect.DeepEqual(current.Spec, svc.Spec) {
+		return nil
+	}
+
+	svcCopy := current.DeepCopy()
+	svcCopy.Spec = svc.Spec
+	_, err = c.blendedset.InwinstackV1().Services().Update(svcCopy)
+	return err
+}
+
+func (c *Controller) applySecurity(sec *blendedv1.Security) error {
+	current, err := c.secLister.Securities(sec.Namespace).Get(sec.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err := c.blendedset.InwinstackV1().Securities(sec.Namespace).Create(sec)
+			return err
+		}
+		return err
+	}
+
+	if current.Labels[constants.NetworkPolicyKey] != sec.Labels[constants.NetworkPolicyKey] {
+		return fmt.Errorf("security '%s/%s' already exists and isn't owned by the network policy", sec.Namespace, sec.Name)
+	}
+
+	if reflect.DeepEqual(current.Spec, sec.Spec) {
+		return nil
+	}
+
+	secCopy := current.DeepCopy()
+	secCopy.Spec = sec.Spec
+	_, err = c.blendedset.InwinstackV1().Securities(sec.Namespace).Update(secCopy)
+	return err
+}
+
+// cleanup deletes the resources owned by the network policy except the translated ones.
+func (c *Controller) cleanup(owner string, t *translation) error {
+	selector := labels.SelectorFromSet(labels.Set{constants.NetworkPolicyKey: owner})
+	expected := map[string]bool{}
+	for _, sec := range t.securities {
+		expected["sec/"+sec.Namespace+"/"+sec.Name] = true
+	}
+	for _, svc := range t.services {
+		expected["svc/"+svc.Name] = true
+	}
+	for _, grp := range t.groups {
+		expected["grp/"+grp.Name] = true
+	}
+
+	// The resources are listed from the API server instead of the caches, which might not
+	// have the resources that were just created. Securities go first, since they refer to
+	// the services and address groups.
+	opts := metav1.ListOptions{LabelSelector: selector.String()}
+	securities, err := c.blendedset.InwinstackV1().Securities(metav1.NamespaceAll).List(opts)
+	if err != nil {
+		return err
+	}
+	for _, sec := range securities.Items {
+		if !expected["sec/"+sec.Namespace+"/"+sec.Name] {
+			err := c.blendedset.InwinstackV1().Securities(sec.Namespace).Delete(sec.Name, nil)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	services, err := c.blendedset.InwinstackV1().Services().List(opts)
+	if err != nil {
+		return err
+	}
+	for _, svc := range services.Items {
+		if !expected["svc/"+svc.Name] {
+			err := c.blendedset.InwinstackV1().Services().Delete(svc.Name, nil)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	groups, err := c.inwinset.InwinstackV1().AddressGroups().List(opts)
+	if err != nil {
+		return err
+	}
+	for _, grp := range groups.Items {
+		if !expected["grp/"+grp.Name] {
+			err := c.inwinset.InwinstackV1().AddressGroups().Delete(grp.Name, nil)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/tags"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const any = "any"
+
+// translation represents the resources translated from a network policy.
+type translation struct {
+	groups     []*inwinv1.AddressGroup
+	services   []*blendedv1.Service
+	securities []*blendedv1.Security
+}
+
+func ownerValue(namespace, name string) string {
+	return fmt.Sprintf("%s.%s", namespace, name)
+}
+
+// objectName returns the name of an object translated from the network policy, such as
+// k8s-default-web-pods, which is shortened to fit PAN.
+func objectName(np *networkingv1.NetworkPolicy, suffix string) string {
+	name := fmt.Sprintf("k8s-%s-%s-%s", np.Namespace, np.Name, suffix)
+	return naming.Shorten(name, fmt.Sprintf("%s/%s/%s", np.Namespace, np.Name, suffix))
+}
+
+// securityName returns the name of a security translated from the network policy, such as
+// web-ingress-0, which is shortened to fit PAN.
+func securityName(np *networkingv1.NetworkPolicy, suffix string) string {
+	name := fmt.Sprintf("%s-%s", np.Name, suffix)
+	return naming.Shorten(name, fmt.Sprintf("%s/%s/%s", np.Namespace, np.Name, suffix))
+}
+
+func newObjectMeta(np *networkingv1.NetworkPolicy, name string, owned bool) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{constants.NetworkPolicyKey: ownerValue(np.Namespace, np.Name)},
+	}
+	if owned {
+		meta.Namespace = np.Namespace
+		meta.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(np, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy")),
+		}
+	}
+	return meta
+}
+
+// labelTerms returns the match terms of the given label selector.
+func labelTerms(sel *metav1.LabelSelector, tagFunc func(key, value string) string) ([]string, error) {
+	terms := []string{}
+	keys := []string{}
+	for key := range sel.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		terms = append(terms, tags.Quote(tagFunc(key, sel.MatchLabels[key])))
+	}
+
+	for _, expr := range sel.MatchExpressions {
+		if expr.Operator != metav1.LabelSelectorOpIn {
+			return nil, fmt.Errorf("the selector operator %s is not supported", expr.Operator)
+		}
+
+		values := []string{}
+		for _, value := range expr.Values {
+			values = append(values, tags.Quote(tagFunc(expr.Key, value)))
+		}
+		terms = append(terms, fmt.Sprintf("(%s)", strings.Join(values, " or ")))
+	}
+	return terms, nil
+}
+
+// podMatch returns the dynamic match of the pods selected in the namespace.
+func (c *Controller) podMatch(namespace string, sel *metav1.LabelSelector) (string, error) {
+	terms, err := labelTerms(sel, c.tagger.Label)
+	if err != nil {
+		return "", err
+	}
+	terms = append([]string{tags.Quote(c.tagger.Namespace(namespace))}, terms...)
+	return strings.Join(terms, " and "), nil
+}
+
+// peerMatch returns the dynamic match of the pods selected by the peer. An empty
+// match means that the peer selects all pods.
+func (c *Controller) peerMatch(namespace string, peer networkingv1.NetworkPolicyPeer) (string, error) {
+	if peer.NamespaceSelector == nil {
+		return c.podMatch(namespace, peer.PodSelector)
+	}
+
+	terms, err := labelTerms(peer.NamespaceSelector, c.tagger.NamespaceLabel)
+	if err != nil {
+		return "", err
+	}
+
+	if peer.PodSelector != nil {
+		podTerms, err := labelTerms(peer.PodSelector, c.tagger.Label)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, podTerms...)
+	}
+	return strings.Join(terms, " and "), nil
+}
+
+func (c *Controller) newAddressGroup(np *networkingv1.NetworkPolicy, name, match string) *inwinv1.AddressGroup {
+	return &inwinv1.AddressGroup{
+		ObjectMeta: newObjectMeta(np, name, false),
+		Spec: inwinv1.AddressGroupSpec{
+			DynamicMatch: match,
+			Description:  fmt.Sprintf("Synced from the Kubernetes network policy %s/%s", np.Namespace, np.Name),
+		},
+	}
+}
+
+// peerAddresses translates the peers into addresses, and appends the address groups to the translation.
+func (c *Controller) peerAddresses(np *networkingv1.NetworkPolicy, prefix string, peers []networkingv1.NetworkPolicyPeer, t *translation) ([]string, error) {
+	if len(peers) == 0 {
+		return []string{any}, nil
+	}
+
+	addresses := []string{}
+	for i, peer := range peers {
+		if peer.IPBlock != nil {
+			cidrs, err := subtract(peer.IPBlock.CIDR, peer.IPBlock.Except)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, cidrs...)
+			continue
+		}
+
+		match, err := c.peerMatch(np.Namespace, peer)
+		if err != nil {
+			return nil, err
+		}
+
+		if match == "" {
+			return []string{any}, nil
+		}
+
+		name := objectName(np, fmt.Sprintf("%s-%d", prefix, i))
+		t.groups = append(t.groups, c.newAddressGroup(np, name, match))
+		addresses = append(addresses, name)
+	}
+	return addresses, nil
+}
+
+// portServices translates the ports into services, and appends the services to the translation.
+func (c *Controller) portServices(np *networkingv1.NetworkPolicy, ports []networkingv1.NetworkPolicyPort, t *translation) ([]string, error) {
+	if len(ports) == 0 {
+		return []string{any}, nil
+	}
+
+	names := []string{}
+	for _, port := range ports {
+		protocol := v1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+
+		if protocol != v1.ProtocolTCP && protocol != v1.ProtocolUDP {
+			return nil, fmt.Errorf("the protocol %s is not supported", protocol)
+		}
+
+		if port.Port == nil {
+			return nil, fmt.Errorf("the port must be set for the protocol %s", protocol)
+		}
+
+		if port.Port.IntVal == 0 {
+			return nil, fmt.Errorf("the named port %s is not supported", port.Port.StrVal)
+		}
+
+		p := strings.ToLower(string(protocol))
+		name := objectName(np, fmt.Sprintf("%s%d", p, port.Port.IntVal))
+		if !containsService(t.services, name) {
+			t.services = append(t.services, &blendedv1.Service{
+				ObjectMeta: newObjectMeta(np, name, false),
+				Spec: blendedv1.ServiceSpec{
+					Protocol:        p,
+					DestinationPort: fmt.Sprintf("%d", port.Port.IntVal),
+					Description:     fmt.Sprintf("Synced from the Kubernetes network policy %s/%s", np.Namespace, np.Name),
+				},
+			})
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func containsService(services []*blendedv1.Service, name string) bool {
+	for _, s := range services {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) newSecurity(np *networkingv1.NetworkPolicy, name string, sources, destinations, services []string) *blendedv1.Security {
+	return &blendedv1.Security{
+		ObjectMeta: newObjectMeta(np, name, true),
+		Spec: blendedv1.SecuritySpec{
+			SourceZones:          []string{any},
+			SourceAddresses:      sources,
+			SourceUsers:          []string{any},
+			HipProfiles:          []string{any},
+			DestinationZones:     []string{any},
+			DestinationAddresses: destinations,
+			Applications:         []string{any},
+			Categories:           []string{any},
+			Services:             services,
+			Action:               blendedv1.SecurityAllow,
+			Description:          fmt.Sprintf("Synced from the Kubernetes network policy %s/%s", np.Namespace, np.Name),
+		},
+	}
+}
+
+func hasPolicyType(np *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(np.Spec.PolicyTypes) == 0 {
+		// Ingress is always set, and egress is set when there are egress rules.
+		return policyType == networkingv1.PolicyTypeIngress || len(np.Spec.Egress) != 0
+	}
+
+	for _, t := range np.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+// translate translates the network policy into address groups, services and allowed securities.
+func (c *Controller) translate(np *networkingv1.NetworkPolicy) (*translation, error) {
+	t := &translation{}
+	match, err := c.podMatch(np.Namespace, &np.Spec.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := objectName(np, "pods")
+	t.groups = append(t.groups, c.newAddressGroup(np, pods, match))
+
+	if hasPolicyType(np, networkingv1.PolicyTypeIngress) {
+		for i, rule := range np.Spec.Ingress {
+			sources, err := c.peerAddresses(np, fmt.Sprintf("ingress-%d", i), rule.From, t)
+			if err != nil {
+				return nil, err
+			}
+
+			services, err := c.portServices(np, rule.Ports, t)
+			if err != nil {
+				return nil, err
+			}
+
+			name := securityName(np, fmt.Sprintf("ingress-%d", i))
+			t.securities = append(t.securities, c.newSecurity(np, name, sources, []string{pods}, services))
+		}
+	}
+
+	if hasPolicyType(np, networkingv1.PolicyTypeEgress) {
+		for i, rule := range np.Spec.Egress {
+			destinations, err := c.peerAddresses(np, fmt.Sprintf("egress-%d", i), rule.To, t)
+			if err != nil {
+				return nil, err
+			}
+
+			services, err := c.portServices(np, rule.Ports, t)
+			if err != nil {
+				return nil, err
+			}
+
+			name := securityName(np, fmt.Sprintf("egress-%d", i))
+			t.securities = append(t.securities, c.newSecurity(np, name, []string{pods}, destinations, services))
+		}
+	}
+	return t, nil
+}
@@ -21,11 +21,23 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/golang/glog"
 	blended "github.com/inwinstack/blended/generated/clientset/versioned"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	"github.com/inwinstack/pa-controller/pkg/edl"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/operator/firewall"
+	"github.com/inwinstack/pa-controller/pkg/operator/loadbalancer"
+	"github.com/inwinstack/pa-controller/pkg/operator/networkpolicy"
 	"github.com/inwinstack/pa-controller/pkg/operator/pan"
+	"github.com/inwinstack/pa-controller/pkg/operator/pod"
+	"github.com/inwinstack/pa-controller/pkg/webhook"
 	"github.com/inwinstack/pango"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 )
 
 const defaultSyncTime = time.Second * 30
@@ -33,34 +45,150 @@ const defaultSyncTime = time.Second * 30
 // Operator represents an operator context
 type Operator struct {
 	clientset      blended.Interface
+	inwinset       inwin.Interface
 	informer       blendedinformers.SharedInformerFactory
+	inwinInformer  inwininformers.SharedInformerFactory
+	kubeInformer   informers.SharedInformerFactory
 	cfg            *config.Config
 	mainController *pan.Controller
+	fwController   *firewall.Controller
+	lbController   *loadbalancer.Controller
+	npController   *networkpolicy.Controller
+	podController  *pod.Controller
+	edlServer      *edl.Server
+	webhookServer  *webhook.Server
 }
 
-// New creates an instance of the operator
-func New(cfg *config.Config, fw *pango.Firewall, clientset blended.Interface) *Operator {
+// New creates an instance of the operator. The client is the client of the default firewall or
+// Panorama, and it's nil when the default firewall is disabled.
+func New(
+	cfg *config.Config,
+	fw *pango.Firewall,
+	pano *pango.Panorama,
+	client *credentials.Client,
+	clientset blended.Interface,
+	inwinset inwin.Interface,
+	kubeset kubernetes.Interface) *Operator {
 	t := defaultSyncTime
 	if cfg.SyncSec > 30 {
 		t = time.Second * time.Duration(cfg.SyncSec)
 	}
 
-	o := &Operator{cfg: cfg, clientset: clientset}
+	o := &Operator{cfg: cfg, clientset: clientset, inwinset: inwinset}
 	o.informer = blendedinformers.NewSharedInformerFactory(clientset, t)
-	o.mainController = pan.NewController(cfg, fw, clientset, o.informer)
+	o.inwinInformer = inwininformers.NewSharedInformerFactory(inwinset, t)
+	o.kubeInformer = informers.NewSharedInformerFactory(kubeset, t)
+	o.fwController = firewall.NewController(cfg, clientset, inwinset, kubeset, o.inwinInformer)
+
+	// The default firewall is optional when the firewalls are managed by Firewall resources.
+	switch {
+	case fw != nil:
+		o.mainController = pan.NewController(cfg, fw, client, clientset, inwinset, o.informer, o.inwinInformer, o.kubeInformer)
+	case pano != nil:
+		o.mainController = pan.NewPanoramaController(cfg, pano, client, nil, clientset, inwinset, o.informer, o.inwinInformer, o.kubeInformer)
+	}
+	if cfg.SyncLoadBalancer {
+		o.lbController = loadbalancer.NewController(cfg, clientset, o.kubeInformer.Core().V1().Services(), o.informer)
+	}
+
+	if cfg.SyncNetworkPolicy {
+		o.npController = networkpolicy.NewController(cfg, clientset, inwinset, o.kubeInformer.Networking().V1().NetworkPolicies(), o.informer, o.inwinInformer)
+	}
+
+	// The pod tags are registered to the User-ID of the default firewall, so they aren't synced
+	// in the dry-run mode, to Panorama, or to the firewalls of Firewall resources.
+	if cfg.SyncPodTags {
+		switch {
+		case cfg.DryRun:
+			glog.Warning("The pod tags aren't registered in the dry-run mode.")
+		case fw == nil:
+			glog.Warning("The pod tags are only registered to the default firewall, ignoring --sync-pod-tags for Panorama or Firewall resources.")
+		default:
+			o.podController = pod.NewController(cfg, fw.UserId, o.kubeInformer.Core().V1().Pods(), o.kubeInformer.Core().V1().Namespaces())
+		}
+	}
+
+	if cfg.EDLListenAddress != "" {
+		o.edlServer = edl.NewServer(cfg.EDLListenAddress, o.kubeInformer, o.inwinInformer)
+	}
+
+	if cfg.WebhookListenAddress != "" {
+		o.webhookServer = webhook.NewServer(cfg, kubeset, o.kubeInformer, o.inwinInformer)
+	}
 	return o
 }
 
 // Run serves an isntance of the operator
 func (o *Operator) Run(ctx context.Context) error {
 	go o.informer.Start(ctx.Done())
-	if err := o.mainController.Run(ctx, o.cfg.Threads); err != nil {
-		return fmt.Errorf("failed to run main controller: %s", err.Error())
+	go o.inwinInformer.Start(ctx.Done())
+	go o.kubeInformer.Start(ctx.Done())
+	if o.mainController != nil {
+		if err := o.mainController.Run(ctx, o.cfg.Threads); err != nil {
+			return fmt.Errorf("failed to run main controller: %s", err.Error())
+		}
+	}
+
+	if err := o.fwController.Run(ctx, o.cfg.Threads); err != nil {
+		return fmt.Errorf("failed to run firewall controller: %s", err.Error())
+	}
+
+	if o.lbController != nil {
+		if err := o.lbController.Run(ctx, o.cfg.Threads); err != nil {
+			return fmt.Errorf("failed to run load balancer controller: %s", err.Error())
+		}
+	}
+
+	if o.npController != nil {
+		if err := o.npController.Run(ctx, o.cfg.Threads); err != nil {
+			return fmt.Errorf("failed to run network policy controller: %s", err.Error())
+		}
+	}
+
+	if o.podController != nil {
+		if err := o.podController.Run(ctx, o.cfg.Threads); err != nil {
+			return fmt.Errorf("failed to run pod IP tag controller: %s", err.Error())
+		}
+	}
+
+	if o.edlServer != nil {
+		if err := o.edlServer.Run(ctx); err != nil {
+			return fmt.Errorf("failed to run external dynamic list server: %s", err.Error())
+		}
+	}
+
+	if o.webhookServer != nil {
+		if err := o.webhookServer.Run(ctx); err != nil {
+			return fmt.Errorf("failed to run webhook server: %s", err.Error())
+		}
 	}
 	return nil
 }
 
 // Stop stops the main controller
 func (o *Operator) Stop() {
-	o.mainController.Stop()
+	if o.mainController != nil {
+		o.mainController.Stop()
+	}
+
+	o.fwController.Stop()
+	if o.lbController != nil {
+		o.lbController.Stop()
+	}
+
+	if o.npController != nil {
+		o.npController.Stop()
+	}
+
+	if o.podController != nil {
+		o.podController.Stop()
+	}
+
+	if o.edlServer != nil {
+		o.edlServer.Stop()
+	}
+
+	if o.webhookServer != nil {
+		o.webhookServer.Stop()
+	}
 }
@@ -24,10 +24,17 @@ import (
 
 	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
 	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
 	"github.com/inwinstack/pango"
 	"github.com/inwinstack/pango/objs"
+	"github.com/inwinstack/pango/objs/addr"
+	"github.com/inwinstack/pango/objs/addrgrp"
+	"github.com/inwinstack/pango/objs/edl"
 	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/objs/srvcgrp"
 	"github.com/inwinstack/pango/poli"
 	"github.com/inwinstack/pango/poli/nat"
 	"github.com/inwinstack/pango/poli/security"
@@ -37,6 +44,7 @@ import (
 	extensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
 type customResource struct {
@@ -84,11 +92,17 @@ func TestOperator(t *testing.T) {
 			Security: &security.FwSecurity{},
 		},
 		Objects: &objs.FwObjs{
-			Services: &srvc.FwSrvc{},
+			Services:     &srvc.FwSrvc{},
+			Address:      &addr.FwAddr{},
+			AddressGroup: &addrgrp.FwAddrGrp{},
+			ServiceGroup: &srvcgrp.FwSrvcGrp{},
+			Edl:          &edl.FwEdl{},
 		},
 	}
-	cfg := &config.Config{Threads: 2, Retry: 5}
+	cfg := &config.Config{Threads: 2, Retry: 5, SyncLoadBalancer: true, SyncNetworkPolicy: true, EDLListenAddress: "127.0.0.1:0"}
 	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	kubeset := kubefake.NewSimpleClientset()
 	extensionsClient := extensionsfake.NewSimpleClientset()
 
 	resources := []customResource{
@@ -116,6 +130,46 @@ func TestOperator(t *testing.T) {
 			Version: blendedv1.Version,
 			Scope:   apiextensionsv1beta1.NamespaceScoped,
 		},
+		{
+			Name:    "address",
+			Plural:  "addresses",
+			Kind:    reflect.TypeOf(inwinv1.Address{}).Name(),
+			Group:   inwinv1.CustomResourceGroup,
+			Version: inwinv1.Version,
+			Scope:   apiextensionsv1beta1.ClusterScoped,
+		},
+		{
+			Name:    "addressgroup",
+			Plural:  "addressgroups",
+			Kind:    reflect.TypeOf(inwinv1.AddressGroup{}).Name(),
+			Group:   inwinv1.CustomResourceGroup,
+			Version: inwinv1.Version,
+			Scope:   apiextensionsv1beta1.ClusterScoped,
+		},
+		{
+			Name:    "servicegroup",
+			Plural:  "servicegroups",
+			Kind:    reflect.TypeOf(inwinv1.ServiceGroup{}).Name(),
+			Group:   inwinv1.CustomResourceGroup,
+			Version: inwinv1.Version,
+			Scope:   apiextensionsv1beta1.ClusterScoped,
+		},
+		{
+			Name:    "externaldynamiclist",
+			Plural:  "externaldynamiclists",
+			Kind:    reflect.TypeOf(inwinv1.ExternalDynamicList{}).Name(),
+			Group:   inwinv1.CustomResourceGroup,
+			Version: inwinv1.Version,
+			Scope:   apiextensionsv1beta1.ClusterScoped,
+		},
+		{
+			Name:    "firewall",
+			Plural:  "firewalls",
+			Kind:    reflect.TypeOf(inwinv1.Firewall{}).Name(),
+			Group:   inwinv1.CustomResourceGroup,
+			Version: inwinv1.Version,
+			Scope:   apiextensionsv1beta1.ClusterScoped,
+		},
 	}
 	for _, res := range resources {
 		assert.Nil(t, createCRD(extensionsClient, res))
@@ -125,7 +179,7 @@ func TestOperator(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, len(resources), len(crds.Items))
 
-	op := New(cfg, fw, blendedset)
+	op := New(cfg, fw, nil, credentials.NewClient(&fw.Client), blendedset, inwinset, kubeset)
 	assert.NotNil(t, op)
 	assert.Nil(t, op.Run(ctx))
 
@@ -0,0 +1,74 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package address
+
+import (
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/addr"
+)
+
+func (c *Controller) newAddressObject(a *inwinv1.Address) *addr.Entry {
+	return &addr.Entry{
+		Name:        a.Name,
+		Type:        a.Spec.Type,
+		Value:       a.Spec.Value,
+		Description: a.Spec.Description,
+		Tags:        ownership.AddTags(a.Spec.Tags, c.cfg.ClusterID),
+	}
+}
+
+func (c *Controller) isExistingAddressObject(a *inwinv1.Address) bool {
+	if entry, err := c.addr.Get(c.cfg.Vsys, a.Name); err == nil {
+		if len(entry.Name) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) updateAddressObject(a *inwinv1.Address) error {
+	entry := c.newAddressObject(a)
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.addr.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindAddress, a.ObjectMeta)
+	return nil
+}
+
+func (c *Controller) deleteAddressObject(a *inwinv1.Address) error {
+	if !c.isExistingAddressObject(a) {
+		return nil
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.addr.Delete(c.cfg.Vsys, a.Name); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindAddress, a.ObjectMeta)
+	return nil
+}
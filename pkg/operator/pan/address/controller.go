@@ -0,0 +1,283 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package address
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thoas/go-funk"
+
+	"github.com/golang/glog"
+	"github.com/inwinstack/blended/constants"
+	"github.com/inwinstack/blended/k8sutil"
+	"github.com/inwinstack/blended/util"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	informerv1 "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions/inwinstack/v1"
+	listerv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+	"github.com/inwinstack/pango/objs/addr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Client represents the address objects of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (addr.Entry, error)
+	Edit(vsys string, e addr.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
+// Controller represents the controller of address
+type Controller struct {
+	cfg      *config.Config
+	addr     Client
+	inwinset inwin.Interface
+	lister   listerv1.AddressLister
+	synced   cache.InformerSynced
+	queue    workqueue.RateLimitingInterface
+
+	commit chan *commit.Object
+	locker *lock.Locker
+}
+
+// NewController creates an instance of the address controller
+func NewController(
+	cfg *config.Config,
+	addr Client,
+	inwinset inwin.Interface,
+	informer informerv1.AddressInformer,
+	commit chan *commit.Object,
+	locker *lock.Locker) *Controller {
+	controller := &Controller{
+		cfg:      cfg,
+		addr:     addr,
+		inwinset: inwinset,
+		lister:   informer.Lister(),
+		synced:   informer.Informer().HasSynced,
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AddressObjects"),
+		commit:   commit,
+		locker:   locker,
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oo := old.(*inwinv1.Address)
+			no := new.(*inwinv1.Address)
+			k8sutil.MakeNeedToUpdate(&no.ObjectMeta, oo.Spec, no.Spec)
+			controller.enqueue(no)
+		},
+	})
+	return controller
+}
+
+// Run serves the address controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the address controller")
+	glog.Info("Waiting for the address informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the address controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the address controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("Address expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("Address error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("Address successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) reconcile(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	address, err := c.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("address '%s' in work queue no longer exists", key))
+			return err
+		}
+		return err
+	}
+
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(address.ObjectMeta, address.Spec.FirewallRef) != c.cfg.Firewall {
+		return nil
+	}
+
+	if !address.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := c.cleanup(address); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := c.checkAndUdateFinalizer(address); err != nil {
+		return err
+	}
+
+	need := k8sutil.IsNeedToUpdate(address.ObjectMeta)
+	if address.Status.Phase != inwinv1.AddressActive || need {
+		if address.Status.Phase == inwinv1.AddressFailed {
+			t := util.SubtractNowTime(address.Status.LastUpdateTime.Time)
+			if t.Seconds() <= float64(c.cfg.SyncSec) && !need {
+				return nil
+			}
+		}
+		if err := c.createOrUpdate(address); err != nil {
+			return c.makeFailed(address, err)
+		}
+		return nil
+	}
+
+	if address.Status.Phase == inwinv1.AddressActive && !c.isExistingAddressObject(address) {
+		if err := c.createOrUpdate(address); err != nil {
+			return c.makeFailed(address, err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) checkAndUdateFinalizer(addr *inwinv1.Address) error {
+	addrCopy := addr.DeepCopy()
+	ok := funk.ContainsString(addrCopy.Finalizers, constants.CustomFinalizer)
+	if addr.Status.Phase == inwinv1.AddressActive && !ok {
+		k8sutil.AddFinalizer(&addrCopy.ObjectMeta, constants.CustomFinalizer)
+		if _, err := c.inwinset.InwinstackV1().Addresses().Update(addrCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) makeFailed(addr *inwinv1.Address, e error) error {
+	addrCopy := addr.DeepCopy()
+	addrCopy.Status.Reason = e.Error()
+	addrCopy.Status.Phase = inwinv1.AddressFailed
+	addrCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(addrCopy.Annotations, constants.NeedUpdateKey)
+	if _, err := c.inwinset.InwinstackV1().Addresses().Update(addrCopy); err != nil {
+		return err
+	}
+	glog.Errorf("Address got an error:%+v.", e)
+	return nil
+}
+
+func (c *Controller) createOrUpdate(addr *inwinv1.Address) error {
+	if c.cfg.DryRun {
+		return c.planAddressObject(addr, false)
+	}
+
+	addrCopy := addr.DeepCopy()
+	if err := c.updateAddressObject(addrCopy); err != nil {
+		return err
+	}
+
+	addrCopy.Status.Reason = ""
+	addrCopy.Status.Phase = inwinv1.AddressActive
+	addrCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(addrCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&addrCopy.ObjectMeta)
+	k8sutil.AddFinalizer(&addrCopy.ObjectMeta, constants.CustomFinalizer)
+	if _, err := c.inwinset.InwinstackV1().Addresses().Update(addrCopy); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) cleanup(addr *inwinv1.Address) error {
+	if c.cfg.DryRun {
+		return c.planAddressObject(addr, true)
+	}
+
+	addrCopy := addr.DeepCopy()
+	if err := c.deleteAddressObject(addrCopy); err != nil {
+		return err
+	}
+
+	k8sutil.RemoveFinalizer(&addrCopy.ObjectMeta, constants.CustomFinalizer)
+	addrCopy.Status.Phase = inwinv1.AddressTerminating
+	if _, err := c.inwinset.InwinstackV1().Addresses().Update(addrCopy); err != nil {
+		return err
+	}
+	return nil
+}
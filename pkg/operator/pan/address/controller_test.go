@@ -0,0 +1,179 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package address
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/blended/constants"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/addr"
+	"github.com/inwinstack/pango/testdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const timeout = 3 * time.Second
+
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
+	for {
+		select {
+		case c := <-commit:
+			assert.NotNil(t, c)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func TestAddressController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, ClusterID: "k8s-a"}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwAddr := &addr.FwAddr{}
+	fwAddr.Initialize(mc)
+
+	controller := NewController(cfg, fwAddr, inwinset, informer.Inwinstack().V1().Addresses(), commit, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	a := &inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-web",
+		},
+		Spec: inwinv1.AddressSpec{
+			Type:        inwinv1.AddressIPNetmask,
+			Value:       "172.22.132.10/32",
+			Description: "Test address",
+		},
+	}
+
+	mc.Reset()
+	mc.AddResp("")
+	_, err := inwinset.InwinstackV1().Addresses().Create(a)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ga, err := inwinset.InwinstackV1().Addresses().Get(a.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+
+		mc.AddResp(mc.Elm)
+		entry, err := fwAddr.Get(cfg.Vsys, ga.Name)
+		assert.Nil(t, err)
+		if ga.Status.Phase == inwinv1.AddressActive && entry.Name != "" {
+			assert.Equal(t, []string{constants.CustomFinalizer}, ga.Finalizers)
+			assert.Equal(t, ga.Name, entry.Name)
+			assert.Equal(t, ga.Spec.Type, entry.Type)
+			assert.Equal(t, ga.Spec.Value, entry.Value)
+			assert.Equal(t, ga.Spec.Description, entry.Description)
+			assert.Equal(t, ownership.Tags(cfg.ClusterID), entry.Tags)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address object hasn't created.")
+
+	cancel()
+	controller.Stop()
+
+	// The fake clientset doesn't honor finalizers, so run the cleanup directly.
+	ga, err := inwinset.InwinstackV1().Addresses().Get(a.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	elm := mc.Elm
+	mc.Reset()
+	mc.Resp, mc.Called = nil, 0
+	mc.AddResp(elm)
+	mc.AddResp("")
+	assert.Nil(t, controller.cleanup(ga))
+	assert.Equal(t, "delete", mc.Function)
+	assert.Contains(t, mc.Path, a.Name)
+
+	ga, err = inwinset.InwinstackV1().Addresses().Get(a.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, ga.Finalizers)
+	assert.Equal(t, inwinv1.AddressTerminating, ga.Status.Phase)
+
+	assert.Nil(t, inwinset.InwinstackV1().Addresses().Delete(a.Name, nil))
+	addrList, err := inwinset.InwinstackV1().Addresses().List(metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(addrList.Items))
+	mc.Reset()
+}
+
+func TestAddressControllerLocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, SyncSec: 60}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	fwAddr := &addr.FwAddr{}
+	fwAddr.Initialize(mc)
+
+	// The config is locked by another admin.
+	lc := &testdata.MockClient{}
+	lc.AddResp(`<config-locks><entry name="admin1"><comment/></entry></config-locks>`)
+	locker := lock.NewLocker(lc, "vsys1", "", true)
+
+	controller := NewController(cfg, fwAddr, inwinset, informer.Inwinstack().V1().Addresses(), commit, locker)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	a := &inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-locked"},
+		Spec: inwinv1.AddressSpec{
+			Type:  inwinv1.AddressIPNetmask,
+			Value: "172.22.132.11/32",
+		},
+	}
+	_, err := inwinset.InwinstackV1().Addresses().Create(a)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ga, err := inwinset.InwinstackV1().Addresses().Get(a.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ga.Status.Phase == inwinv1.AddressFailed {
+			assert.Equal(t, "blocked by lock held by admin1", ga.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address object hasn't been blocked by the lock.")
+
+	cancel()
+	controller.Stop()
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package address
+
+import (
+	"github.com/golang/glog"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// planAddressObject records the change of the address object on the resource in the dry-run mode, where
+// the firewall isn't edited.
+func (c *Controller) planAddressObject(a *inwinv1.Address, deleting bool) error {
+	current, err := c.addr.Get(c.cfg.Vsys, a.Name)
+	if err != nil && !plan.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil && current.Name != ""
+
+	p := plan.Delete(a.Name, exists)
+	if !deleting {
+		entry := c.newAddressObject(a)
+		p = plan.Create(a.Name, *entry)
+		if exists {
+			p = plan.Update(a.Name, current, *entry)
+		}
+	}
+
+	aCopy := a.DeepCopy()
+	if !plan.Record(&aCopy.ObjectMeta, p) {
+		return nil
+	}
+
+	glog.Infof("Address dry-run plan: %s.", p)
+	if _, err := c.inwinset.InwinstackV1().Addresses().Update(aCopy); err != nil {
+		return err
+	}
+	return nil
+}
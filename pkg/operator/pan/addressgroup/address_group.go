@@ -0,0 +1,116 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addressgroup
+
+import (
+	"fmt"
+
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/addrgrp"
+)
+
+// missingAddresses returns the static members that are neither an active address nor an
+// active address group of the same firewall. Nothing is missing in dry-run mode, where the
+// members are only planned.
+func (c *Controller) missingAddresses(grp *inwinv1.AddressGroup) []string {
+	if c.cfg.DryRun {
+		return nil
+	}
+
+	missing := []string{}
+	for _, name := range grp.Spec.StaticAddresses {
+		addr, err := c.addrLister.Get(name)
+		if err == nil && addr.Status.Phase == inwinv1.AddressActive &&
+			firewall.Ref(addr.ObjectMeta, addr.Spec.FirewallRef) == c.cfg.Firewall {
+			continue
+		}
+
+		member, err := c.lister.Get(name)
+		if err == nil && name != grp.Name && member.Status.Phase == inwinv1.AddressGroupActive &&
+			firewall.Ref(member.ObjectMeta, member.Spec.FirewallRef) == c.cfg.Firewall {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+func (c *Controller) newAddressGroup(grp *inwinv1.AddressGroup) (*addrgrp.Entry, error) {
+	static := len(grp.Spec.StaticAddresses) != 0
+	dynamic := len(grp.Spec.DynamicMatch) != 0
+	switch {
+	case static && dynamic:
+		return nil, fmt.Errorf("the staticAddresses and dynamicMatch can't be set at the same time")
+	case !static && !dynamic:
+		return nil, fmt.Errorf("one of the staticAddresses and dynamicMatch must be set")
+	}
+
+	return &addrgrp.Entry{
+		Name:            grp.Name,
+		Description:     grp.Spec.Description,
+		StaticAddresses: grp.Spec.StaticAddresses,
+		DynamicMatch:    grp.Spec.DynamicMatch,
+		Tags:            ownership.AddTags(grp.Spec.Tags, c.cfg.ClusterID),
+	}, nil
+}
+
+func (c *Controller) isExistingAddressGroup(grp *inwinv1.AddressGroup) bool {
+	if entry, err := c.addrgrp.Get(c.cfg.Vsys, grp.Name); err == nil {
+		if len(entry.Name) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) updateAddressGroup(grp *inwinv1.AddressGroup) error {
+	entry, err := c.newAddressGroup(grp)
+	if err != nil {
+		return err
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.addrgrp.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindAddressGroup, grp.ObjectMeta)
+	return nil
+}
+
+func (c *Controller) deleteAddressGroup(grp *inwinv1.AddressGroup) error {
+	if !c.isExistingAddressGroup(grp) {
+		return nil
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.addrgrp.Delete(c.cfg.Vsys, grp.Name); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindAddressGroup, grp.ObjectMeta)
+	return nil
+}
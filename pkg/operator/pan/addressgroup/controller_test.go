@@ -0,0 +1,274 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addressgroup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/blended/constants"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/addrgrp"
+	"github.com/inwinstack/pango/testdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const timeout = 3 * time.Second
+
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
+	for {
+		select {
+		case c := <-commit:
+			assert.NotNil(t, c)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func TestAddressGroupController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, ClusterID: "k8s-a"}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwAddrGrp := &addrgrp.FwAddrGrp{}
+	fwAddrGrp.Initialize(mc)
+
+	controller := NewController(cfg, fwAddrGrp, inwinset, informer.Inwinstack().V1().AddressGroups(), informer.Inwinstack().V1().Addresses(), commit, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	grp := &inwinv1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-web",
+		},
+		Spec: inwinv1.AddressGroupSpec{
+			DynamicMatch: "'k8s.ns.default'",
+			Description:  "Test address group",
+		},
+	}
+
+	mc.Reset()
+	mc.AddResp("")
+	_, err := inwinset.InwinstackV1().AddressGroups().Create(grp)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().AddressGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+
+		mc.AddResp(mc.Elm)
+		entry, err := fwAddrGrp.Get(cfg.Vsys, ggrp.Name)
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.AddressGroupActive && entry.Name != "" {
+			assert.Equal(t, []string{constants.CustomFinalizer}, ggrp.Finalizers)
+			assert.Equal(t, ggrp.Name, entry.Name)
+			assert.Equal(t, ggrp.Spec.DynamicMatch, entry.DynamicMatch)
+			assert.Equal(t, ggrp.Spec.Description, entry.Description)
+			assert.Equal(t, ownership.Tags(cfg.ClusterID), entry.Tags)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address group hasn't created.")
+	assert.Nil(t, inwinset.InwinstackV1().AddressGroups().Delete(grp.Name, nil))
+	grpList, err := inwinset.InwinstackV1().AddressGroups().List(metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(grpList.Items))
+
+	member := &inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-web"},
+		Status:     inwinv1.AddressStatus{Phase: inwinv1.AddressActive},
+	}
+	_, err = inwinset.InwinstackV1().Addresses().Create(member)
+	assert.Nil(t, err)
+
+	invalid := &inwinv1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-invalid",
+		},
+		Spec: inwinv1.AddressGroupSpec{
+			StaticAddresses: []string{"k8s-web"},
+			DynamicMatch:    "'k8s.ns.default'",
+		},
+	}
+	_, err = inwinset.InwinstackV1().AddressGroups().Create(invalid)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().AddressGroups().Get(invalid.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.AddressGroupFailed {
+			assert.NotEmpty(t, ggrp.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The invalid address group hasn't failed.")
+
+	cancel()
+	mc.Reset()
+	controller.Stop()
+}
+
+func TestAddressGroupControllerMemberRemoved(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	mc := &testdata.MockClient{}
+	fwAddrGrp := &addrgrp.FwAddrGrp{}
+	fwAddrGrp.Initialize(mc)
+
+	controller := NewController(cfg, fwAddrGrp, inwinset, informer.Inwinstack().V1().AddressGroups(), informer.Inwinstack().V1().Addresses(), commit, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	member := &inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-db"},
+		Spec: inwinv1.AddressSpec{
+			Type:  inwinv1.AddressIPNetmask,
+			Value: "172.22.132.20/32",
+		},
+		Status: inwinv1.AddressStatus{Phase: inwinv1.AddressActive},
+	}
+	_, err := inwinset.InwinstackV1().Addresses().Create(member)
+	assert.Nil(t, err)
+
+	grp := &inwinv1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-db-servers"},
+		Spec: inwinv1.AddressGroupSpec{
+			StaticAddresses: []string{member.Name},
+		},
+	}
+
+	mc.Reset()
+	mc.AddResp("")
+	_, err = inwinset.InwinstackV1().AddressGroups().Create(grp)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().AddressGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.AddressGroupActive {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address group hasn't been active.")
+
+	// Removing the member moves the group back to pending.
+	assert.Nil(t, inwinset.InwinstackV1().Addresses().Delete(member.Name, nil))
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().AddressGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.AddressGroupPending {
+			assert.Equal(t, "waiting for the member addresses: k8s-db", ggrp.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address group hasn't noticed the removed member.")
+
+	cancel()
+	mc.Reset()
+	controller.Stop()
+}
+
+func TestAddressGroupControllerMemberNotReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	mc := &testdata.MockClient{}
+	fwAddrGrp := &addrgrp.FwAddrGrp{}
+	fwAddrGrp.Initialize(mc)
+
+	controller := NewController(cfg, fwAddrGrp, inwinset, informer.Inwinstack().V1().AddressGroups(), informer.Inwinstack().V1().Addresses(), commit, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	// An active address of another firewall and a failed address group aren't members yet.
+	other := &inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-db"},
+		Spec: inwinv1.AddressSpec{
+			Type:        inwinv1.AddressIPNetmask,
+			Value:       "172.22.132.20/32",
+			FirewallRef: "fw-b",
+		},
+		Status: inwinv1.AddressStatus{Phase: inwinv1.AddressActive},
+	}
+	_, err := inwinset.InwinstackV1().Addresses().Create(other)
+	assert.Nil(t, err)
+
+	failedGrp := &inwinv1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-web-servers"},
+		Spec:       inwinv1.AddressGroupSpec{FirewallRef: "fw-b"},
+		Status:     inwinv1.AddressGroupStatus{Phase: inwinv1.AddressGroupFailed},
+	}
+	_, err = inwinset.InwinstackV1().AddressGroups().Create(failedGrp)
+	assert.Nil(t, err)
+
+	grp := &inwinv1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-servers"},
+		Spec: inwinv1.AddressGroupSpec{
+			StaticAddresses: []string{other.Name, failedGrp.Name},
+		},
+	}
+	_, err = inwinset.InwinstackV1().AddressGroups().Create(grp)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().AddressGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.AddressGroupPending {
+			assert.Equal(t, "waiting for the member addresses: k8s-db, k8s-web-servers", ggrp.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The address group hasn't waited for the members.")
+
+	cancel()
+	mc.Reset()
+	controller.Stop()
+}
@@ -19,56 +19,214 @@ package pan
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 	blended "github.com/inwinstack/blended/generated/clientset/versioned"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
 	"github.com/inwinstack/blended/util"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/guardrail"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/operator/pan/address"
+	"github.com/inwinstack/pa-controller/pkg/operator/pan/addressgroup"
+	"github.com/inwinstack/pa-controller/pkg/operator/pan/edl"
 	"github.com/inwinstack/pa-controller/pkg/operator/pan/nat"
 	"github.com/inwinstack/pa-controller/pkg/operator/pan/security"
 	"github.com/inwinstack/pa-controller/pkg/operator/pan/service"
+	"github.com/inwinstack/pa-controller/pkg/operator/pan/servicegroup"
+	"github.com/inwinstack/pa-controller/pkg/panorama"
 	"github.com/inwinstack/pango"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 )
 
 // Controller represents the controller of PAN
 type Controller struct {
 	cfg      *config.Config
 	fw       *pango.Firewall
+	pano     *pango.Panorama
+	client   *credentials.Client
+	onPush   PushFunc
 	service  *service.Controller
+	srvcgrp  *servicegroup.Controller
+	address  *address.Controller
+	addrgrp  *addressgroup.Controller
+	edl      *edl.Controller
 	nat      *nat.Controller
 	security *security.Controller
 
-	commit chan bool
+	blendedset blended.Interface
+	inwinset   inwin.Interface
+	batcher    *commit.Batcher
+	locker     *lock.Locker
+	clients    *clients
 }
 
-// NewController creates an instance of the PAN controller
+// PushFunc is called with the results of the devices after pushing to a device group.
+type PushFunc func(devices []inwinv1.FirewallDeviceStatus, err error)
+
+// clients represents the objects and policies of a firewall or a device group of Panorama.
+type clients struct {
+	nat      nat.Client
+	security security.Client
+	service  service.Client
+	srvcgrp  servicegroup.Client
+	address  address.Client
+	addrgrp  addressgroup.Client
+	edl      edl.Client
+	tags     tagClient
+}
+
+// NewController creates an instance of the PAN controller. The namespaces of the firewall are
+// bound to the client, which commits to the firewall.
 func NewController(
 	cfg *config.Config,
 	fw *pango.Firewall,
+	client *credentials.Client,
 	blendedset blended.Interface,
-	informer blendedinformers.SharedInformerFactory) *Controller {
+	inwinset inwin.Interface,
+	informer blendedinformers.SharedInformerFactory,
+	inwinInformer inwininformers.SharedInformerFactory,
+	kubeInformer informers.SharedInformerFactory) *Controller {
 	c := &Controller{
-		cfg:    cfg,
-		fw:     fw,
-		commit: make(chan bool, 1),
+		cfg:        cfg,
+		fw:         fw,
+		client:     client,
+		blendedset: blendedset,
+		inwinset:   inwinset,
+		batcher:    newBatcher(cfg),
+		locker:     lock.NewLocker(client, cfg.Vsys, lockID(cfg), cfg.Lock),
+	}
+	c.newControllers(&clients{
+		nat:      fw.Policies.Nat,
+		security: fw.Policies.Security,
+		service:  fw.Objects.Services,
+		srvcgrp:  fw.Objects.ServiceGroup,
+		address:  fw.Objects.Address,
+		addrgrp:  fw.Objects.AddressGroup,
+		edl:      fw.Objects.Edl,
+		tags:     fw.Objects.Tags,
+	}, blendedset, inwinset, informer, inwinInformer, kubeInformer)
+	return c
+}
+
+// NewPanoramaController creates an instance of the PAN controller for Panorama. The policies are
+// synced to the rulebase of the device group, and the objects are synced to the device group or
+// shared. The namespaces of Panorama are bound to the client like the firewall. The onPush is
+// called after pushing to the devices, and it's optional.
+func NewPanoramaController(
+	cfg *config.Config,
+	pano *pango.Panorama,
+	client *credentials.Client,
+	onPush PushFunc,
+	blendedset blended.Interface,
+	inwinset inwin.Interface,
+	informer blendedinformers.SharedInformerFactory,
+	inwinInformer inwininformers.SharedInformerFactory,
+	kubeInformer informers.SharedInformerFactory) *Controller {
+	c := &Controller{
+		cfg:        cfg,
+		pano:       pano,
+		client:     client,
+		onPush:     onPush,
+		blendedset: blendedset,
+		inwinset:   inwinset,
+		batcher:    newBatcher(cfg),
+		locker:     lock.NewLocker(client, cfg.DeviceGroup, lockID(cfg), cfg.Lock),
+	}
+
+	location := cfg.DeviceGroup
+	if cfg.SharedObjects {
+		location = panorama.Shared
 	}
-	c.nat = nat.NewController(cfg, fw.Policies.Nat, blendedset, informer.Inwinstack().V1().NATs(), c.commit)
-	c.service = service.NewController(cfg, fw.Objects.Services, blendedset, informer.Inwinstack().V1().Services(), c.commit)
-	c.security = security.NewController(cfg, fw.Policies.Security, blendedset, informer.Inwinstack().V1().Securities(), c.commit)
+	c.newControllers(&clients{
+		nat:      panorama.NewNat(pano.Policies.Nat, cfg.DeviceGroup, cfg.Rulebase),
+		security: panorama.NewSecurity(pano.Policies.Security, cfg.DeviceGroup, cfg.Rulebase),
+		service:  panorama.NewServices(pano.Objects.Services, location),
+		srvcgrp:  panorama.NewServiceGroup(pano.Objects.ServiceGroup, location),
+		address:  panorama.NewAddress(pano.Objects.Address, location),
+		addrgrp:  panorama.NewAddressGroup(pano.Objects.AddressGroup, location),
+		edl:      panorama.NewEdl(pano.Objects.Edl, location),
+		tags:     panorama.NewTags(pano.Objects.Tags, location),
+	}, blendedset, inwinset, informer, inwinInformer, kubeInformer)
 	return c
 }
 
+// lockID returns the ID of the locks of the controller, which is the cluster ID and the
+// firewall of the controller.
+func lockID(cfg *config.Config) string {
+	return strings.Trim(cfg.ClusterID+"/"+cfg.Firewall, "/")
+}
+
+func (c *Controller) newControllers(
+	clients *clients,
+	blendedset blended.Interface,
+	inwinset inwin.Interface,
+	informer blendedinformers.SharedInformerFactory,
+	inwinInformer inwininformers.SharedInformerFactory,
+	kubeInformer informers.SharedInformerFactory) {
+	cfg := c.cfg
+	c.clients = clients
+	resolver := dependency.NewResolver(cfg, informer, inwinInformer)
+	guard := guardrail.NewGuard(kubeInformer, inwinInformer)
+	c.nat = nat.NewController(cfg, clients.nat, blendedset, informer.Inwinstack().V1().NATs(), c.batcher.Queue(), c.locker, resolver, guard)
+	c.service = service.NewController(cfg, clients.service, blendedset, informer.Inwinstack().V1().Services(), c.batcher.Queue(), c.locker, resolver)
+	c.srvcgrp = servicegroup.NewController(cfg, clients.srvcgrp, inwinset, inwinInformer.Inwinstack().V1().ServiceGroups(), informer.Inwinstack().V1().Services(), c.batcher.Queue(), c.locker)
+	c.address = address.NewController(cfg, clients.address, inwinset, inwinInformer.Inwinstack().V1().Addresses(), c.batcher.Queue(), c.locker)
+	c.edl = edl.NewController(cfg, clients.edl, inwinset, inwinInformer.Inwinstack().V1().ExternalDynamicLists(), c.batcher.Queue(), c.locker)
+	c.addrgrp = addressgroup.NewController(cfg, clients.addrgrp, inwinset, inwinInformer.Inwinstack().V1().AddressGroups(), inwinInformer.Inwinstack().V1().Addresses(), c.batcher.Queue(), c.locker)
+	c.security = security.NewController(cfg, clients.security, blendedset, informer.Inwinstack().V1().Securities(), c.batcher.Queue(), c.locker, resolver, guard)
+}
+
 // Run serves the PAN controller
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	glog.Info("Starting the PAN controller")
 	go c.handleCommitJob(ctx.Done())
+	if c.cfg.ClusterID != "" {
+		if err := c.ensureTags(); err != nil {
+			glog.Errorf("Failed to create the ownership tags: %+v.", err)
+		}
+		if c.cfg.SweepSec > 0 {
+			go wait.Until(c.sweep, time.Second*time.Duration(c.cfg.SweepSec), ctx.Done())
+		}
+	}
+
+	if c.cfg.DryRun {
+		glog.Info("The PAN controller runs in the dry-run mode, the changes are planned without editing or committing")
+		if c.cfg.Validate {
+			go c.validate()
+		}
+	}
 
 	if err := c.service.Run(ctx, c.cfg.Threads); err != nil {
 		return fmt.Errorf("failed to run the service controller: %s", err.Error())
 	}
 
+	if err := c.srvcgrp.Run(ctx, c.cfg.Threads); err != nil {
+		return fmt.Errorf("failed to run the service group controller: %s", err.Error())
+	}
+
+	if err := c.address.Run(ctx, c.cfg.Threads); err != nil {
+		return fmt.Errorf("failed to run the address controller: %s", err.Error())
+	}
+
+	if err := c.edl.Run(ctx, c.cfg.Threads); err != nil {
+		return fmt.Errorf("failed to run the external dynamic list controller: %s", err.Error())
+	}
+
+	if err := c.addrgrp.Run(ctx, c.cfg.Threads); err != nil {
+		return fmt.Errorf("failed to run the address group controller: %s", err.Error())
+	}
+
 	if err := c.nat.Run(ctx, c.cfg.Threads); err != nil {
 		return fmt.Errorf("failed to run the nat controller: %s", err.Error())
 	}
@@ -85,44 +243,191 @@ func (c *Controller) Stop() {
 	c.nat.Stop()
 	c.security.Stop()
 	c.service.Stop()
+	c.srvcgrp.Stop()
+	c.address.Stop()
+	c.edl.Stop()
+	c.addrgrp.Stop()
+	if err := c.locker.Release(); err != nil {
+		glog.Errorf("Failed to release the locks: %+v.", err)
+	}
 }
 
-func (c *Controller) commitToPAN() error {
-	_, err := c.fw.Commit(c.cfg.Vsys, c.cfg.Admins, c.cfg.DaNPartial, c.cfg.PaOPartial, c.cfg.Force, c.cfg.Sync)
+// commitToPAN commits to PAN and polls the commit job to completion.
+func (c *Controller) commitToPAN() (*commit.Result, error) {
+	// The commit fails when the commits are locked by another admin.
+	if err := c.locker.Check(); err != nil {
+		return nil, err
+	}
+
+	if c.pano != nil {
+		return c.commitToPanorama()
+	}
+
+	job, err := c.client.Commit(c.cfg.Vsys, c.cfg.Admins, c.cfg.DaNPartial, c.cfg.PaOPartial, c.cfg.Force, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	if job == 0 {
+		return nothingCommitted(), nil
+	}
+	return commit.Wait(c.client, job)
+}
+
+// nothingCommitted returns the result of a commit without a job, which means PAN had no
+// changes of the admins to commit. The changes of the batch aren't live in this case, for
+// example when they were reverted, so they aren't marked committed.
+func nothingCommitted() *commit.Result {
+	return &commit.Result{Time: time.Now(), Messages: []string{"there were no changes to commit"}}
+}
+
+// commitToPanorama commits to Panorama and waits for the commit job, then pushes to the
+// devices of the device group.
+func (c *Controller) commitToPanorama() (*commit.Result, error) {
+	job, err := c.client.Commit("", c.cfg.Admins, c.cfg.DaNPartial, c.cfg.PaOPartial, c.cfg.Force, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := nothingCommitted()
+	if job != 0 {
+		if result, err = commit.Wait(c.client, job); err != nil {
+			return result, err
+		}
+	}
+
+	devices, err := panorama.Push(c.client.Panorama(), c.cfg.DeviceGroup)
+	for _, d := range devices {
+		glog.Infof("Pushed to device '%s' (%s) of device group '%s': %s.", d.Name, d.Serial, c.cfg.DeviceGroup, d.Result)
+	}
+
+	if c.onPush != nil {
+		c.onPush(devices, err)
+	}
+
+	if err != nil {
+		result.Committed = false
+		result.Messages = append(result.Messages, fmt.Sprintf("failed to push to the devices: %s", err.Error()))
+	}
+	return result, err
+}
+
+// revert reverts the candidate config changed by the admins of the controller to the running
+// config. The admin of the client is used when the commit admins aren't set, so the changes of
+// the other admins are never reverted.
+func (c *Controller) revert() error {
+	admins := c.cfg.Admins
+	if len(admins) == 0 && c.client.Load().Username != "" {
+		admins = []string{c.client.Load().Username}
+	}
+	glog.Infof("Reverting the candidate config of admins %v.", admins)
+	return commit.Revert(c.client, admins)
+}
+
+// validate runs a validate job on the candidate config in the dry-run mode, where nothing
+// is committed.
+func (c *Controller) validate() {
+	result, err := commit.Validate(c.client)
+	if err != nil {
+		glog.Errorf("Failed to validate the candidate config: %+v.", err)
+		if result == nil {
+			return
+		}
+	}
+
+	for _, msg := range result.Messages {
+		glog.Infof("Validate job %d: %s.", result.JobID, msg)
+	}
+	if result.Committed {
+		glog.Infof("Validate job %d passed.", result.JobID)
+	}
+}
+
+// newBatcher creates the batcher of the commit jobs, which waits for the next job within
+// the commit wait time.
+func newBatcher(cfg *config.Config) *commit.Batcher {
+	return commit.NewBatcher(
+		time.Second*time.Duration(cfg.CommitWaitTime),
+		time.Second*time.Duration(cfg.CommitMaxDelay),
+		cfg.CommitMaxBatch,
+		clock.RealClock{})
 }
 
-func (c *Controller) waitNextCommitJob(t time.Duration) bool {
-	ch := make(chan struct{})
+// QueueDepth returns the number of changes waiting for a commit.
+func (c *Controller) QueueDepth() int {
+	return c.batcher.QueueDepth()
+}
+
+// BatchSize returns the number of changes in the last commit.
+func (c *Controller) BatchSize() int {
+	return c.batcher.BatchSize()
+}
+
+// pauseEdits waits for the edits in flight and holds the new edits off until the locker resumes,
+// then returns the objects queued for the next batch. The queue is drained while waiting, since
+// the edits in flight may be blocked on a full queue.
+func (c *Controller) pauseEdits() []*commit.Object {
+	paused := make(chan struct{})
 	go func() {
-		<-c.commit
-		close(ch)
+		c.locker.Pause()
+		close(paused)
 	}()
 
-	select {
-	case <-ch:
-		return c.waitNextCommitJob(t)
-	case <-time.After(t):
-		c.commit <- false
-		return true
+	var objects []*commit.Object
+	for {
+		select {
+		case <-paused:
+			return append(objects, c.batcher.Drain()...)
+		case obj := <-c.batcher.Queue():
+			if obj != nil {
+				objects = append(objects, obj)
+			}
+		}
 	}
 }
 
 func (c *Controller) handleCommitJob(stopCh <-chan struct{}) {
 	for {
-		select {
-		case ok := <-c.commit:
-			if ok {
-				if c.waitNextCommitJob(time.Second * time.Duration(c.cfg.CommitWaitTime)) {
-					glog.V(3).Infoln("Received commit job signal...")
-					util.Retry(c.commitToPAN, time.Second*2, c.cfg.Retry)
-				}
-			}
-		case <-stopCh:
+		objects := c.batcher.Next(stopCh)
+		if objects == nil {
 			return
 		}
+
+		glog.V(3).Infof("Received a commit batch of %d changes, %d changes queued...", len(objects), c.batcher.QueueDepth())
+		var result *commit.Result
+		err := util.Retry(func() error {
+			r, err := c.commitToPAN()
+			if r != nil {
+				result = r
+			}
+			return err
+		}, time.Second*2, c.cfg.Retry)
+
+		if result == nil {
+			result = &commit.Result{Time: time.Now(), Messages: []string{err.Error()}}
+		}
+
+		// The candidate config is reverted when the commit fails, so the edits aren't
+		// committed by the next admin. The edits are paused during the revert, and the edits
+		// waiting for the next batch are reverted as well, so they're marked failed to be
+		// synced again. Nothing is reverted when the commit is blocked by the lock of another
+		// admin, and the batch is marked failed to be synced again once the lock is released.
+		if err != nil && !lock.IsLocked(err) {
+			queued := c.pauseEdits()
+			if rerr := c.revert(); rerr != nil {
+				glog.Errorf("Failed to revert the candidate config: %+v.", rerr)
+				c.record(commit.Unique(queued), result, err)
+			} else if reverted := commit.Unique(queued); len(reverted) != 0 {
+				revertErr := fmt.Errorf("the change was reverted after the commit failed: %s", err.Error())
+				c.record(reverted, &commit.Result{Time: time.Now(), Messages: []string{revertErr.Error()}}, revertErr)
+			}
+			c.locker.Resume()
+		}
+		c.record(commit.Unique(objects), result, err)
+
+		// The locks are kept while an edit is in flight or the next batch is waiting.
+		if err := c.locker.ReleaseIdle(c.batcher.QueueDepth); err != nil {
+			glog.Errorf("Failed to release the locks: %+v.", err)
+		}
 	}
 }
@@ -18,30 +18,37 @@ package pan
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
 	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/credentials"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/lock"
 	"github.com/inwinstack/pango"
 	"github.com/inwinstack/pango/objs"
+	"github.com/inwinstack/pango/objs/addr"
+	"github.com/inwinstack/pango/objs/addrgrp"
+	"github.com/inwinstack/pango/objs/edl"
 	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/objs/srvcgrp"
 	"github.com/inwinstack/pango/poli"
 	"github.com/inwinstack/pango/poli/nat"
 	"github.com/inwinstack/pango/poli/security"
+	"github.com/inwinstack/pango/testdata"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
-func commitSignal(t *testing.T, commit chan bool) {
-	for {
-		select {
-		case c := <-commit:
-			assert.Equal(t, false, c)
-			break
-		}
-	}
-}
-
 func TestPANController(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	fw := &pango.Firewall{
@@ -50,20 +57,136 @@ func TestPANController(t *testing.T) {
 			Security: &security.FwSecurity{},
 		},
 		Objects: &objs.FwObjs{
-			Services: &srvc.FwSrvc{},
+			Services:     &srvc.FwSrvc{},
+			Address:      &addr.FwAddr{},
+			AddressGroup: &addrgrp.FwAddrGrp{},
+			ServiceGroup: &srvcgrp.FwSrvcGrp{},
+			Edl:          &edl.FwEdl{},
 		},
 	}
 	cfg := &config.Config{Threads: 2, Retry: 5}
 	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
 	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
-	controller := NewController(cfg, fw, blendedset, informer)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	controller := NewController(cfg, fw, credentials.NewClient(&fw.Client), blendedset, inwinset, informer, inwinInformer, kubeInformer)
 	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
 	assert.NotNil(t, controller)
 	assert.Nil(t, controller.Run(ctx, cfg.Threads))
 
-	go commitSignal(t, controller.commit)
-	controller.commit <- false
+	sec := &blendedv1.Security{ObjectMeta: metav1.ObjectMeta{Name: "test-sec", Namespace: "default"}}
+	_, err := blendedset.InwinstackV1().Securities(sec.Namespace).Create(sec)
+	assert.Nil(t, err)
+
+	objects := commit.Unique([]*commit.Object{
+		commit.NewObject(commit.KindSecurity, sec.ObjectMeta),
+		commit.NewObject(commit.KindSecurity, sec.ObjectMeta),
+		commit.NewObject(commit.KindAddress, metav1.ObjectMeta{Name: "deleted"}),
+	})
+	assert.Equal(t, 2, len(objects))
+
+	committed := time.Date(2018, 10, 1, 8, 0, 0, 0, time.UTC)
+	commitErr := fmt.Errorf("commit job 12 failed: rule is invalid")
+	controller.record(objects, &commit.Result{JobID: 12, Time: committed, Committed: false, Messages: []string{"rule is invalid"}}, commitErr)
+	gsec, err := blendedset.InwinstackV1().Securities(sec.Namespace).Get(sec.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "False", gsec.Annotations[constants.CommittedKey])
+	assert.Equal(t, "12", gsec.Annotations[constants.CommitJobKey])
+	assert.Equal(t, "2018-10-01T08:00:00Z", gsec.Annotations[constants.CommitTimeKey])
+	assert.Equal(t, "rule is invalid", gsec.Annotations[constants.CommitMessageKey])
+	assert.Equal(t, blendedv1.SecurityFailed, gsec.Status.Phase)
+	assert.Equal(t, commitErr.Error(), gsec.Status.Reason)
+
+	controller.record(objects, &commit.Result{JobID: 13, Time: committed, Committed: true}, nil)
+	gsec, err = blendedset.InwinstackV1().Securities(sec.Namespace).Get(sec.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "True", gsec.Annotations[constants.CommittedKey])
+	assert.Equal(t, "13", gsec.Annotations[constants.CommitJobKey])
+	_, ok := gsec.Annotations[constants.CommitMessageKey]
+	assert.False(t, ok)
+
+	// The changes aren't live when PAN has nothing to commit, such as after a revert.
+	controller.record(objects, nothingCommitted(), nil)
+	gsec, err = blendedset.InwinstackV1().Securities(sec.Namespace).Get(sec.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "False", gsec.Annotations[constants.CommittedKey])
+	assert.Equal(t, "0", gsec.Annotations[constants.CommitJobKey])
+	assert.Equal(t, "there were no changes to commit", gsec.Annotations[constants.CommitMessageKey])
+
+	cancel()
+	controller.Stop()
+}
+
+func TestPanoramaController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pano := &pango.Panorama{
+		Policies: &poli.PanoPoli{
+			Nat:      &nat.PanoNat{},
+			Security: &security.PanoSecurity{},
+		},
+		Objects: &objs.PanoObjs{
+			Services:     &srvc.PanoSrvc{},
+			Address:      &addr.PanoAddr{},
+			AddressGroup: &addrgrp.PanoAddrGrp{},
+			ServiceGroup: &srvcgrp.PanoSrvcGrp{},
+			Edl:          &edl.PanoEdl{},
+		},
+	}
+	cfg := &config.Config{Threads: 2, Retry: 5, Panorama: true, DeviceGroup: "k8s", SharedObjects: true}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	controller := NewPanoramaController(cfg, pano, credentials.NewClient(&pano.Client), nil, blendedset, inwinset, informer, inwinInformer, kubeInformer)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	assert.NotNil(t, controller)
+	assert.Nil(t, controller.fw)
+	assert.Equal(t, pano, controller.pano)
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
 
 	cancel()
 	controller.Stop()
 }
+
+func TestPauseEdits(t *testing.T) {
+	cfg := &config.Config{CommitMaxBatch: 1}
+	c := &Controller{
+		cfg:     cfg,
+		batcher: newBatcher(cfg),
+		locker:  lock.NewLocker(&testdata.MockClient{}, "vsys1", "", false),
+	}
+
+	// The edit in flight is blocked on the full queue, and it's waited for while the queue
+	// is drained.
+	c.batcher.Queue() <- commit.NewObject(commit.KindAddress, metav1.ObjectMeta{Name: "web"})
+	assert.Nil(t, c.locker.Acquire())
+	go func() {
+		defer c.locker.Done()
+		c.batcher.Queue() <- commit.NewObject(commit.KindAddress, metav1.ObjectMeta{Name: "db"})
+	}()
+
+	queued := c.pauseEdits()
+	assert.Equal(t, 2, len(queued))
+
+	acquired := make(chan struct{})
+	go func() {
+		assert.Nil(t, c.locker.Acquire())
+		c.locker.Done()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("An edit was started while the edits are paused.")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	c.locker.Resume()
+	<-acquired
+}
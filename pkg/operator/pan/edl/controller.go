@@ -0,0 +1,283 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thoas/go-funk"
+
+	"github.com/golang/glog"
+	"github.com/inwinstack/blended/constants"
+	"github.com/inwinstack/blended/k8sutil"
+	"github.com/inwinstack/blended/util"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	informerv1 "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions/inwinstack/v1"
+	listerv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+	paedl "github.com/inwinstack/pango/objs/edl"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Client represents the external dynamic lists of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (paedl.Entry, error)
+	Edit(vsys string, e paedl.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
+// Controller represents the controller of external dynamic list
+type Controller struct {
+	cfg      *config.Config
+	edl      Client
+	inwinset inwin.Interface
+	lister   listerv1.ExternalDynamicListLister
+	synced   cache.InformerSynced
+	queue    workqueue.RateLimitingInterface
+
+	commit chan *commit.Object
+	locker *lock.Locker
+}
+
+// NewController creates an instance of the external dynamic list controller
+func NewController(
+	cfg *config.Config,
+	edl Client,
+	inwinset inwin.Interface,
+	informer informerv1.ExternalDynamicListInformer,
+	commit chan *commit.Object,
+	locker *lock.Locker) *Controller {
+	controller := &Controller{
+		cfg:      cfg,
+		edl:      edl,
+		inwinset: inwinset,
+		lister:   informer.Lister(),
+		synced:   informer.Informer().HasSynced,
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ExternalDynamicListObjects"),
+		commit:   commit,
+		locker:   locker,
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oo := old.(*inwinv1.ExternalDynamicList)
+			no := new.(*inwinv1.ExternalDynamicList)
+			k8sutil.MakeNeedToUpdate(&no.ObjectMeta, oo.Spec, no.Spec)
+			controller.enqueue(no)
+		},
+	})
+	return controller
+}
+
+// Run serves the external dynamic list controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the external dynamic list controller")
+	glog.Info("Waiting for the external dynamic list informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the external dynamic list controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the external dynamic list controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("ExternalDynamicList expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("ExternalDynamicList error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("ExternalDynamicList successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) reconcile(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	list, err := c.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("external dynamic list '%s' in work queue no longer exists", key))
+			return err
+		}
+		return err
+	}
+
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(list.ObjectMeta, list.Spec.FirewallRef) != c.cfg.Firewall {
+		return nil
+	}
+
+	if !list.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := c.cleanup(list); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := c.checkAndUdateFinalizer(list); err != nil {
+		return err
+	}
+
+	need := k8sutil.IsNeedToUpdate(list.ObjectMeta)
+	if list.Status.Phase != inwinv1.ExternalDynamicListActive || need {
+		if list.Status.Phase == inwinv1.ExternalDynamicListFailed {
+			t := util.SubtractNowTime(list.Status.LastUpdateTime.Time)
+			if t.Seconds() <= float64(c.cfg.SyncSec) && !need {
+				return nil
+			}
+		}
+		if err := c.createOrUpdate(list); err != nil {
+			return c.makeFailed(list, err)
+		}
+		return nil
+	}
+
+	if list.Status.Phase == inwinv1.ExternalDynamicListActive && !c.isExistingEDLObject(list) {
+		if err := c.createOrUpdate(list); err != nil {
+			return c.makeFailed(list, err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) checkAndUdateFinalizer(edl *inwinv1.ExternalDynamicList) error {
+	edlCopy := edl.DeepCopy()
+	ok := funk.ContainsString(edlCopy.Finalizers, constants.CustomFinalizer)
+	if edl.Status.Phase == inwinv1.ExternalDynamicListActive && !ok {
+		k8sutil.AddFinalizer(&edlCopy.ObjectMeta, constants.CustomFinalizer)
+		if _, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Update(edlCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) makeFailed(edl *inwinv1.ExternalDynamicList, e error) error {
+	edlCopy := edl.DeepCopy()
+	edlCopy.Status.Reason = e.Error()
+	edlCopy.Status.Phase = inwinv1.ExternalDynamicListFailed
+	edlCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(edlCopy.Annotations, constants.NeedUpdateKey)
+	if _, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Update(edlCopy); err != nil {
+		return err
+	}
+	glog.Errorf("ExternalDynamicList got an error:%+v.", e)
+	return nil
+}
+
+func (c *Controller) createOrUpdate(edl *inwinv1.ExternalDynamicList) error {
+	if c.cfg.DryRun {
+		return c.planEDLObject(edl, false)
+	}
+
+	edlCopy := edl.DeepCopy()
+	if err := c.updateEDLObject(edlCopy); err != nil {
+		return err
+	}
+
+	edlCopy.Status.Reason = ""
+	edlCopy.Status.Phase = inwinv1.ExternalDynamicListActive
+	edlCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(edlCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&edlCopy.ObjectMeta)
+	k8sutil.AddFinalizer(&edlCopy.ObjectMeta, constants.CustomFinalizer)
+	if _, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Update(edlCopy); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) cleanup(edl *inwinv1.ExternalDynamicList) error {
+	if c.cfg.DryRun {
+		return c.planEDLObject(edl, true)
+	}
+
+	edlCopy := edl.DeepCopy()
+	if err := c.deleteEDLObject(edlCopy); err != nil {
+		return err
+	}
+
+	k8sutil.RemoveFinalizer(&edlCopy.ObjectMeta, constants.CustomFinalizer)
+	edlCopy.Status.Phase = inwinv1.ExternalDynamicListTerminating
+	if _, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Update(edlCopy); err != nil {
+		return err
+	}
+	return nil
+}
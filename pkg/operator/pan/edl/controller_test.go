@@ -0,0 +1,236 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/blended/constants"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	paedl "github.com/inwinstack/pango/objs/edl"
+	"github.com/inwinstack/pango/testdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const timeout = 3 * time.Second
+
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
+	for {
+		select {
+		case c := <-commit:
+			assert.NotNil(t, c)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func TestExternalDynamicListController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, EDLURL: "http://10.0.0.10:8080/"}
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwEdl := &paedl.FwEdl{}
+	fwEdl.Initialize(mc)
+
+	controller := NewController(cfg, fwEdl, inwinset, informer.Inwinstack().V1().ExternalDynamicLists(), commit, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	e := &inwinv1.ExternalDynamicList{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-web-pods",
+		},
+		Spec: inwinv1.ExternalDynamicListSpec{
+			Source:      inwinv1.ExternalDynamicListPod,
+			Namespaces:  []string{"default"},
+			Description: "Test external dynamic list",
+		},
+	}
+
+	mc.Reset()
+	mc.AddResp("")
+	_, err := inwinset.InwinstackV1().ExternalDynamicLists().Create(e)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ge, err := inwinset.InwinstackV1().ExternalDynamicLists().Get(e.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+
+		mc.AddResp(mc.Elm)
+		entry, err := fwEdl.Get(cfg.Vsys, ge.Name)
+		assert.Nil(t, err)
+		if ge.Status.Phase == inwinv1.ExternalDynamicListActive && entry.Name != "" {
+			assert.Equal(t, []string{constants.CustomFinalizer}, ge.Finalizers)
+			assert.Equal(t, "http://10.0.0.10:8080/edl/k8s-web-pods", ge.Status.URL)
+			assert.Equal(t, ge.Name, entry.Name)
+			assert.Equal(t, paedl.TypeIp, entry.Type)
+			assert.Equal(t, ge.Status.URL, entry.Source)
+			assert.Equal(t, paedl.RepeatEveryFiveMinutes, entry.Repeat)
+			assert.Equal(t, ge.Spec.Description, entry.Description)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The external dynamic list object hasn't created.")
+
+	cancel()
+	controller.Stop()
+
+	// The fake clientset doesn't honor finalizers, so run the cleanup directly.
+	ge, err := inwinset.InwinstackV1().ExternalDynamicLists().Get(e.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	elm := mc.Elm
+	mc.Reset()
+	mc.Resp, mc.Called = nil, 0
+	mc.AddResp(elm)
+	mc.AddResp("")
+	assert.Nil(t, controller.cleanup(ge))
+	assert.Equal(t, "delete", mc.Function)
+	assert.Contains(t, mc.Path, e.Name)
+
+	assert.Nil(t, inwinset.InwinstackV1().ExternalDynamicLists().Delete(e.Name, nil))
+	edlList, err := inwinset.InwinstackV1().ExternalDynamicLists().List(metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(edlList.Items))
+	mc.Reset()
+}
+
+func TestExternalDynamicListObject(t *testing.T) {
+	tests := []struct {
+		baseURL   string
+		clusterID string
+		spec      inwinv1.ExternalDynamicListSpec
+		entry     *paedl.Entry
+		err       string
+	}{
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListNode},
+			entry:   &paedl.Entry{Repeat: paedl.RepeatEveryFiveMinutes},
+		},
+		{
+			baseURL: "https://edl.example.com/",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListService, Repeat: paedl.RepeatHourly},
+			entry:   &paedl.Entry{Repeat: paedl.RepeatHourly},
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatDaily, RepeatAt: "03"},
+			entry:   &paedl.Entry{Repeat: paedl.RepeatDaily, RepeatAt: "03"},
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec: inwinv1.ExternalDynamicListSpec{
+				Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatWeekly, RepeatAt: "23", RepeatDayOfWeek: "sunday",
+			},
+			entry: &paedl.Entry{Repeat: paedl.RepeatWeekly, RepeatAt: "23", RepeatDayOfWeek: "sunday"},
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec: inwinv1.ExternalDynamicListSpec{
+				Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatMonthly, RepeatAt: "00", RepeatDayOfMonth: 31,
+			},
+			entry: &paedl.Entry{Repeat: paedl.RepeatMonthly, RepeatAt: "00", RepeatDayOfMonth: 31},
+		},
+		{
+			baseURL:   "http://10.0.0.10:8080",
+			clusterID: "k8s-a",
+			spec:      inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod, Description: "web pods"},
+			entry:     &paedl.Entry{Repeat: paedl.RepeatEveryFiveMinutes, Description: "web pods [pa-controller pa-cluster-k8s-a]"},
+		},
+		{
+			spec: inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod},
+			err:  "the EDL URL isn't set",
+		},
+		{
+			baseURL: "10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod},
+			err:     "invalid EDL URL",
+		},
+		{
+			baseURL: "ftp://10.0.0.10",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod},
+			err:     "invalid EDL URL 'ftp://10.0.0.10', it must be an http or https URL",
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: "ingress"},
+			err:     "invalid source 'ingress'",
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod, Repeat: "yearly"},
+			err:     "invalid repeat 'yearly'",
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatDaily, RepeatAt: "24"},
+			err:     "invalid repeatAt '24' of the daily repeat, it must be an hour from 00 to 23",
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec: inwinv1.ExternalDynamicListSpec{
+				Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatWeekly, RepeatAt: "03", RepeatDayOfWeek: "Sun",
+			},
+			err: "invalid repeatDayOfWeek 'Sun' of the weekly repeat",
+		},
+		{
+			baseURL: "http://10.0.0.10:8080",
+			spec:    inwinv1.ExternalDynamicListSpec{Source: inwinv1.ExternalDynamicListPod, Repeat: paedl.RepeatMonthly, RepeatAt: "03"},
+			err:     "invalid repeatDayOfMonth 0 of the monthly repeat",
+		},
+	}
+
+	for _, test := range tests {
+		c := &Controller{cfg: &config.Config{EDLURL: test.baseURL, ClusterID: test.clusterID}}
+		e := &inwinv1.ExternalDynamicList{
+			ObjectMeta: metav1.ObjectMeta{Name: "k8s-web-pods"},
+			Spec:       test.spec,
+		}
+
+		entry, err := c.newEDLObject(e)
+		if test.err != "" {
+			assert.NotNil(t, err)
+			if err != nil {
+				assert.Contains(t, err.Error(), test.err)
+			}
+			continue
+		}
+
+		assert.Nil(t, err)
+		test.entry.Name = e.Name
+		test.entry.Type = paedl.TypeIp
+		test.entry.Source = strings.TrimSuffix(test.baseURL, "/") + "/edl/k8s-web-pods"
+		assert.Equal(t, test.entry, entry)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	paedl "github.com/inwinstack/pango/objs/edl"
+	"github.com/thoas/go-funk"
+)
+
+var (
+	sources = []string{
+		string(inwinv1.ExternalDynamicListPod),
+		string(inwinv1.ExternalDynamicListNode),
+		string(inwinv1.ExternalDynamicListService),
+	}
+	daysOfWeek = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	hour       = regexp.MustCompile(`^([01][0-9]|2[0-3])$`)
+)
+
+// URL returns the URL where the IPs of the external dynamic list are served.
+func URL(baseURL, name string) string {
+	return fmt.Sprintf("%s/edl/%s", strings.TrimSuffix(baseURL, "/"), name)
+}
+
+// validateURL checks the base URL that PAN polls the lists from.
+func validateURL(baseURL string) error {
+	if baseURL == "" {
+		return fmt.Errorf("the EDL URL isn't set")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid EDL URL '%s': %s", baseURL, err.Error())
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid EDL URL '%s', it must be an http or https URL", baseURL)
+	}
+	return nil
+}
+
+// validateRepeat checks the recurring interval, and the hour and day it needs.
+func validateRepeat(spec inwinv1.ExternalDynamicListSpec) error {
+	switch spec.Repeat {
+	case "", paedl.RepeatEveryFiveMinutes, paedl.RepeatHourly:
+		return nil
+	case paedl.RepeatDaily, paedl.RepeatWeekly, paedl.RepeatMonthly:
+	default:
+		return fmt.Errorf("invalid repeat '%s'", spec.Repeat)
+	}
+
+	if !hour.MatchString(spec.RepeatAt) {
+		return fmt.Errorf("invalid repeatAt '%s' of the %s repeat, it must be an hour from 00 to 23", spec.RepeatAt, spec.Repeat)
+	}
+
+	switch {
+	case spec.Repeat == paedl.RepeatWeekly && !funk.ContainsString(daysOfWeek, spec.RepeatDayOfWeek):
+		return fmt.Errorf("invalid repeatDayOfWeek '%s' of the weekly repeat", spec.RepeatDayOfWeek)
+	case spec.Repeat == paedl.RepeatMonthly && (spec.RepeatDayOfMonth < 1 || spec.RepeatDayOfMonth > 31):
+		return fmt.Errorf("invalid repeatDayOfMonth %d of the monthly repeat", spec.RepeatDayOfMonth)
+	}
+	return nil
+}
+
+func (c *Controller) newEDLObject(e *inwinv1.ExternalDynamicList) (*paedl.Entry, error) {
+	if err := validateURL(c.cfg.EDLURL); err != nil {
+		return nil, err
+	}
+
+	if !funk.ContainsString(sources, string(e.Spec.Source)) {
+		return nil, fmt.Errorf("invalid source '%s'", e.Spec.Source)
+	}
+
+	if err := validateRepeat(e.Spec); err != nil {
+		return nil, err
+	}
+
+	entry := &paedl.Entry{
+		Name:        e.Name,
+		Type:        paedl.TypeIp,
+		Description: ownership.Describe(e.Spec.Description, c.cfg.ClusterID),
+		Source:      URL(c.cfg.EDLURL, e.Name),
+		Repeat:      e.Spec.Repeat,
+	}
+
+	switch e.Spec.Repeat {
+	case "":
+		entry.Repeat = paedl.RepeatEveryFiveMinutes
+	case paedl.RepeatDaily:
+		entry.RepeatAt = e.Spec.RepeatAt
+	case paedl.RepeatWeekly:
+		entry.RepeatAt = e.Spec.RepeatAt
+		entry.RepeatDayOfWeek = e.Spec.RepeatDayOfWeek
+	case paedl.RepeatMonthly:
+		entry.RepeatAt = e.Spec.RepeatAt
+		entry.RepeatDayOfMonth = e.Spec.RepeatDayOfMonth
+	}
+	return entry, nil
+}
+
+func (c *Controller) isExistingEDLObject(e *inwinv1.ExternalDynamicList) bool {
+	if entry, err := c.edl.Get(c.cfg.Vsys, e.Name); err == nil {
+		if len(entry.Name) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) updateEDLObject(e *inwinv1.ExternalDynamicList) error {
+	entry, err := c.newEDLObject(e)
+	if err != nil {
+		return err
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.edl.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	e.Status.URL = entry.Source
+	c.commit <- commit.NewObject(commit.KindExternalDynamicList, e.ObjectMeta)
+	return nil
+}
+
+func (c *Controller) deleteEDLObject(e *inwinv1.ExternalDynamicList) error {
+	if !c.isExistingEDLObject(e) {
+		return nil
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.edl.Delete(c.cfg.Vsys, e.Name); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindExternalDynamicList, e.ObjectMeta)
+	return nil
+}
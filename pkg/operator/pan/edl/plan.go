@@ -0,0 +1,56 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edl
+
+import (
+	"github.com/golang/glog"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// planEDLObject records the change of the external dynamic list on the resource in the dry-run mode, where
+// the firewall isn't edited.
+func (c *Controller) planEDLObject(e *inwinv1.ExternalDynamicList, deleting bool) error {
+	current, err := c.edl.Get(c.cfg.Vsys, e.Name)
+	if err != nil && !plan.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil && current.Name != ""
+
+	p := plan.Delete(e.Name, exists)
+	if !deleting {
+		entry, err := c.newEDLObject(e)
+		if err != nil {
+			return err
+		}
+		p = plan.Create(e.Name, *entry)
+		if exists {
+			p = plan.Update(e.Name, current, *entry)
+		}
+	}
+
+	eCopy := e.DeepCopy()
+	if !plan.Record(&eCopy.ObjectMeta, p) {
+		return nil
+	}
+
+	glog.Infof("ExternalDynamicList dry-run plan: %s.", p)
+	if _, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Update(eCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package nat
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thoas/go-funk"
@@ -31,35 +32,59 @@ import (
 	listerv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
 	"github.com/inwinstack/blended/k8sutil"
 	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/guardrail"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
 	"github.com/inwinstack/pango/poli/nat"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+// Client represents the NAT policies of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (nat.Entry, error)
+	Edit(vsys string, e nat.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
 // Controller represents the controller of nat
 type Controller struct {
 	cfg        *config.Config
-	fwNat      *nat.FwNat
+	fwNat      Client
 	blendedset blended.Interface
 	lister     listerv1.NATLister
 	synced     cache.InformerSynced
 	queue      workqueue.RateLimitingInterface
 
-	commit chan bool
+	commit   chan *commit.Object
+	locker   *lock.Locker
+	resolver *dependency.Resolver
+	guard    *guardrail.Guard
 }
 
 // NewController creates an instance of the nat controller
 func NewController(
 	cfg *config.Config,
-	fwNat *nat.FwNat,
+	fwNat Client,
 	blendedset blended.Interface,
 	informer informerv1.NATInformer,
-	commit chan bool) *Controller {
+	commit chan *commit.Object,
+	locker *lock.Locker,
+	resolver *dependency.Resolver,
+	guard *guardrail.Guard) *Controller {
 	controller := &Controller{
 		cfg:        cfg,
 		blendedset: blendedset,
@@ -68,6 +93,9 @@ func NewController(
 		synced:     informer.Informer().HasSynced,
 		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NATs"),
 		commit:     commit,
+		locker:     locker,
+		resolver:   resolver,
+		guard:      guard,
 	}
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueue,
@@ -78,6 +106,8 @@ func NewController(
 			controller.enqueue(no)
 		},
 	})
+	resolver.OnDependencyChange(controller.enqueueWaitingOn)
+	guard.OnPolicyChange(controller.enqueueNamespaces)
 	return controller
 }
 
@@ -85,7 +115,7 @@ func NewController(
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	glog.Info("Starting the nat controller")
 	glog.Info("Waiting for the nat informer caches to sync")
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), append(append(c.resolver.Synced(), c.guard.Synced()...), c.synced)...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -148,6 +178,38 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.Add(key)
 }
 
+// enqueueWaitingOn enqueues the NATs waiting for the given object, either referenced or in a
+// referenced group, so that they're reconciled once it becomes active.
+func (c *Controller) enqueueWaitingOn(name string) {
+	nats, err := c.lister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, nat := range nats {
+		if nat.Status.Phase == dependency.Phase && funk.ContainsString(c.resolver.Resolve(dependency.NATRefs(nat.Spec)), name) {
+			c.enqueue(nat)
+		}
+	}
+}
+
+// enqueueNamespaces enqueues all the NATs of the namespaces selected by a changed firewall
+// policy, so that they're checked against the policies again, including the active ones.
+func (c *Controller) enqueueNamespaces(namespaces []string) {
+	for _, ns := range namespaces {
+		nats, err := c.lister.NATs(ns).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, nat := range nats {
+			c.enqueue(nat)
+		}
+	}
+}
+
 func (c *Controller) reconcile(key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -164,6 +226,11 @@ func (c *Controller) reconcile(key string) error {
 		return err
 	}
 
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(nat.ObjectMeta, "") != c.cfg.Firewall {
+		return nil
+	}
+
 	if !nat.ObjectMeta.DeletionTimestamp.IsZero() {
 		if err := c.cleanup(nat); err != nil {
 			return err
@@ -183,16 +250,24 @@ func (c *Controller) reconcile(key string) error {
 				return nil
 			}
 		}
+		violations, err := c.guard.NAT(nat.Namespace, nat.Spec)
+		if err != nil {
+			return err
+		}
+		if len(violations) != 0 {
+			return c.makeViolation(nat, violations)
+		}
+		if waiting := c.resolver.Waiting(dependency.NATRefs(nat.Spec)); len(waiting) != 0 {
+			return c.makeWaiting(nat, waiting)
+		}
 		if err := c.createOrUpdate(nat); err != nil {
 			return c.makeFailed(nat, err)
 		}
 		return nil
 	}
 
-	if nat.Status.Phase == blendedv1.NATActive && !c.isExistingNatPolicy(nat) {
-		if err := c.createOrUpdate(nat); err != nil {
-			return c.makeFailed(nat, err)
-		}
+	if nat.Status.Phase == blendedv1.NATActive {
+		return c.checkDrift(nat)
 	}
 	return nil
 }
@@ -209,6 +284,40 @@ func (c *Controller) checkAndUdateFinalizer(nat *blendedv1.NAT) error {
 	return nil
 }
 
+func (c *Controller) makeWaiting(nat *blendedv1.NAT, waiting []string) error {
+	reason := fmt.Sprintf("waiting for the dependencies: %s", strings.Join(waiting, ", "))
+	if nat.Status.Phase == dependency.Phase && nat.Status.Reason == reason {
+		return nil
+	}
+
+	natCopy := nat.DeepCopy()
+	natCopy.Status.Reason = reason
+	natCopy.Status.Phase = dependency.Phase
+	natCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.blendedset.InwinstackV1().NATs(natCopy.Namespace).Update(natCopy); err != nil {
+		return err
+	}
+	glog.V(3).Infof("NAT '%s/%s' is waiting for the dependencies: %v.", nat.Namespace, nat.Name, waiting)
+	return nil
+}
+
+func (c *Controller) makeViolation(nat *blendedv1.NAT, violations field.ErrorList) error {
+	reason := violations.ToAggregate().Error()
+	if nat.Status.Phase == guardrail.Phase && nat.Status.Reason == reason {
+		return nil
+	}
+
+	natCopy := nat.DeepCopy()
+	natCopy.Status.Reason = reason
+	natCopy.Status.Phase = guardrail.Phase
+	natCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.blendedset.InwinstackV1().NATs(natCopy.Namespace).Update(natCopy); err != nil {
+		return err
+	}
+	glog.Warningf("NAT '%s/%s' violates the firewall policies: %s.", nat.Namespace, nat.Name, reason)
+	return nil
+}
+
 func (c *Controller) makeFailed(nat *blendedv1.NAT, e error) error {
 	natCopy := nat.DeepCopy()
 	natCopy.Status.Reason = e.Error()
@@ -223,6 +332,10 @@ func (c *Controller) makeFailed(nat *blendedv1.NAT, e error) error {
 }
 
 func (c *Controller) createOrUpdate(nat *blendedv1.NAT) error {
+	if c.cfg.DryRun {
+		return c.planNatPolicy(nat, false)
+	}
+
 	natCopy := nat.DeepCopy()
 	if err := c.updateNatPolicy(natCopy); err != nil {
 		return err
@@ -232,6 +345,9 @@ func (c *Controller) createOrUpdate(nat *blendedv1.NAT) error {
 	natCopy.Status.Phase = blendedv1.NATActive
 	natCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
 	delete(natCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&natCopy.ObjectMeta)
+	naming.Record(&natCopy.ObjectMeta, naming.EntryName(c.cfg.NamingScheme, nat.ObjectMeta))
+	drift.Record(&natCopy.ObjectMeta, nil)
 	k8sutil.AddFinalizer(&natCopy.ObjectMeta, constants.CustomFinalizer)
 	if _, err := c.blendedset.InwinstackV1().NATs(natCopy.Namespace).Update(natCopy); err != nil {
 		return err
@@ -240,6 +356,10 @@ func (c *Controller) createOrUpdate(nat *blendedv1.NAT) error {
 }
 
 func (c *Controller) cleanup(nat *blendedv1.NAT) error {
+	if c.cfg.DryRun {
+		return c.planNatPolicy(nat, true)
+	}
+
 	natCopy := nat.DeepCopy()
 	if err := c.deleteNatPolicy(natCopy); err != nil {
 		return err
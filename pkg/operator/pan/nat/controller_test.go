@@ -25,7 +25,9 @@ import (
 	"github.com/inwinstack/blended/constants"
 	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	paconstants "github.com/inwinstack/pa-controller/pkg/constants"
 	"github.com/inwinstack/pango/poli/nat"
 	"github.com/inwinstack/pango/testdata"
 	"github.com/stretchr/testify/assert"
@@ -34,11 +36,11 @@ import (
 
 const timeout = 2 * time.Second
 
-func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
 	for {
 		select {
 		case c := <-commit:
-			assert.Equal(t, true, c)
+			assert.NotNil(t, c)
 		case <-stopCh:
 			return
 		}
@@ -47,7 +49,7 @@ func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
 
 func TestNATController(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
-	commit := make(chan bool, 1)
+	commit := make(chan *commit.Object, 1)
 	cfg := &config.Config{Threads: 2, Retry: 5}
 	blendedset := blendedfake.NewSimpleClientset()
 	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
@@ -57,7 +59,7 @@ func TestNATController(t *testing.T) {
 	fwNat := &nat.FwNat{}
 	fwNat.Initialize(mc)
 
-	controller := NewController(cfg, fwNat, blendedset, informer.Inwinstack().V1().NATs(), commit)
+	controller := NewController(cfg, fwNat, blendedset, informer.Inwinstack().V1().NATs(), commit, nil, nil, nil)
 	go informer.Start(ctx.Done())
 	go commitSignal(t, controller.commit, ctx.Done())
 	assert.Nil(t, controller.Run(ctx, cfg.Threads))
@@ -117,3 +119,56 @@ func TestNATController(t *testing.T) {
 	mc.Reset()
 	controller.Stop()
 }
+
+func TestNATControllerConflict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, NamingScheme: "name"}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	fwNat := &nat.FwNat{}
+	fwNat.Initialize(mc)
+
+	controller := NewController(cfg, fwNat, blendedset, informer.Inwinstack().V1().NATs(), commit, nil, nil, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	// The NATs of the same name in different namespaces have the same entry on PAN.
+	for _, namespace := range []string{"team-a", "team-b"} {
+		nat := &blendedv1.NAT{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+			Spec:       blendedv1.NATSpec{Type: blendedv1.NATIPv4},
+		}
+		_, err := blendedset.InwinstackV1().NATs(namespace).Create(nat)
+		assert.Nil(t, err)
+
+		phase := blendedv1.NATActive
+		if namespace == "team-b" {
+			phase = blendedv1.NATFailed
+		}
+
+		failed := true
+		for start := time.Now(); time.Since(start) < timeout; {
+			gnat, err := blendedset.InwinstackV1().NATs(namespace).Get(nat.Name, metav1.GetOptions{})
+			assert.Nil(t, err)
+
+			if gnat.Status.Phase == phase {
+				if phase == blendedv1.NATActive {
+					assert.Equal(t, "web", gnat.Annotations[paconstants.EntryNameKey])
+				} else {
+					assert.Equal(t, "the entry 'web' on PAN is owned by 'team-a/web'", gnat.Status.Reason)
+				}
+				failed = false
+				break
+			}
+		}
+		assert.Equal(t, false, failed, "The nat policy hasn't been %s.", phase)
+	}
+
+	cancel()
+	controller.Stop()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nat
+
+import (
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// checkDrift compares the NAT policy on the firewall with the resource field by field. The
+// missing NAT policy is created, and the drifted one is either re-applied or reported by
+// the drift policy of the resource. The resource is checked against the firewall policies
+// first, in case a policy changed after it was pushed, and then it's neither re-applied nor
+// re-created.
+func (c *Controller) checkDrift(n *blendedv1.NAT) error {
+	violations, err := c.guard.NAT(n.Namespace, n.Spec)
+	if err != nil {
+		return err
+	}
+	if len(violations) != 0 {
+		return c.makeViolation(n, violations)
+	}
+
+	current, err := c.fwNat.Get(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, n.ObjectMeta))
+	if err != nil || current.Name == "" {
+		if err := c.createOrUpdate(n); err != nil {
+			return c.makeFailed(n, err)
+		}
+		return nil
+	}
+
+	fields := drift.Fields(plan.Diff(current, *c.newNatPolicy(n)))
+	if len(fields) != 0 && drift.Policy(n.ObjectMeta, c.cfg.DriftPolicy) == drift.PolicyReapply {
+		// The spec is re-applied at most once within the sync seconds, in case PAN keeps
+		// returning the entry differently.
+		t := util.SubtractNowTime(n.Status.LastUpdateTime.Time)
+		if t.Seconds() <= float64(c.cfg.SyncSec) {
+			return nil
+		}
+
+		glog.Infof("NAT '%s' drifted on %v, re-applying the spec.", n.Name, fields)
+		if err := c.createOrUpdate(n); err != nil {
+			return c.makeFailed(n, err)
+		}
+		return nil
+	}
+
+	nCopy := n.DeepCopy()
+	if !drift.Record(&nCopy.ObjectMeta, fields) {
+		return nil
+	}
+
+	if len(fields) != 0 {
+		glog.Warningf("NAT '%s' drifted on %v.", n.Name, fields)
+	}
+	if _, err := c.blendedset.InwinstackV1().NATs(n.Namespace).Update(nCopy); err != nil {
+		return err
+	}
+	return nil
+}
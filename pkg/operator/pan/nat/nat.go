@@ -18,12 +18,20 @@ package nat
 
 import (
 	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
 	"github.com/inwinstack/pango/poli/nat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-func (c *Controller) newNatPolicy(n *blendedv1.NAT) *nat.Entry {
+// NewPolicy returns the NAT policy of a NAT resource.
+func NewPolicy(cfg *config.Config, n *blendedv1.NAT) *nat.Entry {
 	entry := &nat.Entry{
-		Name:                           n.Name,
+		Name:                           naming.EntryName(cfg.NamingScheme, n.ObjectMeta),
 		Description:                    n.Spec.Description,
 		Type:                           n.Spec.Type,
 		SourceZones:                    n.Spec.SourceZones,
@@ -51,14 +59,34 @@ func (c *Controller) newNatPolicy(n *blendedv1.NAT) *nat.Entry {
 		Disabled:                       n.Spec.Disabled,
 		Targets:                        n.Spec.Targets,
 		NegateTarget:                   n.Spec.NegateTarget,
-		Tags:                           n.Spec.Tags,
+		Tags:                           ownership.AddTags(n.Spec.Tags, cfg.ClusterID),
 	}
 	entry.Defaults()
 	return entry
 }
 
+func (c *Controller) newNatPolicy(n *blendedv1.NAT) *nat.Entry {
+	return NewPolicy(c.cfg, n)
+}
+
+// checkConflict returns an error when the NAT policy of a resource is owned by another NAT.
+func (c *Controller) checkConflict(n *blendedv1.NAT) error {
+	nats, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	others := []metav1.ObjectMeta{}
+	for _, o := range nats {
+		if firewall.Ref(o.ObjectMeta, "") == c.cfg.Firewall {
+			others = append(others, o.ObjectMeta)
+		}
+	}
+	return naming.Conflict(c.cfg.NamingScheme, n.ObjectMeta, others)
+}
+
 func (c *Controller) isExistingNatPolicy(nat *blendedv1.NAT) bool {
-	if entry, err := c.fwNat.Get(c.cfg.Vsys, nat.Name); err == nil {
+	if entry, err := c.fwNat.Get(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, nat.ObjectMeta)); err == nil {
 		if len(entry.Name) != 0 {
 			return true
 		}
@@ -67,11 +95,20 @@ func (c *Controller) isExistingNatPolicy(nat *blendedv1.NAT) bool {
 }
 
 func (c *Controller) updateNatPolicy(nat *blendedv1.NAT) error {
+	if err := c.checkConflict(nat); err != nil {
+		return err
+	}
+
 	entry := c.newNatPolicy(nat)
-	if err := c.fwNat.Edit(c.cfg.Vsys, *entry); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.fwNat.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindNAT, nat.ObjectMeta)
 	return nil
 }
 
@@ -80,9 +117,14 @@ func (c *Controller) deleteNatPolicy(nat *blendedv1.NAT) error {
 		return nil
 	}
 
-	if err := c.fwNat.Delete(c.cfg.Vsys, nat.Name); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.fwNat.Delete(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, nat.ObjectMeta)); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindNAT, nat.ObjectMeta)
 	return nil
 }
@@ -0,0 +1,55 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nat
+
+import (
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// planNatPolicy records the change of the NAT policy on the resource in the dry-run mode, where
+// the firewall isn't edited.
+func (c *Controller) planNatPolicy(n *blendedv1.NAT, deleting bool) error {
+	name := naming.EntryName(c.cfg.NamingScheme, n.ObjectMeta)
+	current, err := c.fwNat.Get(c.cfg.Vsys, name)
+	if err != nil && !plan.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil && current.Name != ""
+
+	p := plan.Delete(name, exists)
+	if !deleting {
+		entry := c.newNatPolicy(n)
+		p = plan.Create(name, *entry)
+		if exists {
+			p = plan.Update(name, current, *entry)
+		}
+	}
+
+	nCopy := n.DeepCopy()
+	if !plan.Record(&nCopy.ObjectMeta, p) {
+		return nil
+	}
+
+	glog.Infof("NAT dry-run plan: %s.", p)
+	if _, err := c.blendedset.InwinstackV1().NATs(n.Namespace).Update(nCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// record records the commit result on the resources included in the commit, and the resources
+// are marked failed with the commit error when the commit fails.
+func (c *Controller) record(objects []*commit.Object, result *commit.Result, commitErr error) {
+	for _, obj := range objects {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			return c.recordObject(obj, result, commitErr)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			glog.Errorf("Failed to record the commit result on '%s': %+v.", obj, err)
+		}
+	}
+
+	if commitErr != nil {
+		glog.Errorf("Commit job %d failed: %+v.", result.JobID, result.Messages)
+	}
+}
+
+func (c *Controller) recordObject(obj *commit.Object, result *commit.Result, commitErr error) error {
+	opts := metav1.GetOptions{}
+	reason := ""
+	if commitErr != nil {
+		reason = commitErr.Error()
+	}
+	now := metav1.NewTime(time.Now())
+	switch obj.Kind {
+	case commit.KindSecurity:
+		sec, err := c.blendedset.InwinstackV1().Securities(obj.Namespace).Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		secCopy := sec.DeepCopy()
+		result.Annotate(&secCopy.ObjectMeta)
+		if commitErr != nil {
+			secCopy.Status.Phase = blendedv1.SecurityFailed
+			secCopy.Status.Reason = reason
+			secCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.blendedset.InwinstackV1().Securities(obj.Namespace).Update(secCopy)
+		return err
+	case commit.KindNAT:
+		nat, err := c.blendedset.InwinstackV1().NATs(obj.Namespace).Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		natCopy := nat.DeepCopy()
+		result.Annotate(&natCopy.ObjectMeta)
+		if commitErr != nil {
+			natCopy.Status.Phase = blendedv1.NATFailed
+			natCopy.Status.Reason = reason
+			natCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.blendedset.InwinstackV1().NATs(obj.Namespace).Update(natCopy)
+		return err
+	case commit.KindService:
+		svc, err := c.blendedset.InwinstackV1().Services().Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		svcCopy := svc.DeepCopy()
+		result.Annotate(&svcCopy.ObjectMeta)
+		if commitErr != nil {
+			svcCopy.Status.Phase = blendedv1.ServiceFailed
+			svcCopy.Status.Reason = reason
+			svcCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.blendedset.InwinstackV1().Services().Update(svcCopy)
+		return err
+	case commit.KindServiceGroup:
+		grp, err := c.inwinset.InwinstackV1().ServiceGroups().Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		grpCopy := grp.DeepCopy()
+		result.Annotate(&grpCopy.ObjectMeta)
+		if commitErr != nil {
+			grpCopy.Status.Phase = inwinv1.ServiceGroupFailed
+			grpCopy.Status.Reason = reason
+			grpCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy)
+		return err
+	case commit.KindAddress:
+		addr, err := c.inwinset.InwinstackV1().Addresses().Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		addrCopy := addr.DeepCopy()
+		result.Annotate(&addrCopy.ObjectMeta)
+		if commitErr != nil {
+			addrCopy.Status.Phase = inwinv1.AddressFailed
+			addrCopy.Status.Reason = reason
+			addrCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.inwinset.InwinstackV1().Addresses().Update(addrCopy)
+		return err
+	case commit.KindAddressGroup:
+		grp, err := c.inwinset.InwinstackV1().AddressGroups().Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		grpCopy := grp.DeepCopy()
+		result.Annotate(&grpCopy.ObjectMeta)
+		if commitErr != nil {
+			grpCopy.Status.Phase = inwinv1.AddressGroupFailed
+			grpCopy.Status.Reason = reason
+			grpCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.inwinset.InwinstackV1().AddressGroups().Update(grpCopy)
+		return err
+	case commit.KindExternalDynamicList:
+		edl, err := c.inwinset.InwinstackV1().ExternalDynamicLists().Get(obj.Name, opts)
+		if err != nil {
+			return err
+		}
+		edlCopy := edl.DeepCopy()
+		result.Annotate(&edlCopy.ObjectMeta)
+		if commitErr != nil {
+			edlCopy.Status.Phase = inwinv1.ExternalDynamicListFailed
+			edlCopy.Status.Reason = reason
+			edlCopy.Status.LastUpdateTime = now
+		}
+		_, err = c.inwinset.InwinstackV1().ExternalDynamicLists().Update(edlCopy)
+		return err
+	}
+	return fmt.Errorf("unknown kind '%s'", obj.Kind)
+}
@@ -19,6 +19,7 @@ package security
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thoas/go-funk"
@@ -31,34 +32,59 @@ import (
 	listerv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
 	"github.com/inwinstack/blended/k8sutil"
 	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/guardrail"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
 	"github.com/inwinstack/pango/poli/security"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+// Client represents the security policies of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (security.Entry, error)
+	Edit(vsys string, e security.Entry) error
+	MoveGroup(vsys string, movement int, rule string, e ...security.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
 // Controller represents the controller of security
 type Controller struct {
 	cfg        *config.Config
-	fwSec      *security.FwSecurity
+	fwSec      Client
 	blendedset blended.Interface
 	lister     listerv1.SecurityLister
 	synced     cache.InformerSynced
 	queue      workqueue.RateLimitingInterface
-	commit     chan bool
+	commit     chan *commit.Object
+	locker     *lock.Locker
+	resolver   *dependency.Resolver
+	guard      *guardrail.Guard
 }
 
 // NewController creates an instance of the security controller
 func NewController(
 	cfg *config.Config,
-	fwSec *security.FwSecurity,
+	fwSec Client,
 	blendedset blended.Interface,
 	informer informerv1.SecurityInformer,
-	commit chan bool) *Controller {
+	commit chan *commit.Object,
+	locker *lock.Locker,
+	resolver *dependency.Resolver,
+	guard *guardrail.Guard) *Controller {
 	controller := &Controller{
 		cfg:        cfg,
 		fwSec:      fwSec,
@@ -67,6 +93,9 @@ func NewController(
 		synced:     informer.Informer().HasSynced,
 		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Securities"),
 		commit:     commit,
+		locker:     locker,
+		resolver:   resolver,
+		guard:      guard,
 	}
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueue,
@@ -77,6 +106,8 @@ func NewController(
 			controller.enqueue(no)
 		},
 	})
+	resolver.OnDependencyChange(controller.enqueueWaitingOn)
+	guard.OnPolicyChange(controller.enqueueNamespaces)
 	return controller
 }
 
@@ -84,7 +115,7 @@ func NewController(
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	glog.Info("Starting the security controller")
 	glog.Info("Waiting for the security informer caches to sync")
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), append(append(c.resolver.Synced(), c.guard.Synced()...), c.synced)...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -147,6 +178,38 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.Add(key)
 }
 
+// enqueueWaitingOn enqueues the securities waiting for the given object, either referenced or
+// in a referenced group, so that they're reconciled once it becomes active.
+func (c *Controller) enqueueWaitingOn(name string) {
+	secs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, sec := range secs {
+		if sec.Status.Phase == dependency.Phase && funk.ContainsString(c.resolver.Resolve(dependency.SecurityRefs(sec.Spec)), name) {
+			c.enqueue(sec)
+		}
+	}
+}
+
+// enqueueNamespaces enqueues all the securities of the namespaces selected by a changed firewall
+// policy, so that they're checked against the policies again, including the active ones.
+func (c *Controller) enqueueNamespaces(namespaces []string) {
+	for _, ns := range namespaces {
+		secs, err := c.lister.Securities(ns).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+
+		for _, sec := range secs {
+			c.enqueue(sec)
+		}
+	}
+}
+
 func (c *Controller) reconcile(key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -163,6 +226,11 @@ func (c *Controller) reconcile(key string) error {
 		return err
 	}
 
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(security.ObjectMeta, "") != c.cfg.Firewall {
+		return nil
+	}
+
 	if !security.ObjectMeta.DeletionTimestamp.IsZero() {
 		if err := c.cleanup(security); err != nil {
 			return err
@@ -182,16 +250,24 @@ func (c *Controller) reconcile(key string) error {
 				return nil
 			}
 		}
+		violations, err := c.guard.Security(security.Namespace, security.Spec)
+		if err != nil {
+			return err
+		}
+		if len(violations) != 0 {
+			return c.makeViolation(security, violations)
+		}
+		if waiting := c.resolver.Waiting(dependency.SecurityRefs(security.Spec)); len(waiting) != 0 {
+			return c.makeWaiting(security, waiting)
+		}
 		if err := c.createOrUpdate(security); err != nil {
 			return c.makeFailed(security, err)
 		}
 		return nil
 	}
 
-	if security.Status.Phase == blendedv1.SecurityActive && !c.isExistingSecurityPolicy(security) {
-		if err := c.createOrUpdate(security); err != nil {
-			return c.makeFailed(security, err)
-		}
+	if security.Status.Phase == blendedv1.SecurityActive {
+		return c.checkDrift(security)
 	}
 	return nil
 }
@@ -208,6 +284,40 @@ func (c *Controller) checkAndUdateFinalizer(sec *blendedv1.Security) error {
 	return nil
 }
 
+func (c *Controller) makeWaiting(sec *blendedv1.Security, waiting []string) error {
+	reason := fmt.Sprintf("waiting for the dependencies: %s", strings.Join(waiting, ", "))
+	if sec.Status.Phase == dependency.Phase && sec.Status.Reason == reason {
+		return nil
+	}
+
+	secCopy := sec.DeepCopy()
+	secCopy.Status.Reason = reason
+	secCopy.Status.Phase = dependency.Phase
+	secCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.blendedset.InwinstackV1().Securities(secCopy.Namespace).Update(secCopy); err != nil {
+		return err
+	}
+	glog.V(3).Infof("Security '%s/%s' is waiting for the dependencies: %v.", sec.Namespace, sec.Name, waiting)
+	return nil
+}
+
+func (c *Controller) makeViolation(sec *blendedv1.Security, violations field.ErrorList) error {
+	reason := violations.ToAggregate().Error()
+	if sec.Status.Phase == guardrail.Phase && sec.Status.Reason == reason {
+		return nil
+	}
+
+	secCopy := sec.DeepCopy()
+	secCopy.Status.Reason = reason
+	secCopy.Status.Phase = guardrail.Phase
+	secCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.blendedset.InwinstackV1().Securities(secCopy.Namespace).Update(secCopy); err != nil {
+		return err
+	}
+	glog.Warningf("Security '%s/%s' violates the firewall policies: %s.", sec.Namespace, sec.Name, reason)
+	return nil
+}
+
 func (c *Controller) makeFailed(sec *blendedv1.Security, e error) error {
 	secCopy := sec.DeepCopy()
 	secCopy.Status.Reason = e.Error()
@@ -222,6 +332,10 @@ func (c *Controller) makeFailed(sec *blendedv1.Security, e error) error {
 }
 
 func (c *Controller) createOrUpdate(sec *blendedv1.Security) error {
+	if c.cfg.DryRun {
+		return c.planSecurityPolicy(sec, false)
+	}
+
 	secCopy := sec.DeepCopy()
 	if err := c.updateSecurityPolicy(secCopy); err != nil {
 		return err
@@ -231,6 +345,9 @@ func (c *Controller) createOrUpdate(sec *blendedv1.Security) error {
 	secCopy.Status.Phase = blendedv1.SecurityActive
 	secCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
 	delete(secCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&secCopy.ObjectMeta)
+	naming.Record(&secCopy.ObjectMeta, naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta))
+	drift.Record(&secCopy.ObjectMeta, nil)
 	k8sutil.AddFinalizer(&secCopy.ObjectMeta, constants.CustomFinalizer)
 	if _, err := c.blendedset.InwinstackV1().Securities(secCopy.Namespace).Update(secCopy); err != nil {
 		return err
@@ -239,6 +356,10 @@ func (c *Controller) createOrUpdate(sec *blendedv1.Security) error {
 }
 
 func (c *Controller) cleanup(sec *blendedv1.Security) error {
+	if c.cfg.DryRun {
+		return c.planSecurityPolicy(sec, true)
+	}
+
 	secCopy := sec.DeepCopy()
 	if err := c.deleteSecurityPolicy(secCopy); err != nil {
 		return err
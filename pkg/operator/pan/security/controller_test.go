@@ -25,21 +25,31 @@ import (
 	"github.com/inwinstack/blended/constants"
 	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	paconstants "github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/guardrail"
 	"github.com/inwinstack/pango/poli/security"
 	"github.com/inwinstack/pango/testdata"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/stretchr/testify/assert"
 )
 
 const timeout = 2 * time.Second
 
-func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
 	for {
 		select {
 		case c := <-commit:
-			assert.Equal(t, true, c)
+			assert.NotNil(t, c)
 		case <-stopCh:
 			return
 		}
@@ -48,7 +58,7 @@ func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
 
 func TestSecurityController(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
-	commit := make(chan bool, 1)
+	commit := make(chan *commit.Object, 1)
 	cfg := &config.Config{Threads: 2, Retry: 5}
 	blendedset := blendedfake.NewSimpleClientset()
 	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
@@ -58,7 +68,7 @@ func TestSecurityController(t *testing.T) {
 	fwSec := &security.FwSecurity{}
 	fwSec.Initialize(mc)
 
-	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit)
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, nil, nil)
 	go informer.Start(ctx.Done())
 	go commitSignal(t, controller.commit, ctx.Done())
 	assert.Nil(t, controller.Run(ctx, cfg.Threads))
@@ -119,3 +129,362 @@ func TestSecurityController(t *testing.T) {
 	mc.Reset()
 	controller.Stop()
 }
+
+func TestSecurityControllerPosition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, SyncSec: 60}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	fwSec := &security.FwSecurity{}
+	fwSec.Initialize(mc)
+
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, nil, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	namespace := "default"
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-sec",
+			Namespace:   namespace,
+			Annotations: map[string]string{paconstants.PositionKey: "before deny-all"},
+		},
+		Spec: blendedv1.SecuritySpec{Action: blendedv1.SecurityAllow},
+	}
+	_, err := blendedset.InwinstackV1().Securities(namespace).Create(sec)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+
+		if gsec.Status.Phase == blendedv1.SecurityFailed {
+			assert.Equal(t, "the rule 'deny-all' of the position doesn't exist", gsec.Status.Reason)
+			assert.Equal(t, "", mc.Function)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The security policy hasn't failed.")
+
+	cancel()
+	controller.Stop()
+}
+
+func TestSecurityControllerDependencies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinfake.NewSimpleClientset(), 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwSec := &security.FwSecurity{}
+	fwSec.Initialize(mc)
+
+	resolver := dependency.NewResolver(cfg, informer, inwinInformer)
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, resolver, nil)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-tcp80"},
+		Status:     blendedv1.ServiceStatus{Phase: blendedv1.ServicePending},
+	}
+	_, err := blendedset.InwinstackV1().Services().Create(svc)
+	assert.Nil(t, err)
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	namespace := "default"
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "allow-web",
+			Namespace: namespace,
+		},
+		Spec: blendedv1.SecuritySpec{
+			SourceAddresses:      []string{"any"},
+			DestinationAddresses: []string{"140.23.110.10"},
+			Services:             []string{"k8s-tcp80"},
+			Action:               blendedv1.SecurityAllow,
+		},
+	}
+
+	mc.Reset()
+	_, err = blendedset.InwinstackV1().Securities(namespace).Create(sec)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == dependency.Phase {
+			assert.Equal(t, "waiting for the dependencies: Service/k8s-tcp80", gsec.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The security policy hasn't waited for the service.")
+	assert.Equal(t, "", mc.Function)
+
+	// The security policy is pushed once the service becomes active.
+	mc.AddResp("")
+	svc.Status.Phase = blendedv1.ServiceActive
+	_, err = blendedset.InwinstackV1().Services().Update(svc)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == blendedv1.SecurityActive {
+			assert.Equal(t, "", gsec.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The security policy hasn't been pushed.")
+
+	cancel()
+	mc.Reset()
+	controller.Stop()
+}
+
+func TestSecurityControllerPolicyViolationZones(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset(&inwinv1.FirewallPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+		Spec:       inwinv1.FirewallPolicySpec{AllowedZones: []string{"trust", "dmz"}},
+	})
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	), 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwSec := &security.FwSecurity{}
+	fwSec.Initialize(mc)
+
+	guard := guardrail.NewGuard(kubeInformer, inwinInformer)
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, nil, guard)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	// The empty zones are any, which isn't in the allowed zones.
+	namespace := "default"
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "allow-web",
+			Namespace: namespace,
+		},
+		Spec: blendedv1.SecuritySpec{
+			DestinationZones:     []string{"dmz"},
+			SourceAddresses:      []string{"any"},
+			DestinationAddresses: []string{"140.23.110.10"},
+			Services:             []string{"k8s-tcp80"},
+			Action:               blendedv1.SecurityAllow,
+		},
+	}
+
+	mc.Reset()
+	_, err := blendedset.InwinstackV1().Securities(namespace).Create(sec)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == guardrail.Phase {
+			assert.Equal(t, "spec.sourceZones: Forbidden: zone 'any' isn't allowed by the FirewallPolicy 'tenants'", gsec.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The policy violation hasn't been reported.")
+	assert.Equal(t, "", mc.Function)
+
+	controller.Stop()
+}
+
+func TestSecurityControllerPolicyViolation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+	kubeInformer := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	), 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwSec := &security.FwSecurity{}
+	fwSec.Initialize(mc)
+
+	policy := &inwinv1.FirewallPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-only"},
+		Spec:       inwinv1.FirewallPolicySpec{AllowedActions: []string{"deny", "drop"}},
+	}
+	_, err := inwinset.InwinstackV1().FirewallPolicies().Create(policy)
+	assert.Nil(t, err)
+
+	guard := guardrail.NewGuard(kubeInformer, inwinInformer)
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, nil, guard)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go kubeInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	namespace := "default"
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "allow-web",
+			Namespace: namespace,
+		},
+		Spec: blendedv1.SecuritySpec{
+			SourceAddresses:      []string{"any"},
+			DestinationAddresses: []string{"140.23.110.10"},
+			Services:             []string{"k8s-tcp80"},
+			Action:               blendedv1.SecurityAllow,
+		},
+	}
+
+	mc.Reset()
+	_, err = blendedset.InwinstackV1().Securities(namespace).Create(sec)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == guardrail.Phase {
+			assert.Equal(t, "spec.action: Forbidden: action 'allow' isn't allowed by the FirewallPolicy 'deny-only'", gsec.Status.Reason)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The policy violation hasn't been reported.")
+	assert.Equal(t, "", mc.Function)
+
+	// The security policy is pushed once the firewall policy is deleted.
+	mc.AddResp("")
+	assert.Nil(t, inwinset.InwinstackV1().FirewallPolicies().Delete(policy.Name, nil))
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == blendedv1.SecurityActive {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The security policy hasn't been pushed.")
+
+	// The active security policy is checked again when a firewall policy is created.
+	policy.ResourceVersion = ""
+	_, err = inwinset.InwinstackV1().FirewallPolicies().Create(policy)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsec.Status.Phase == guardrail.Phase {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The policy violation of the active security policy hasn't been reported.")
+
+	cancel()
+	mc.Reset()
+	controller.Stop()
+}
+
+func TestSecurityControllerDriftOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, SyncSec: 60}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "k8s-drift",
+			Namespace:  "default",
+			Finalizers: []string{constants.CustomFinalizer},
+			Annotations: map[string]string{
+				paconstants.DriftPolicyKey: "report",
+				paconstants.DriftedKey:     "SourceAddresses",
+			},
+		},
+		Spec: blendedv1.SecuritySpec{
+			SourceZones:          []string{"untrust"},
+			SourceAddresses:      []string{"10.0.0.1", "10.0.0.2"},
+			DestinationZones:     []string{"trust"},
+			DestinationAddresses: []string{"140.23.110.10", "140.23.110.11"},
+			Services:             []string{"k8s-tcp80", "k8s-tcp443"},
+			Action:               blendedv1.SecurityAllow,
+		},
+		Status: blendedv1.SecurityStatus{Phase: blendedv1.SecurityActive},
+	}
+
+	// PAN returns the same security policy with the members in another order.
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	fwSec := &security.FwSecurity{}
+	fwSec.Initialize(mc)
+	entry := NewPolicy(cfg, sec)
+	entry.SourceAddresses = []string{"10.0.0.2", "10.0.0.1"}
+	entry.DestinationAddresses = []string{"140.23.110.11", "140.23.110.10"}
+	entry.Services = []string{"k8s-tcp443", "k8s-tcp80"}
+	assert.Nil(t, fwSec.Edit(cfg.Vsys, *entry))
+	current := mc.Elm
+	mc.Resp = nil
+	mc.AddResp(current)
+
+	controller := NewController(cfg, fwSec, blendedset, informer.Inwinstack().V1().Securities(), commit, nil, nil, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	_, err := blendedset.InwinstackV1().Securities(sec.Namespace).Create(sec)
+	assert.Nil(t, err)
+
+	// The members aren't reported as drifted.
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsec, err := blendedset.InwinstackV1().Securities(sec.Namespace).Get(sec.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if _, ok := gsec.Annotations[paconstants.DriftedKey]; !ok {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The reordered members have been reported as drifted.")
+	assert.NotEqual(t, "edit", mc.Function)
+
+	cancel()
+	controller.Stop()
+}
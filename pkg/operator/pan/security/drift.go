@@ -0,0 +1,90 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/placement"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// checkDrift compares the security policy on the firewall with the resource field by field, and
+// checks its position in the rulebase. The missing security policy is created, and the drifted
+// one is either re-applied or reported by the drift policy of the resource. The resource is
+// checked against the firewall policies first, in case a policy changed after it was pushed,
+// and then it's neither re-applied nor re-created.
+func (c *Controller) checkDrift(sec *blendedv1.Security) error {
+	violations, err := c.guard.Security(sec.Namespace, sec.Spec)
+	if err != nil {
+		return err
+	}
+	if len(violations) != 0 {
+		return c.makeViolation(sec, violations)
+	}
+
+	name := naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta)
+	current, err := c.fwSec.Get(c.cfg.Vsys, name)
+	if err != nil || current.Name == "" {
+		if err := c.createOrUpdate(sec); err != nil {
+			return c.makeFailed(sec, err)
+		}
+		return nil
+	}
+
+	changes := plan.Diff(current, *c.newSecurityPolicy(sec))
+	rules, placements, err := c.checkPosition(sec)
+	if err != nil {
+		return c.makeFailed(sec, err)
+	}
+
+	if !placement.IsSatisfied(name, rules, placements) {
+		changes = append(changes, plan.Change{Field: "Position"})
+	}
+
+	fields := drift.Fields(changes)
+	if len(fields) != 0 && drift.Policy(sec.ObjectMeta, c.cfg.DriftPolicy) == drift.PolicyReapply {
+		// The spec is re-applied at most once within the sync seconds, in case PAN keeps
+		// returning the entry differently.
+		t := util.SubtractNowTime(sec.Status.LastUpdateTime.Time)
+		if t.Seconds() <= float64(c.cfg.SyncSec) {
+			return nil
+		}
+
+		glog.Infof("Security '%s' drifted on %v, re-applying the spec.", sec.Name, fields)
+		if err := c.createOrUpdate(sec); err != nil {
+			return c.makeFailed(sec, err)
+		}
+		return nil
+	}
+
+	secCopy := sec.DeepCopy()
+	if !drift.Record(&secCopy.ObjectMeta, fields) {
+		return nil
+	}
+
+	if len(fields) != 0 {
+		glog.Warningf("Security '%s' drifted on %v.", sec.Name, fields)
+	}
+	if _, err := c.blendedset.InwinstackV1().Securities(sec.Namespace).Update(secCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// planSecurityPolicy records the change of the security policy on the resource in the dry-run mode, where
+// the firewall isn't edited.
+func (c *Controller) planSecurityPolicy(sec *blendedv1.Security, deleting bool) error {
+	name := naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta)
+	current, err := c.fwSec.Get(c.cfg.Vsys, name)
+	if err != nil && !plan.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil && current.Name != ""
+
+	p := plan.Delete(name, exists)
+	if !deleting {
+		entry := c.newSecurityPolicy(sec)
+		p = plan.Create(name, *entry)
+		if exists {
+			p = plan.Update(name, current, *entry)
+		}
+	}
+
+	secCopy := sec.DeepCopy()
+	if !plan.Record(&secCopy.ObjectMeta, p) {
+		return nil
+	}
+
+	glog.Infof("Security dry-run plan: %s.", p)
+	if _, err := c.blendedset.InwinstackV1().Securities(sec.Namespace).Update(secCopy); err != nil {
+		return err
+	}
+	return nil
+}
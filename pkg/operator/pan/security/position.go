@@ -0,0 +1,84 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/importer"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/placement"
+	"github.com/thoas/go-funk"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DefaultPlacement returns the placement of a resource without a position, which is set by the
+// move flags. The imported policies are kept at their positions.
+func DefaultPlacement(cfg *config.Config, sec *blendedv1.Security) placement.Placement {
+	if importer.IsAdopted(sec.ObjectMeta) {
+		return placement.Placement{}
+	}
+	return placement.Placement{Where: cfg.MoveType, Rule: cfg.MoveRule}
+}
+
+func (c *Controller) defaultPlacement(sec *blendedv1.Security) placement.Placement {
+	return DefaultPlacement(c.cfg, sec)
+}
+
+// checkPosition returns the rules in the order of the rulebase and the placements of the
+// security policies synced to the firewall by their names on PAN. The rule of the resource
+// is at the bottom when it doesn't exist yet, and the resources with invalid positions are
+// left out except the given one.
+func (c *Controller) checkPosition(sec *blendedv1.Security) ([]string, map[string]placement.Placement, error) {
+	name := naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta)
+	p, err := placement.Of(sec.ObjectMeta, name, c.defaultPlacement(sec))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	placements := map[string]placement.Placement{name: p}
+	for _, s := range secs {
+		sname := naming.EntryName(c.cfg.NamingScheme, s.ObjectMeta)
+		if sname == name || firewall.Ref(s.ObjectMeta, "") != c.cfg.Firewall {
+			continue
+		}
+
+		if sp, err := placement.Of(s.ObjectMeta, sname, c.defaultPlacement(s)); err == nil {
+			placements[sname] = sp
+		}
+	}
+
+	rules, err := c.fwSec.GetList(c.cfg.Vsys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !funk.ContainsString(rules, name) {
+		rules = append(rules, name)
+	}
+
+	if err := placement.Check(name, rules, placements); err != nil {
+		return nil, nil, err
+	}
+	return rules, placements, nil
+}
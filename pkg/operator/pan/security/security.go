@@ -18,15 +18,24 @@ package security
 
 import (
 	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pa-controller/pkg/placement"
 	"github.com/inwinstack/pango/poli/security"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-func (c *Controller) newSecurityPolicy(sec *blendedv1.Security) *security.Entry {
+// NewPolicy returns the security policy of a Security resource.
+func NewPolicy(cfg *config.Config, sec *blendedv1.Security) *security.Entry {
 	entry := &security.Entry{
-		Name:                            sec.Name,
+		Name:                            naming.EntryName(cfg.NamingScheme, sec.ObjectMeta),
 		Type:                            sec.Spec.Type,
 		Description:                     sec.Spec.Description,
-		Tags:                            sec.Spec.Tags,
+		Tags:                            ownership.AddTags(sec.Spec.Tags, cfg.ClusterID),
 		SourceZones:                     sec.Spec.SourceZones,
 		SourceAddresses:                 sec.Spec.SourceAddresses,
 		NegateSource:                    sec.Spec.NegateSource,
@@ -61,8 +70,29 @@ func (c *Controller) newSecurityPolicy(sec *blendedv1.Security) *security.Entry
 	return entry
 }
 
+func (c *Controller) newSecurityPolicy(sec *blendedv1.Security) *security.Entry {
+	return NewPolicy(c.cfg, sec)
+}
+
+// checkConflict returns an error when the security policy of a resource is owned by another
+// Security.
+func (c *Controller) checkConflict(sec *blendedv1.Security) error {
+	secs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	others := []metav1.ObjectMeta{}
+	for _, o := range secs {
+		if firewall.Ref(o.ObjectMeta, "") == c.cfg.Firewall {
+			others = append(others, o.ObjectMeta)
+		}
+	}
+	return naming.Conflict(c.cfg.NamingScheme, sec.ObjectMeta, others)
+}
+
 func (c *Controller) isExistingSecurityPolicy(sec *blendedv1.Security) bool {
-	if entry, err := c.fwSec.Get(c.cfg.Vsys, sec.Name); err == nil {
+	if entry, err := c.fwSec.Get(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta)); err == nil {
 		if len(entry.Name) != 0 {
 			return true
 		}
@@ -71,15 +101,33 @@ func (c *Controller) isExistingSecurityPolicy(sec *blendedv1.Security) bool {
 }
 
 func (c *Controller) updateSecurityPolicy(sec *blendedv1.Security) error {
+	if err := c.checkConflict(sec); err != nil {
+		return err
+	}
+
 	entry := c.newSecurityPolicy(sec)
-	if err := c.fwSec.Edit(c.cfg.Vsys, *entry); err != nil {
+	rules, placements, err := c.checkPosition(sec)
+	if err != nil {
 		return err
 	}
 
-	if err := c.fwSec.MoveGroup(c.cfg.Vsys, c.cfg.MoveType, c.cfg.MoveRule, *entry); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.fwSec.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+
+	// The policy is only moved when it isn't at its position, so the order is kept stable.
+	if !placement.IsSatisfied(entry.Name, rules, placements) {
+		p := placements[entry.Name]
+		if err := c.fwSec.MoveGroup(c.cfg.Vsys, p.Where, p.Rule, *entry); err != nil {
+			return c.locker.Explain(err)
+		}
+	}
+	c.commit <- commit.NewObject(commit.KindSecurity, sec.ObjectMeta)
 	return nil
 }
 
@@ -88,9 +136,14 @@ func (c *Controller) deleteSecurityPolicy(sec *blendedv1.Security) error {
 		return nil
 	}
 
-	if err := c.fwSec.Delete(c.cfg.Vsys, sec.Name); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.fwSec.Delete(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, sec.ObjectMeta)); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindSecurity, sec.ObjectMeta)
 	return nil
 }
@@ -19,6 +19,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thoas/go-funk"
@@ -31,7 +32,14 @@ import (
 	listerv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
 	"github.com/inwinstack/blended/k8sutil"
 	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
 	"github.com/inwinstack/pango/objs/srvc"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,25 +49,37 @@ import (
 	"k8s.io/client-go/util/workqueue"
 )
 
+// Client represents the service objects of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (srvc.Entry, error)
+	Edit(vsys string, e srvc.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
 // Controller represents the controller of service
 type Controller struct {
 	cfg        *config.Config
-	srvc       *srvc.FwSrvc
+	srvc       Client
 	blendedset blended.Interface
 	lister     listerv1.ServiceLister
 	synced     cache.InformerSynced
 	queue      workqueue.RateLimitingInterface
 
-	commit chan bool
+	commit   chan *commit.Object
+	locker   *lock.Locker
+	resolver *dependency.Resolver
 }
 
 // NewController creates an instance of the service controller
 func NewController(
 	cfg *config.Config,
-	srvc *srvc.FwSrvc,
+	srvc Client,
 	blendedset blended.Interface,
 	informer informerv1.ServiceInformer,
-	commit chan bool) *Controller {
+	commit chan *commit.Object,
+	locker *lock.Locker,
+	resolver *dependency.Resolver) *Controller {
 	controller := &Controller{
 		cfg:        cfg,
 		srvc:       srvc,
@@ -68,6 +88,8 @@ func NewController(
 		synced:     informer.Informer().HasSynced,
 		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ServiceObjects"),
 		commit:     commit,
+		locker:     locker,
+		resolver:   resolver,
 	}
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueue,
@@ -78,6 +100,7 @@ func NewController(
 			controller.enqueue(no)
 		},
 	})
+	resolver.OnServiceReleased(controller.enqueueDeleting)
 	return controller
 }
 
@@ -85,7 +108,7 @@ func NewController(
 func (c *Controller) Run(ctx context.Context, threadiness int) error {
 	glog.Info("Starting the service controller")
 	glog.Info("Waiting for the service informer caches to sync")
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), append(c.resolver.Synced(), c.synced)...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -148,6 +171,16 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.Add(key)
 }
 
+// enqueueDeleting enqueues the deleting services of the given names, so that the services which
+// are no longer referenced are deleted.
+func (c *Controller) enqueueDeleting(names []string) {
+	for _, name := range names {
+		if svc := c.resolver.Service(name); svc != nil && !svc.DeletionTimestamp.IsZero() {
+			c.enqueue(svc)
+		}
+	}
+}
+
 func (c *Controller) reconcile(key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -164,6 +197,11 @@ func (c *Controller) reconcile(key string) error {
 		return err
 	}
 
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(service.ObjectMeta, "") != c.cfg.Firewall {
+		return nil
+	}
+
 	if !service.ObjectMeta.DeletionTimestamp.IsZero() {
 		if err := c.cleanup(service); err != nil {
 			return err
@@ -189,10 +227,8 @@ func (c *Controller) reconcile(key string) error {
 		return nil
 	}
 
-	if service.Status.Phase == blendedv1.ServiceActive && !c.isExistingServiceObject(service) {
-		if err := c.createOrUpdate(service); err != nil {
-			return c.makeFailed(service, err)
-		}
+	if service.Status.Phase == blendedv1.ServiceActive {
+		return c.checkDrift(service)
 	}
 	return nil
 }
@@ -223,6 +259,10 @@ func (c *Controller) makeFailed(svc *blendedv1.Service, e error) error {
 }
 
 func (c *Controller) createOrUpdate(svc *blendedv1.Service) error {
+	if c.cfg.DryRun {
+		return c.planServiceObject(svc, false)
+	}
+
 	svcCopy := svc.DeepCopy()
 	if err := c.updateServiceObject(svcCopy); err != nil {
 		return err
@@ -232,6 +272,8 @@ func (c *Controller) createOrUpdate(svc *blendedv1.Service) error {
 	svcCopy.Status.Phase = blendedv1.ServiceActive
 	svcCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
 	delete(svcCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&svcCopy.ObjectMeta)
+	drift.Record(&svcCopy.ObjectMeta, nil)
 	k8sutil.AddFinalizer(&svcCopy.ObjectMeta, constants.CustomFinalizer)
 	if _, err := c.blendedset.InwinstackV1().Services().Update(svcCopy); err != nil {
 		return err
@@ -239,7 +281,38 @@ func (c *Controller) createOrUpdate(svc *blendedv1.Service) error {
 	return nil
 }
 
+func (c *Controller) makeReferenced(svc *blendedv1.Service, refs []string) error {
+	reason := fmt.Sprintf("still referenced by %s", strings.Join(refs, ", "))
+	if svc.Status.Phase == blendedv1.ServiceTerminating && svc.Status.Reason == reason {
+		return nil
+	}
+
+	svcCopy := svc.DeepCopy()
+	svcCopy.Status.Reason = reason
+	svcCopy.Status.Phase = blendedv1.ServiceTerminating
+	svcCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.blendedset.InwinstackV1().Services().Update(svcCopy); err != nil {
+		return err
+	}
+	glog.V(3).Infof("Service '%s' is still referenced by %v.", svc.Name, refs)
+	return nil
+}
+
 func (c *Controller) cleanup(svc *blendedv1.Service) error {
+	refs, err := c.resolver.ServiceReferences(naming.EntryName(c.cfg.NamingScheme, svc.ObjectMeta))
+	if err != nil {
+		return err
+	}
+
+	// The service is kept on PAN and by the finalizer until nothing references it.
+	if len(refs) != 0 {
+		return c.makeReferenced(svc, refs)
+	}
+
+	if c.cfg.DryRun {
+		return c.planServiceObject(svc, true)
+	}
+
 	svcCopy := svc.DeepCopy()
 	if err := c.deleteServiceObject(svcCopy); err != nil {
 		return err
@@ -25,7 +25,12 @@ import (
 	"github.com/inwinstack/blended/constants"
 	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
 	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	"github.com/inwinstack/pa-controller/pkg/commit"
 	"github.com/inwinstack/pa-controller/pkg/config"
+	paconstants "github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
 	"github.com/inwinstack/pango/objs/srvc"
 	"github.com/inwinstack/pango/testdata"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,11 +40,11 @@ import (
 
 const timeout = 3 * time.Second
 
-func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
 	for {
 		select {
 		case c := <-commit:
-			assert.Equal(t, true, c)
+			assert.NotNil(t, c)
 		case <-stopCh:
 			return
 		}
@@ -48,7 +53,7 @@ func commitSignal(t *testing.T, commit chan bool, stopCh <-chan struct{}) {
 
 func TestServiceController(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
-	commit := make(chan bool, 1)
+	commit := make(chan *commit.Object, 1)
 	cfg := &config.Config{Threads: 2, Retry: 5}
 	blendedset := blendedfake.NewSimpleClientset()
 	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
@@ -58,7 +63,7 @@ func TestServiceController(t *testing.T) {
 	fwSrvc := &srvc.FwSrvc{}
 	fwSrvc.Initialize(mc)
 
-	controller := NewController(cfg, fwSrvc, blendedset, informer.Inwinstack().V1().Services(), commit)
+	controller := NewController(cfg, fwSrvc, blendedset, informer.Inwinstack().V1().Services(), commit, nil, nil)
 	go informer.Start(ctx.Done())
 	go commitSignal(t, controller.commit, ctx.Done())
 	assert.Nil(t, controller.Run(ctx, cfg.Threads))
@@ -110,3 +115,189 @@ func TestServiceController(t *testing.T) {
 	mc.Reset()
 	controller.Stop()
 }
+
+func TestServiceControllerDryRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, DryRun: true}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+
+	// The service object exists on the firewall with another destination port.
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<entry name="k8s-dry-run"><protocol><tcp><port>80</port></tcp></protocol></entry>`)
+	fwSrvc := &srvc.FwSrvc{}
+	fwSrvc.Initialize(mc)
+
+	controller := NewController(cfg, fwSrvc, blendedset, informer.Inwinstack().V1().Services(), commit, nil, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-dry-run"},
+		Spec: blendedv1.ServiceSpec{
+			Protocol:        "tcp",
+			DestinationPort: "80,8080",
+		},
+	}
+	_, err := blendedset.InwinstackV1().Services().Create(svc)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if p, ok := gsvc.Annotations[paconstants.PlanKey]; ok {
+			assert.Equal(t, "update k8s-dry-run: DestinationPort: 80 -> 80,8080", p)
+			assert.NotEqual(t, blendedv1.ServiceActive, gsvc.Status.Phase)
+			assert.Equal(t, 0, len(gsvc.Finalizers))
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service object hasn't been planned.")
+	assert.Equal(t, "get", mc.Function)
+
+	cancel()
+	controller.Stop()
+}
+
+func TestServiceControllerDrift(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, SyncSec: 60}
+	blendedset := blendedfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+
+	// The destination port of the service object is changed on the firewall.
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<entry name="k8s-drift"><protocol><tcp><port>80</port></tcp></protocol></entry>`)
+	fwSrvc := &srvc.FwSrvc{}
+	fwSrvc.Initialize(mc)
+
+	controller := NewController(cfg, fwSrvc, blendedset, informer.Inwinstack().V1().Services(), commit, nil, nil)
+	go informer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "k8s-drift",
+			Finalizers:  []string{constants.CustomFinalizer},
+			Annotations: map[string]string{paconstants.DriftPolicyKey: "report"},
+		},
+		Spec: blendedv1.ServiceSpec{
+			Protocol:        "tcp",
+			DestinationPort: "80,8080",
+		},
+		Status: blendedv1.ServiceStatus{Phase: blendedv1.ServiceActive},
+	}
+	_, err := blendedset.InwinstackV1().Services().Create(svc)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if fields, ok := gsvc.Annotations[paconstants.DriftedKey]; ok {
+			assert.Equal(t, "DestinationPort", fields)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The drift hasn't been reported.")
+	assert.Equal(t, "get", mc.Function)
+
+	// The spec is re-applied with the reapply policy.
+	gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	gsvc.Annotations[paconstants.DriftPolicyKey] = "reapply"
+	_, err = blendedset.InwinstackV1().Services().Update(gsvc)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if _, ok := gsvc.Annotations[paconstants.DriftedKey]; !ok {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The spec hasn't been re-applied.")
+
+	cancel()
+	controller.Stop()
+}
+
+func TestServiceControllerReferenced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5}
+	now := metav1.Now()
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+		Spec:       blendedv1.SecuritySpec{Services: []string{"k8s-tcp80"}},
+	}
+	blendedset := blendedfake.NewSimpleClientset(sec)
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinfake.NewSimpleClientset(), 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwSrvc := &srvc.FwSrvc{}
+	fwSrvc.Initialize(mc)
+
+	resolver := dependency.NewResolver(cfg, informer, inwinInformer)
+	controller := NewController(cfg, fwSrvc, blendedset, informer.Inwinstack().V1().Services(), commit, nil, resolver)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	// The service is being deleted while the security policy still references it.
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "k8s-tcp80",
+			Finalizers:        []string{constants.CustomFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Status: blendedv1.ServiceStatus{Phase: blendedv1.ServiceActive},
+	}
+	mc.Reset()
+	_, err := blendedset.InwinstackV1().Services().Create(svc)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if gsvc.Status.Phase == blendedv1.ServiceTerminating {
+			assert.Equal(t, "still referenced by Security default/allow-web", gsvc.Status.Reason)
+			assert.Equal(t, []string{constants.CustomFinalizer}, gsvc.Finalizers)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service deletion hasn't been blocked.")
+	assert.Equal(t, "", mc.Function)
+
+	// The service is deleted once the security policy is deleted.
+	mc.AddResp("")
+	assert.Nil(t, blendedset.InwinstackV1().Securities(sec.Namespace).Delete(sec.Name, nil))
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		gsvc, err := blendedset.InwinstackV1().Services().Get(svc.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if len(gsvc.Finalizers) == 0 {
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service hasn't been deleted.")
+
+	cancel()
+	controller.Stop()
+}
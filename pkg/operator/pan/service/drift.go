@@ -0,0 +1,68 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/blended/util"
+	"github.com/inwinstack/pa-controller/pkg/drift"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// checkDrift compares the service object on the firewall with the resource field by field. The
+// missing service object is created, and the drifted one is either re-applied or reported by
+// the drift policy of the resource.
+func (c *Controller) checkDrift(svc *blendedv1.Service) error {
+	current, err := c.srvc.Get(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, svc.ObjectMeta))
+	if err != nil || current.Name == "" {
+		if err := c.createOrUpdate(svc); err != nil {
+			return c.makeFailed(svc, err)
+		}
+		return nil
+	}
+
+	fields := drift.Fields(plan.Diff(current, *c.newServiceObject(svc)))
+	if len(fields) != 0 && drift.Policy(svc.ObjectMeta, c.cfg.DriftPolicy) == drift.PolicyReapply {
+		// The spec is re-applied at most once within the sync seconds, in case PAN keeps
+		// returning the entry differently.
+		t := util.SubtractNowTime(svc.Status.LastUpdateTime.Time)
+		if t.Seconds() <= float64(c.cfg.SyncSec) {
+			return nil
+		}
+
+		glog.Infof("Service '%s' drifted on %v, re-applying the spec.", svc.Name, fields)
+		if err := c.createOrUpdate(svc); err != nil {
+			return c.makeFailed(svc, err)
+		}
+		return nil
+	}
+
+	svcCopy := svc.DeepCopy()
+	if !drift.Record(&svcCopy.ObjectMeta, fields) {
+		return nil
+	}
+
+	if len(fields) != 0 {
+		glog.Warningf("Service '%s' drifted on %v.", svc.Name, fields)
+	}
+	if _, err := c.blendedset.InwinstackV1().Services().Update(svcCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -18,22 +18,31 @@ package service
 
 import (
 	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
 	"github.com/inwinstack/pango/objs/srvc"
 )
 
-func (c *Controller) newServiceObject(svc *blendedv1.Service) *srvc.Entry {
+// NewObject returns the service object of a Service resource.
+func NewObject(cfg *config.Config, svc *blendedv1.Service) *srvc.Entry {
 	return &srvc.Entry{
-		Name:            svc.Name,
+		Name:            naming.EntryName(cfg.NamingScheme, svc.ObjectMeta),
 		Protocol:        svc.Spec.Protocol,
 		SourcePort:      svc.Spec.SourcePort,
 		DestinationPort: svc.Spec.DestinationPort,
 		Description:     svc.Spec.Description,
-		Tags:            svc.Spec.Tags,
+		Tags:            ownership.AddTags(svc.Spec.Tags, cfg.ClusterID),
 	}
 }
 
+func (c *Controller) newServiceObject(svc *blendedv1.Service) *srvc.Entry {
+	return NewObject(c.cfg, svc)
+}
+
 func (c *Controller) isExistingServiceObject(svc *blendedv1.Service) bool {
-	if entry, err := c.srvc.Get(c.cfg.Vsys, svc.Name); err == nil {
+	if entry, err := c.srvc.Get(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, svc.ObjectMeta)); err == nil {
 		if len(entry.Name) != 0 {
 			return true
 		}
@@ -43,10 +52,15 @@ func (c *Controller) isExistingServiceObject(svc *blendedv1.Service) bool {
 
 func (c *Controller) updateServiceObject(svc *blendedv1.Service) error {
 	entry := c.newServiceObject(svc)
-	if err := c.srvc.Edit(c.cfg.Vsys, *entry); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.srvc.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindService, svc.ObjectMeta)
 	return nil
 }
 
@@ -55,9 +69,14 @@ func (c *Controller) deleteServiceObject(svc *blendedv1.Service) error {
 		return nil
 	}
 
-	if err := c.srvc.Delete(c.cfg.Vsys, svc.Name); err != nil {
+	if err := c.locker.Acquire(); err != nil {
 		return err
 	}
-	c.commit <- true
+	defer c.locker.Done()
+
+	if err := c.srvc.Delete(c.cfg.Vsys, naming.EntryName(c.cfg.NamingScheme, svc.ObjectMeta)); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindService, svc.ObjectMeta)
 	return nil
 }
@@ -0,0 +1,344 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicegroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/thoas/go-funk"
+
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/blended/constants"
+	blendedinformerv1 "github.com/inwinstack/blended/generated/informers/externalversions/inwinstack/v1"
+	blendedlisterv1 "github.com/inwinstack/blended/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/blended/k8sutil"
+	"github.com/inwinstack/blended/util"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/firewall"
+	inwin "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned"
+	informerv1 "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions/inwinstack/v1"
+	listerv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/lock"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+	"github.com/inwinstack/pango/objs/srvcgrp"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Client represents the service groups of a firewall or a device group of Panorama.
+type Client interface {
+	GetList(vsys string) ([]string, error)
+	Get(vsys, name string) (srvcgrp.Entry, error)
+	Edit(vsys string, e srvcgrp.Entry) error
+	Delete(vsys string, e ...interface{}) error
+}
+
+// Controller represents the controller of service group
+type Controller struct {
+	cfg       *config.Config
+	srvcgrp   Client
+	inwinset  inwin.Interface
+	lister    listerv1.ServiceGroupLister
+	svcLister blendedlisterv1.ServiceLister
+	synced    cache.InformerSynced
+	svcSynced cache.InformerSynced
+	queue     workqueue.RateLimitingInterface
+
+	commit chan *commit.Object
+	locker *lock.Locker
+}
+
+// NewController creates an instance of the service group controller
+func NewController(
+	cfg *config.Config,
+	srvcgrp Client,
+	inwinset inwin.Interface,
+	informer informerv1.ServiceGroupInformer,
+	svcInformer blendedinformerv1.ServiceInformer,
+	commit chan *commit.Object,
+	locker *lock.Locker) *Controller {
+	controller := &Controller{
+		cfg:       cfg,
+		srvcgrp:   srvcgrp,
+		inwinset:  inwinset,
+		lister:    informer.Lister(),
+		svcLister: svcInformer.Lister(),
+		synced:    informer.Informer().HasSynced,
+		svcSynced: svcInformer.Informer().HasSynced,
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ServiceGroups"),
+		commit:    commit,
+		locker:    locker,
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oo := old.(*inwinv1.ServiceGroup)
+			no := new.(*inwinv1.ServiceGroup)
+			k8sutil.MakeNeedToUpdate(&no.ObjectMeta, oo.Spec, no.Spec)
+			controller.enqueue(no)
+		},
+	})
+	svcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueGroupsOf,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueGroupsOf(new)
+		},
+	})
+	return controller
+}
+
+// Run serves the service group controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the service group controller")
+	glog.Info("Waiting for the service group informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced, c.svcSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the service group controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the service group controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("ServiceGroup expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("ServiceGroup error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.Infof("ServiceGroup successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueGroupsOf enqueues all service groups that have the given service as a member,
+// so that groups waiting for the service are reconciled once it becomes active.
+func (c *Controller) enqueueGroupsOf(obj interface{}) {
+	svc, ok := obj.(*blendedv1.Service)
+	if !ok {
+		return
+	}
+
+	groups, err := c.lister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, grp := range groups {
+		if funk.ContainsString(grp.Spec.Services, naming.EntryName(c.cfg.NamingScheme, svc.ObjectMeta)) {
+			c.enqueue(grp)
+		}
+	}
+}
+
+func (c *Controller) reconcile(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	group, err := c.lister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("service group '%s' in work queue no longer exists", key))
+			return err
+		}
+		return err
+	}
+
+	// The resource is synced by the controller of another firewall.
+	if firewall.Ref(group.ObjectMeta, group.Spec.FirewallRef) != c.cfg.Firewall {
+		return nil
+	}
+
+	if !group.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := c.cleanup(group); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := c.checkAndUdateFinalizer(group); err != nil {
+		return err
+	}
+
+	if missing := c.missingServices(group); len(missing) != 0 {
+		return c.makePending(group, missing)
+	}
+
+	need := k8sutil.IsNeedToUpdate(group.ObjectMeta)
+	if group.Status.Phase != inwinv1.ServiceGroupActive || need {
+		if group.Status.Phase == inwinv1.ServiceGroupFailed {
+			t := util.SubtractNowTime(group.Status.LastUpdateTime.Time)
+			if t.Seconds() <= float64(c.cfg.SyncSec) && !need {
+				return nil
+			}
+		}
+		if err := c.createOrUpdate(group); err != nil {
+			return c.makeFailed(group, err)
+		}
+		return nil
+	}
+
+	if group.Status.Phase == inwinv1.ServiceGroupActive && !c.isExistingServiceGroup(group) {
+		if err := c.createOrUpdate(group); err != nil {
+			return c.makeFailed(group, err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) checkAndUdateFinalizer(grp *inwinv1.ServiceGroup) error {
+	grpCopy := grp.DeepCopy()
+	ok := funk.ContainsString(grpCopy.Finalizers, constants.CustomFinalizer)
+	if grp.Status.Phase == inwinv1.ServiceGroupActive && !ok {
+		k8sutil.AddFinalizer(&grpCopy.ObjectMeta, constants.CustomFinalizer)
+		if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) makePending(grp *inwinv1.ServiceGroup, missing []string) error {
+	if grp.Status.Phase == inwinv1.ServiceGroupPending && reflect.DeepEqual(grp.Status.MissingServices, missing) {
+		return nil
+	}
+
+	grpCopy := grp.DeepCopy()
+	grpCopy.Status.Reason = fmt.Sprintf("waiting for the member services: %s", strings.Join(missing, ", "))
+	grpCopy.Status.Phase = inwinv1.ServiceGroupPending
+	grpCopy.Status.MissingServices = missing
+	grpCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+		return err
+	}
+	glog.V(3).Infof("ServiceGroup '%s' is waiting for the member services: %v.", grp.Name, missing)
+	return nil
+}
+
+func (c *Controller) makeFailed(grp *inwinv1.ServiceGroup, e error) error {
+	grpCopy := grp.DeepCopy()
+	grpCopy.Status.Reason = e.Error()
+	grpCopy.Status.Phase = inwinv1.ServiceGroupFailed
+	grpCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(grpCopy.Annotations, constants.NeedUpdateKey)
+	if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+		return err
+	}
+	glog.Errorf("ServiceGroup got an error:%+v.", e)
+	return nil
+}
+
+func (c *Controller) createOrUpdate(grp *inwinv1.ServiceGroup) error {
+	if c.cfg.DryRun {
+		return c.planServiceGroup(grp, false)
+	}
+
+	grpCopy := grp.DeepCopy()
+	if err := c.updateServiceGroup(grpCopy); err != nil {
+		return err
+	}
+
+	grpCopy.Status.Reason = ""
+	grpCopy.Status.Phase = inwinv1.ServiceGroupActive
+	grpCopy.Status.MissingServices = nil
+	grpCopy.Status.LastUpdateTime = metav1.NewTime(time.Now())
+	delete(grpCopy.Annotations, constants.NeedUpdateKey)
+	plan.Clear(&grpCopy.ObjectMeta)
+	k8sutil.AddFinalizer(&grpCopy.ObjectMeta, constants.CustomFinalizer)
+	if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) cleanup(grp *inwinv1.ServiceGroup) error {
+	if c.cfg.DryRun {
+		return c.planServiceGroup(grp, true)
+	}
+
+	grpCopy := grp.DeepCopy()
+	if err := c.deleteServiceGroup(grpCopy); err != nil {
+		return err
+	}
+
+	k8sutil.RemoveFinalizer(&grpCopy.ObjectMeta, constants.CustomFinalizer)
+	grpCopy.Status.Phase = inwinv1.ServiceGroupTerminating
+	if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicegroup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/blended/constants"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	blendedinformers "github.com/inwinstack/blended/generated/informers/externalversions"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	paconstants "github.com/inwinstack/pa-controller/pkg/constants"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/inwinstack/pango/objs/srvcgrp"
+	"github.com/inwinstack/pango/testdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const timeout = 3 * time.Second
+
+func commitSignal(t *testing.T, commit chan *commit.Object, stopCh <-chan struct{}) {
+	for {
+		select {
+		case c := <-commit:
+			assert.NotNil(t, c)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func TestServiceGroupController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, PredefinedServices: []string{inwinv1.ServiceGroupHTTPS, "corp-ldap"}}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	fwSrvcGrp := &srvcgrp.FwSrvcGrp{}
+	fwSrvcGrp.Initialize(mc)
+
+	controller := NewController(cfg, fwSrvcGrp, inwinset,
+		inwinInformer.Inwinstack().V1().ServiceGroups(),
+		informer.Inwinstack().V1().Services(), commit, nil)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	grp := &inwinv1.ServiceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-web",
+		},
+		Spec: inwinv1.ServiceGroupSpec{
+			Services: []string{"k8s-tcp80", inwinv1.ServiceGroupHTTPS, "corp-ldap"},
+		},
+	}
+
+	mc.Reset()
+	mc.AddResp("")
+	_, err := inwinset.InwinstackV1().ServiceGroups().Create(grp)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().ServiceGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.ServiceGroupPending {
+			assert.Equal(t, []string{"k8s-tcp80"}, ggrp.Status.MissingServices)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service group isn't waiting for the members.")
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "k8s-tcp80",
+		},
+		Spec: blendedv1.ServiceSpec{
+			Protocol:        "tcp",
+			DestinationPort: "80",
+		},
+		Status: blendedv1.ServiceStatus{
+			Phase: blendedv1.ServiceActive,
+		},
+	}
+	_, err = blendedset.InwinstackV1().Services().Create(svc)
+	assert.Nil(t, err)
+
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().ServiceGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+
+		mc.AddResp(mc.Elm)
+		entry, err := fwSrvcGrp.Get(cfg.Vsys, ggrp.Name)
+		assert.Nil(t, err)
+		if ggrp.Status.Phase == inwinv1.ServiceGroupActive && entry.Name != "" {
+			assert.Equal(t, []string{constants.CustomFinalizer}, ggrp.Finalizers)
+			assert.Equal(t, 0, len(ggrp.Status.MissingServices))
+			assert.Equal(t, ggrp.Name, entry.Name)
+			assert.ElementsMatch(t, ggrp.Spec.Services, entry.Services)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service group hasn't created.")
+
+	cancel()
+	controller.Stop()
+
+	// The fake clientset doesn't honor finalizers, so run the cleanup directly.
+	ggrp, err := inwinset.InwinstackV1().ServiceGroups().Get(grp.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	elm := mc.Elm
+	mc.Reset()
+	mc.Resp, mc.Called = nil, 0
+	mc.AddResp(elm)
+	mc.AddResp("")
+	assert.Nil(t, controller.cleanup(ggrp))
+	assert.Equal(t, "delete", mc.Function)
+	assert.Contains(t, mc.Path, grp.Name)
+
+	ggrp, err = inwinset.InwinstackV1().ServiceGroups().Get(grp.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, ggrp.Finalizers)
+	assert.Equal(t, inwinv1.ServiceGroupTerminating, ggrp.Status.Phase)
+
+	assert.Nil(t, inwinset.InwinstackV1().ServiceGroups().Delete(grp.Name, nil))
+	grpList, err := inwinset.InwinstackV1().ServiceGroups().List(metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(grpList.Items))
+	mc.Reset()
+}
+
+func TestServiceGroupControllerDryRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commit := make(chan *commit.Object, 1)
+	cfg := &config.Config{Threads: 2, Retry: 5, DryRun: true}
+	blendedset := blendedfake.NewSimpleClientset()
+	inwinset := inwinfake.NewSimpleClientset()
+	informer := blendedinformers.NewSharedInformerFactory(blendedset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	// The service group doesn't exist on the firewall.
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	fwSrvcGrp := &srvcgrp.FwSrvcGrp{}
+	fwSrvcGrp.Initialize(mc)
+
+	controller := NewController(cfg, fwSrvcGrp, inwinset,
+		inwinInformer.Inwinstack().V1().ServiceGroups(),
+		informer.Inwinstack().V1().Services(), commit, nil)
+	go informer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	go commitSignal(t, controller.commit, ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	// The member services are only planned in dry-run mode, so the group doesn't wait for them.
+	grp := &inwinv1.ServiceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-dry-run"},
+		Spec: inwinv1.ServiceGroupSpec{
+			Services: []string{"k8s-tcp80"},
+		},
+	}
+	_, err := inwinset.InwinstackV1().ServiceGroups().Create(grp)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		ggrp, err := inwinset.InwinstackV1().ServiceGroups().Get(grp.Name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		if p, ok := ggrp.Annotations[paconstants.PlanKey]; ok {
+			assert.Equal(t, "create k8s-dry-run: Services: [] -> [k8s-tcp80]", p)
+			assert.NotEqual(t, inwinv1.ServiceGroupPending, ggrp.Status.Phase)
+			assert.Equal(t, 0, len(ggrp.Status.MissingServices))
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The service group hasn't been planned.")
+	assert.Equal(t, "get", mc.Function)
+
+	cancel()
+	controller.Stop()
+}
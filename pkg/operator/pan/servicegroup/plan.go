@@ -0,0 +1,53 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicegroup
+
+import (
+	"github.com/golang/glog"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/plan"
+)
+
+// planServiceGroup records the change of the service group on the resource in the dry-run mode, where
+// the firewall isn't edited.
+func (c *Controller) planServiceGroup(grp *inwinv1.ServiceGroup, deleting bool) error {
+	current, err := c.srvcgrp.Get(c.cfg.Vsys, grp.Name)
+	if err != nil && !plan.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil && current.Name != ""
+
+	p := plan.Delete(grp.Name, exists)
+	if !deleting {
+		entry := c.newServiceGroup(grp)
+		p = plan.Create(grp.Name, *entry)
+		if exists {
+			p = plan.Update(grp.Name, current, *entry)
+		}
+	}
+
+	grpCopy := grp.DeepCopy()
+	if !plan.Record(&grpCopy.ObjectMeta, p) {
+		return nil
+	}
+
+	glog.Infof("ServiceGroup dry-run plan: %s.", p)
+	if _, err := c.inwinset.InwinstackV1().ServiceGroups().Update(grpCopy); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicegroup
+
+import (
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/thoas/go-funk"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/dependency"
+	"github.com/inwinstack/pango/objs/srvcgrp"
+)
+
+// missingServices returns the member services that don't exist or aren't active yet,
+// except for the predefined services of the config. Nothing is missing in dry-run mode,
+// where the services are only planned.
+func (c *Controller) missingServices(grp *inwinv1.ServiceGroup) []string {
+	if c.cfg.DryRun {
+		return nil
+	}
+
+	missing := []string{}
+	for _, name := range grp.Spec.Services {
+		if funk.ContainsString(c.cfg.PredefinedServices, name) {
+			continue
+		}
+
+		svc := dependency.FindService(c.svcLister, c.cfg.NamingScheme, name)
+		if svc == nil || svc.Status.Phase != blendedv1.ServiceActive {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (c *Controller) newServiceGroup(grp *inwinv1.ServiceGroup) *srvcgrp.Entry {
+	return &srvcgrp.Entry{
+		Name:     grp.Name,
+		Services: grp.Spec.Services,
+		Tags:     ownership.AddTags(grp.Spec.Tags, c.cfg.ClusterID),
+	}
+}
+
+func (c *Controller) isExistingServiceGroup(grp *inwinv1.ServiceGroup) bool {
+	if entry, err := c.srvcgrp.Get(c.cfg.Vsys, grp.Name); err == nil {
+		if len(entry.Name) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) updateServiceGroup(grp *inwinv1.ServiceGroup) error {
+	entry := c.newServiceGroup(grp)
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.srvcgrp.Edit(c.cfg.Vsys, *entry); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindServiceGroup, grp.ObjectMeta)
+	return nil
+}
+
+func (c *Controller) deleteServiceGroup(grp *inwinv1.ServiceGroup) error {
+	if !c.isExistingServiceGroup(grp) {
+		return nil
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		return err
+	}
+	defer c.locker.Done()
+
+	if err := c.srvcgrp.Delete(c.cfg.Vsys, grp.Name); err != nil {
+		return c.locker.Explain(err)
+	}
+	c.commit <- commit.NewObject(commit.KindServiceGroup, grp.ObjectMeta)
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pan
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/tags"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tagClient represents the tags of a firewall or a device group of Panorama.
+type tagClient interface {
+	Edit(vsys string, e tags.Entry) error
+}
+
+// ensureTags creates the ownership tags of the cluster, which are referenced by the entries
+// created by the controller.
+func (c *Controller) ensureTags() error {
+	if c.cfg.DryRun {
+		return nil
+	}
+
+	for _, t := range ownership.Tags(c.cfg.ClusterID) {
+		entry := tags.Entry{Name: t, Comment: "Managed by the PA controller"}
+		if err := c.clients.tags.Edit(c.cfg.Vsys, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweep deletes the policies, objects and groups which are owned by the cluster but have no
+// resource, such as the ones whose resources are deleted while the controller is down.
+// Nothing is deleted when the orphans exceed the sweep threshold, or in the dry-run mode,
+// where the orphans are only reported.
+func (c *Controller) sweep() {
+	orphans, err := c.findOrphans()
+	if err != nil {
+		glog.Errorf("Failed to find the orphaned entries: %+v.", err)
+		return
+	}
+
+	if len(orphans) == 0 {
+		return
+	}
+
+	for _, o := range orphans {
+		glog.Infof("Found the orphaned entry '%s'.", o)
+	}
+
+	if c.cfg.DryRun || c.cfg.SweepDryRun {
+		glog.Infof("Found %d orphaned entries, they aren't deleted in the dry-run mode.", len(orphans))
+		return
+	}
+
+	if c.cfg.SweepThreshold > 0 && len(orphans) > c.cfg.SweepThreshold {
+		glog.Errorf("Found %d orphaned entries exceeding the sweep threshold %d, they aren't deleted.", len(orphans), c.cfg.SweepThreshold)
+		return
+	}
+
+	if err := c.locker.Acquire(); err != nil {
+		glog.Errorf("Failed to delete the orphaned entries: %+v.", err)
+		return
+	}
+	defer c.locker.Done()
+
+	for _, o := range orphans {
+		if err := c.deleteOrphan(o); err != nil {
+			glog.Errorf("Failed to delete the orphaned entry '%s': %+v.", o, c.locker.Explain(err))
+			continue
+		}
+		glog.Infof("Deleted the orphaned entry '%s'.", o)
+		c.batcher.Queue() <- o
+	}
+}
+
+// owner lists the entries of a kind, and tells whether an entry is owned by the cluster.
+type owner struct {
+	kind  string
+	list  func(vsys string) ([]string, error)
+	owned func(vsys, name string) bool
+}
+
+// owners returns the owners of the kinds in the order of deletion, so the policies and the
+// groups are deleted before the objects they refer to. The external dynamic lists have no
+// tags, so they're owned by the marker in their description.
+func (c *Controller) owners() []owner {
+	id := c.cfg.ClusterID
+	return []owner{
+		{commit.KindNAT, c.clients.nat.GetList, func(vsys, name string) bool {
+			e, err := c.clients.nat.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindSecurity, c.clients.security.GetList, func(vsys, name string) bool {
+			e, err := c.clients.security.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindAddressGroup, c.clients.addrgrp.GetList, func(vsys, name string) bool {
+			e, err := c.clients.addrgrp.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindServiceGroup, c.clients.srvcgrp.GetList, func(vsys, name string) bool {
+			e, err := c.clients.srvcgrp.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindAddress, c.clients.address.GetList, func(vsys, name string) bool {
+			e, err := c.clients.address.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindService, c.clients.service.GetList, func(vsys, name string) bool {
+			e, err := c.clients.service.Get(vsys, name)
+			return err == nil && ownership.IsOwned(e.Tags, id)
+		}},
+		{commit.KindExternalDynamicList, c.clients.edl.GetList, func(vsys, name string) bool {
+			e, err := c.clients.edl.Get(vsys, name)
+			return err == nil && ownership.IsDescribed(e.Description, id)
+		}},
+	}
+}
+
+// findOrphans returns the entries owned by the cluster without any resource of the same
+// name. The entries are listed before the resources, so the entries of the resources
+// created in between aren't taken as orphans.
+func (c *Controller) findOrphans() ([]*commit.Object, error) {
+	vsys := c.cfg.Vsys
+	owners := c.owners()
+	names := map[string][]string{}
+	for _, o := range owners {
+		list, err := o.list(vsys)
+		if err != nil {
+			return nil, err
+		}
+		names[o.kind] = list
+	}
+
+	resources, err := c.listResources()
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := []*commit.Object{}
+	for _, o := range owners {
+		for _, name := range names[o.kind] {
+			if resources[o.kind][name] || !o.owned(vsys, name) {
+				continue
+			}
+			orphans = append(orphans, commit.NewObject(o.kind, metav1.ObjectMeta{Name: name}))
+		}
+	}
+	return orphans, nil
+}
+
+// listResources returns the entry names of the resources by kind. The resources of all
+// firewalls are included, so an entry is never deleted while any resource has its name.
+func (c *Controller) listResources() (map[string]map[string]bool, error) {
+	opts := metav1.ListOptions{}
+	resources := map[string]map[string]bool{
+		commit.KindNAT:                 {},
+		commit.KindSecurity:            {},
+		commit.KindService:             {},
+		commit.KindServiceGroup:        {},
+		commit.KindAddress:             {},
+		commit.KindAddressGroup:        {},
+		commit.KindExternalDynamicList: {},
+	}
+
+	nats, err := c.blendedset.InwinstackV1().NATs(metav1.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nats.Items {
+		resources[commit.KindNAT][naming.EntryName(c.cfg.NamingScheme, n.ObjectMeta)] = true
+	}
+
+	secs, err := c.blendedset.InwinstackV1().Securities(metav1.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range secs.Items {
+		resources[commit.KindSecurity][naming.EntryName(c.cfg.NamingScheme, s.ObjectMeta)] = true
+	}
+
+	svcs, err := c.blendedset.InwinstackV1().Services().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range svcs.Items {
+		resources[commit.KindService][naming.EntryName(c.cfg.NamingScheme, s.ObjectMeta)] = true
+	}
+
+	srvcgrps, err := c.inwinset.InwinstackV1().ServiceGroups().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range srvcgrps.Items {
+		resources[commit.KindServiceGroup][g.Name] = true
+	}
+
+	addrs, err := c.inwinset.InwinstackV1().Addresses().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs.Items {
+		resources[commit.KindAddress][a.Name] = true
+	}
+
+	addrgrps, err := c.inwinset.InwinstackV1().AddressGroups().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range addrgrps.Items {
+		resources[commit.KindAddressGroup][g.Name] = true
+	}
+
+	edls, err := c.inwinset.InwinstackV1().ExternalDynamicLists().List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edls.Items {
+		resources[commit.KindExternalDynamicList][e.Name] = true
+	}
+	return resources, nil
+}
+
+func (c *Controller) deleteOrphan(o *commit.Object) error {
+	switch o.Kind {
+	case commit.KindNAT:
+		return c.clients.nat.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindSecurity:
+		return c.clients.security.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindService:
+		return c.clients.service.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindServiceGroup:
+		return c.clients.srvcgrp.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindAddress:
+		return c.clients.address.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindAddressGroup:
+		return c.clients.addrgrp.Delete(c.cfg.Vsys, o.Name)
+	case commit.KindExternalDynamicList:
+		return c.clients.edl.Delete(c.cfg.Vsys, o.Name)
+	}
+	return fmt.Errorf("unknown kind '%s'", o.Kind)
+}
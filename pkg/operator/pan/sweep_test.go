@@ -0,0 +1,233 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	blendedfake "github.com/inwinstack/blended/generated/clientset/versioned/fake"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/commit"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	"github.com/inwinstack/pa-controller/pkg/ownership"
+	"github.com/inwinstack/pango/objs/addr"
+	"github.com/inwinstack/pango/objs/addrgrp"
+	"github.com/inwinstack/pango/objs/edl"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/objs/srvcgrp"
+	"github.com/inwinstack/pango/poli/nat"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// fakeEntries keeps the tags of the entries by name.
+type fakeEntries struct {
+	tags    map[string][]string
+	deleted []string
+}
+
+func (f *fakeEntries) GetList(_ string) ([]string, error) {
+	names := []string{}
+	for name := range f.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *fakeEntries) get(name string) ([]string, error) {
+	tags, ok := f.tags[name]
+	if !ok {
+		return nil, fmt.Errorf("'%s' not found", name)
+	}
+	return tags, nil
+}
+
+func (f *fakeEntries) Delete(_ string, e ...interface{}) error {
+	for _, name := range e {
+		f.deleted = append(f.deleted, name.(string))
+		delete(f.tags, name.(string))
+	}
+	return nil
+}
+
+type fakeNat struct{ fakeEntries }
+
+func (f *fakeNat) Get(_, name string) (nat.Entry, error) {
+	tags, err := f.get(name)
+	return nat.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeNat) Edit(_ string, e nat.Entry) error { return nil }
+
+type fakeSecurity struct{ fakeEntries }
+
+func (f *fakeSecurity) Get(_, name string) (security.Entry, error) {
+	tags, err := f.get(name)
+	return security.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeSecurity) Edit(_ string, e security.Entry) error { return nil }
+
+func (f *fakeSecurity) MoveGroup(_ string, _ int, _ string, _ ...security.Entry) error { return nil }
+
+type fakeService struct{ fakeEntries }
+
+func (f *fakeService) Get(_, name string) (srvc.Entry, error) {
+	tags, err := f.get(name)
+	return srvc.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeService) Edit(_ string, e srvc.Entry) error { return nil }
+
+type fakeServiceGroup struct{ fakeEntries }
+
+func (f *fakeServiceGroup) Get(_, name string) (srvcgrp.Entry, error) {
+	tags, err := f.get(name)
+	return srvcgrp.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeServiceGroup) Edit(_ string, e srvcgrp.Entry) error { return nil }
+
+type fakeAddress struct{ fakeEntries }
+
+func (f *fakeAddress) Get(_, name string) (addr.Entry, error) {
+	tags, err := f.get(name)
+	return addr.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeAddress) Edit(_ string, e addr.Entry) error { return nil }
+
+type fakeAddressGroup struct{ fakeEntries }
+
+func (f *fakeAddressGroup) Get(_, name string) (addrgrp.Entry, error) {
+	tags, err := f.get(name)
+	return addrgrp.Entry{Name: name, Tags: tags}, err
+}
+
+func (f *fakeAddressGroup) Edit(_ string, e addrgrp.Entry) error { return nil }
+
+// fakeEdl keeps the descriptions of the external dynamic lists in the tags, since they
+// have no tags.
+type fakeEdl struct{ fakeEntries }
+
+func (f *fakeEdl) Get(_, name string) (edl.Entry, error) {
+	tags, err := f.get(name)
+	return edl.Entry{Name: name, Description: strings.Join(tags, " ")}, err
+}
+
+func (f *fakeEdl) Edit(_ string, e edl.Entry) error { return nil }
+
+func TestSweep(t *testing.T) {
+	owned := ownership.Tags("k8s-a")
+	fnat := &fakeNat{fakeEntries{tags: map[string][]string{
+		"backed": owned,
+		"orphan": owned,
+		"admin":  {"web"},
+	}}}
+	fsec := &fakeSecurity{fakeEntries{tags: map[string][]string{
+		"orphan-sec": owned,
+		"other":      ownership.Tags("k8s-b"),
+	}}}
+	fsvc := &fakeService{fakeEntries{tags: map[string][]string{
+		"orphan-svc": owned,
+	}}}
+	fsrvcgrp := &fakeServiceGroup{fakeEntries{tags: map[string][]string{
+		"orphan-srvcgrp": owned,
+	}}}
+	faddr := &fakeAddress{fakeEntries{tags: map[string][]string{
+		"backed-addr":  owned,
+		"orphan-addr":  owned,
+		"admin-server": {"db"},
+	}}}
+	faddrgrp := &fakeAddressGroup{fakeEntries{tags: map[string][]string{
+		"orphan-addrgrp": owned,
+	}}}
+	fedl := &fakeEdl{fakeEntries{tags: map[string][]string{
+		"orphan-edl": {"pods", ownership.Describe("", "k8s-a")},
+		"admin-edl":  {"blocklist"},
+		"other-edl":  {ownership.Describe("", "k8s-b")},
+	}}}
+
+	blendedset := blendedfake.NewSimpleClientset(&blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{Name: "backed", Namespace: "default"},
+	})
+	inwinset := inwinfake.NewSimpleClientset(&inwinv1.Address{
+		ObjectMeta: metav1.ObjectMeta{Name: "backed-addr"},
+	})
+	c := &Controller{
+		cfg: &config.Config{Vsys: "vsys1", ClusterID: "k8s-a", SweepThreshold: 2},
+		clients: &clients{
+			nat:      fnat,
+			security: fsec,
+			service:  fsvc,
+			srvcgrp:  fsrvcgrp,
+			address:  faddr,
+			addrgrp:  faddrgrp,
+			edl:      fedl,
+		},
+		blendedset: blendedset,
+		inwinset:   inwinset,
+		batcher:    commit.NewBatcher(time.Second, 0, 10, clock.RealClock{}),
+	}
+
+	orphans, err := c.findOrphans()
+	assert.Nil(t, err)
+	assert.Equal(t, []*commit.Object{
+		{Kind: commit.KindNAT, Name: "orphan"},
+		{Kind: commit.KindSecurity, Name: "orphan-sec"},
+		{Kind: commit.KindAddressGroup, Name: "orphan-addrgrp"},
+		{Kind: commit.KindServiceGroup, Name: "orphan-srvcgrp"},
+		{Kind: commit.KindAddress, Name: "orphan-addr"},
+		{Kind: commit.KindService, Name: "orphan-svc"},
+		{Kind: commit.KindExternalDynamicList, Name: "orphan-edl"},
+	}, orphans)
+
+	// Nothing is deleted when the orphans exceed the threshold.
+	c.sweep()
+	assert.Nil(t, fnat.deleted)
+	assert.Equal(t, 0, c.batcher.QueueDepth())
+
+	// The orphans are only reported in the dry-run mode.
+	c.cfg.SweepThreshold = 7
+	c.cfg.SweepDryRun = true
+	c.sweep()
+	assert.Nil(t, fnat.deleted)
+
+	c.cfg.SweepDryRun = false
+	c.sweep()
+	assert.Equal(t, []string{"orphan"}, fnat.deleted)
+	assert.Equal(t, []string{"orphan-sec"}, fsec.deleted)
+	assert.Equal(t, []string{"orphan-svc"}, fsvc.deleted)
+	assert.Equal(t, []string{"orphan-srvcgrp"}, fsrvcgrp.deleted)
+	assert.Equal(t, []string{"orphan-addr"}, faddr.deleted)
+	assert.Equal(t, []string{"orphan-addrgrp"}, faddrgrp.deleted)
+	assert.Equal(t, []string{"orphan-edl"}, fedl.deleted)
+	assert.Equal(t, 7, c.batcher.QueueDepth())
+
+	orphans, err = c.findOrphans()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(orphans))
+}
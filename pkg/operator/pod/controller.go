@@ -0,0 +1,206 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/tags"
+	"github.com/inwinstack/pango/userid"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	informerv1 "k8s.io/client-go/informers/core/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const defaultResyncTime = 5 * time.Minute
+
+// Controller represents the controller of pod IP tags
+type Controller struct {
+	cfg      *config.Config
+	uid      *userid.UserId
+	lister   listerv1.PodLister
+	nsLister listerv1.NamespaceLister
+	tagger   tags.Tagger
+	synced   []cache.InformerSynced
+	queue    workqueue.RateLimitingInterface
+
+	// registered stores the IP and tags that were registered for each pod.
+	lock       sync.Mutex
+	registered map[string]*registration
+}
+
+type registration struct {
+	ip   string
+	tags []string
+}
+
+// NewController creates an instance of the pod IP tag controller
+func NewController(
+	cfg *config.Config,
+	uid *userid.UserId,
+	informer informerv1.PodInformer,
+	nsInformer informerv1.NamespaceInformer) *Controller {
+	controller := &Controller{
+		cfg:      cfg,
+		uid:      uid,
+		lister:   informer.Lister(),
+		nsLister: nsInformer.Lister(),
+		tagger:   tags.New(cfg.ClusterID),
+		synced: []cache.InformerSynced{
+			informer.Informer().HasSynced,
+			nsInformer.Informer().HasSynced,
+		},
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Pods"),
+		registered: map[string]*registration{},
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueue(new)
+		},
+		DeleteFunc: controller.enqueue,
+	})
+	nsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueNamespace(new.(*v1.Namespace))
+		},
+	})
+	return controller
+}
+
+// Run serves the pod IP tag controller
+func (c *Controller) Run(ctx context.Context, threadiness int) error {
+	glog.Info("Starting the pod IP tag controller")
+	glog.Info("Waiting for the pod informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	t := defaultResyncTime
+	if c.cfg.PodTagResyncSec > 0 {
+		t = time.Second * time.Duration(c.cfg.PodTagResyncSec)
+	}
+	go wait.Until(c.resync, t, ctx.Done())
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+	return nil
+}
+
+// Stop stops the pod IP tag controller
+func (c *Controller) Stop() {
+	glog.Info("Stopping the pod IP tag controller")
+	c.queue.ShutDown()
+}
+
+func (c *Controller) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.queue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("Pod expected string in workqueue but got %#v", obj))
+			return nil
+		}
+
+		if err := c.reconcile(key); err != nil {
+			c.queue.AddRateLimited(key)
+			return fmt.Errorf("Pod error syncing '%s': %s, requeuing", key, err.Error())
+		}
+
+		c.queue.Forget(obj)
+		glog.V(3).Infof("Pod successfully synced '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueNamespace enqueues all pods of the namespace, since the namespace labels are a part of the pod tags.
+func (c *Controller) enqueueNamespace(ns *v1.Namespace) {
+	pods, err := c.lister.Pods(ns.Name).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, pod := range pods {
+		c.enqueue(pod)
+	}
+}
+
+func (c *Controller) reconcile(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return err
+	}
+
+	pod, err := c.lister.Pods(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.unregister(key)
+		}
+		return err
+	}
+
+	if !pod.ObjectMeta.DeletionTimestamp.IsZero() || !isTaggable(pod) {
+		return c.unregister(key)
+	}
+
+	ns, err := c.nsLister.Get(namespace)
+	if err != nil {
+		return err
+	}
+	return c.register(key, pod.Status.PodIP, c.podTags(pod, ns))
+}
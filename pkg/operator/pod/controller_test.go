@@ -0,0 +1,169 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/tags"
+	"github.com/inwinstack/pango/testdata"
+	"github.com/inwinstack/pango/userid"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const timeout = 3 * time.Second
+
+func TestPodTags(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web", "app.kubernetes.io/name": "nginx"},
+		},
+	}
+	ns := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{"env": "prod"},
+		},
+	}
+
+	c := &Controller{tagger: tags.New("")}
+	expected := []string{"k8s.label.app.kubernetes.io.name.nginx", "k8s.label.app.web", "k8s.ns.default", "k8s.nslabel.env.prod"}
+	assert.Equal(t, expected, c.podTags(pod, ns))
+
+	c = &Controller{tagger: tags.New("k8s-a")}
+	expected = []string{"k8s.k8s-a.label.app.kubernetes.io.name.nginx", "k8s.k8s-a.label.app.web", "k8s.k8s-a.ns.default", "k8s.k8s-a.nslabel.env.prod"}
+	assert.Equal(t, expected, c.podTags(pod, ns))
+	assert.Equal(t, []string{"k8s.label.app.web"}, difference([]string{"k8s.label.app.web", "k8s.ns.default"}, []string{"k8s.ns.default"}))
+}
+
+func TestPodTagsSpoofing(t *testing.T) {
+	// The labels of a pod can't register the tags of another namespace.
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Labels:    map[string]string{"ns": "prod", "nslabel.env": "prod"},
+		},
+	}
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+
+	c := &Controller{tagger: tags.New("")}
+	result := c.podTags(pod, ns)
+	assert.Equal(t, []string{"k8s.label.ns.prod", "k8s.label.nslabel.env.prod", "k8s.ns.default"}, result)
+	assert.NotContains(t, result, "k8s.ns.prod")
+	assert.NotContains(t, result, "k8s.nslabel.env.prod")
+	for _, tag := range result {
+		assert.True(t, c.tagger.IsManaged(tag))
+	}
+}
+
+func TestPodTagsResync(t *testing.T) {
+	cfg := &config.Config{ClusterID: "k8s-a"}
+	kubeset := kubefake.NewSimpleClientset()
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+
+	mc := &testdata.MockClient{}
+	uid := &userid.UserId{}
+	uid.Initialize(mc)
+	controller := NewController(cfg, uid, kubeInformer.Core().V1().Pods(), kubeInformer.Core().V1().Namespaces())
+
+	// Only the stale tags of this cluster are unregistered, while the tags of another cluster
+	// and the tags without a cluster ID are kept.
+	mc.AddResp(`<entry ip="10.244.1.10"><tag><member>k8s.k8s-a.ns.default</member>` +
+		`<member>k8s.k8s-b.ns.default</member><member>k8s.ns.default</member></tag></entry>`)
+	mc.AddResp("")
+	controller.resync()
+
+	assert.True(t, strings.Contains(mc.Elm, "<unregister>"))
+	assert.True(t, strings.Contains(mc.Elm, "k8s.k8s-a.ns.default"))
+	assert.False(t, strings.Contains(mc.Elm, "k8s.k8s-b.ns.default"))
+	assert.False(t, strings.Contains(mc.Elm, "<member>k8s.ns.default</member>"))
+}
+
+func TestPodController(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &config.Config{Threads: 2}
+	kubeset := kubefake.NewSimpleClientset()
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+
+	// PAN firewall fake client
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+	uid := &userid.UserId{}
+	uid.Initialize(mc)
+
+	controller := NewController(cfg, uid, kubeInformer.Core().V1().Pods(), kubeInformer.Core().V1().Namespaces())
+	go kubeInformer.Start(ctx.Done())
+	assert.Nil(t, controller.Run(ctx, cfg.Threads))
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	_, err := kubeset.CoreV1().Namespaces().Create(ns)
+	assert.Nil(t, err)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: ns.Name,
+			Labels:    map[string]string{"app": "web"},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.244.1.10"},
+	}
+	_, err = kubeset.CoreV1().Pods(ns.Name).Create(pod)
+	assert.Nil(t, err)
+
+	failed := true
+	for start := time.Now(); time.Since(start) < timeout; {
+		controller.lock.Lock()
+		reg, ok := controller.registered["default/web"]
+		controller.lock.Unlock()
+		if ok {
+			assert.Equal(t, "10.244.1.10", reg.ip)
+			assert.Equal(t, []string{"k8s.label.app.web", "k8s.ns.default"}, reg.tags)
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The pod IP tags haven't registered.")
+
+	assert.Nil(t, kubeset.CoreV1().Pods(ns.Name).Delete(pod.Name, nil))
+	failed = true
+	for start := time.Now(); time.Since(start) < timeout; {
+		controller.lock.Lock()
+		_, ok := controller.registered["default/web"]
+		elm := mc.Elm
+		controller.lock.Unlock()
+		if !ok {
+			assert.True(t, strings.Contains(elm, "<unregister>"))
+			failed = false
+			break
+		}
+	}
+	assert.Equal(t, false, failed, "The pod IP tags haven't unregistered.")
+
+	cancel()
+	controller.Stop()
+}
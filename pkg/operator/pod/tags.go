@@ -0,0 +1,173 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/thoas/go-funk"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// isTaggable returns true when the pod has its own IP which can be registered.
+func isTaggable(pod *v1.Pod) bool {
+	if pod.Spec.HostNetwork || pod.Status.PodIP == "" {
+		return false
+	}
+	return pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed
+}
+
+// podTags returns the sorted tags of the pod.
+func (c *Controller) podTags(pod *v1.Pod, ns *v1.Namespace) []string {
+	result := []string{c.tagger.Namespace(pod.Namespace)}
+	for key, value := range pod.Labels {
+		result = append(result, c.tagger.Label(key, value))
+	}
+
+	for key, value := range ns.Labels {
+		result = append(result, c.tagger.NamespaceLabel(key, value))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// difference returns the tags of a that aren't in b.
+func difference(a, b []string) []string {
+	result := []string{}
+	for _, tag := range a {
+		if !funk.ContainsString(b, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func (c *Controller) register(key, ip string, podTags []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	reg := map[string][]string{}
+	unreg := map[string][]string{}
+	if current, ok := c.registered[key]; ok {
+		if current.ip != ip {
+			unreg[current.ip] = current.tags
+			reg[ip] = podTags
+		} else {
+			if added := difference(podTags, current.tags); len(added) != 0 {
+				reg[ip] = added
+			}
+
+			if removed := difference(current.tags, podTags); len(removed) != 0 {
+				unreg[ip] = removed
+			}
+		}
+	} else {
+		reg[ip] = podTags
+	}
+
+	if err := c.uid.Run(nil, nil, reg, unreg, c.cfg.Vsys); err != nil {
+		return err
+	}
+	c.registered[key] = &registration{ip: ip, tags: podTags}
+	return nil
+}
+
+func (c *Controller) unregister(key string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	current, ok := c.registered[key]
+	if !ok {
+		return nil
+	}
+
+	unreg := map[string][]string{current.ip: current.tags}
+	if err := c.uid.Run(nil, nil, nil, unreg, c.cfg.Vsys); err != nil {
+		return err
+	}
+	delete(c.registered, key)
+	return nil
+}
+
+// resync compares the registered tags on the firewall with the pods, and fixes the differences. This
+// recovers the mappings that were lost, e.g. after the firewall restarted.
+func (c *Controller) resync() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	pods, err := c.lister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list pods for resyncing IP tags: %+v.", err)
+		return
+	}
+
+	expected := map[string]*registration{}
+	desired := map[string][]string{}
+	for _, pod := range pods {
+		if !pod.ObjectMeta.DeletionTimestamp.IsZero() || !isTaggable(pod) {
+			continue
+		}
+
+		ns, err := c.nsLister.Get(pod.Namespace)
+		if err != nil {
+			glog.Warningf("Failed to get the namespace of pod '%s/%s': %+v.", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		key := pod.Namespace + "/" + pod.Name
+		expected[key] = &registration{ip: pod.Status.PodIP, tags: c.podTags(pod, ns)}
+		desired[pod.Status.PodIP] = append(desired[pod.Status.PodIP], expected[key].tags...)
+	}
+
+	current, err := c.uid.Registered("", "", c.cfg.Vsys)
+	if err != nil {
+		glog.Errorf("Failed to get the registered IP tags: %+v.", err)
+		return
+	}
+
+	reg := map[string][]string{}
+	unreg := map[string][]string{}
+	for ip, values := range desired {
+		if missing := difference(values, current[ip]); len(missing) != 0 {
+			reg[ip] = missing
+		}
+	}
+
+	// Only the tags of this cluster are unregistered, while the tags registered by others, including
+	// the controllers of other clusters sharing the firewall, are kept.
+	for ip, values := range current {
+		stale := []string{}
+		for _, tag := range difference(values, desired[ip]) {
+			if c.tagger.IsManaged(tag) {
+				stale = append(stale, tag)
+			}
+		}
+
+		if len(stale) != 0 {
+			unreg[ip] = stale
+		}
+	}
+
+	if err := c.uid.Run(nil, nil, reg, unreg, c.cfg.Vsys); err != nil {
+		glog.Errorf("Failed to resync IP tags: %+v.", err)
+		return
+	}
+	c.registered = expected
+	glog.V(2).Infof("Resynced IP tags, %d registered and %d unregistered.", len(reg), len(unreg))
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ownership tags the entries created by the controller with the ID of the cluster,
+// so the entries left without any resource can be told apart from the ones of the admins.
+package ownership
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thoas/go-funk"
+)
+
+// Tag is the tag of the entries created by any PA controller.
+const Tag = "pa-controller"
+
+// ClusterTag returns the tag of the entries created by the controller of a cluster.
+func ClusterTag(clusterID string) string {
+	return "pa-cluster-" + clusterID
+}
+
+// Tags returns the ownership tags of a cluster, and nothing is tagged when the cluster ID
+// isn't set.
+func Tags(clusterID string) []string {
+	if clusterID == "" {
+		return nil
+	}
+	return []string{Tag, ClusterTag(clusterID)}
+}
+
+// AddTags returns the tags with the ownership tags of a cluster appended.
+func AddTags(tags []string, clusterID string) []string {
+	owned := Tags(clusterID)
+	if len(owned) == 0 {
+		return tags
+	}
+
+	result := append([]string{}, tags...)
+	for _, t := range owned {
+		if !funk.ContainsString(result, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// IsOwned returns true when the tags have all the ownership tags of a cluster.
+func IsOwned(tags []string, clusterID string) bool {
+	owned := Tags(clusterID)
+	if len(owned) == 0 {
+		return false
+	}
+
+	for _, t := range owned {
+		if !funk.ContainsString(tags, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// marker returns the ownership marker of a cluster in the descriptions.
+func marker(clusterID string) string {
+	return fmt.Sprintf("[%s %s]", Tag, ClusterTag(clusterID))
+}
+
+// Describe returns the description with the ownership marker of a cluster appended, which
+// marks the entries without tags, such as the external dynamic lists.
+func Describe(description, clusterID string) string {
+	if clusterID == "" || IsDescribed(description, clusterID) {
+		return description
+	}
+
+	if description == "" {
+		return marker(clusterID)
+	}
+	return description + " " + marker(clusterID)
+}
+
+// IsDescribed returns true when the description has the ownership marker of a cluster.
+func IsDescribed(description, clusterID string) bool {
+	return clusterID != "" && strings.HasSuffix(description, marker(clusterID))
+}
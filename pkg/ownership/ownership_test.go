@@ -0,0 +1,53 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnership(t *testing.T) {
+	assert.Nil(t, Tags(""))
+	assert.Equal(t, []string{"web"}, AddTags([]string{"web"}, ""))
+	assert.False(t, IsOwned([]string{Tag}, ""))
+
+	tags := []string{"web"}
+	owned := AddTags(tags, "k8s-a")
+	assert.Equal(t, []string{"web", "pa-controller", "pa-cluster-k8s-a"}, owned)
+	assert.Equal(t, []string{"web"}, tags)
+	assert.Equal(t, owned, AddTags(owned, "k8s-a"))
+
+	assert.True(t, IsOwned(owned, "k8s-a"))
+	assert.False(t, IsOwned(owned, "k8s-b"))
+	assert.False(t, IsOwned([]string{"web", "pa-cluster-k8s-a"}, "k8s-a"))
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "web", Describe("web", ""))
+	assert.False(t, IsDescribed("web [pa-controller pa-cluster-]", ""))
+
+	described := Describe("web", "k8s-a")
+	assert.Equal(t, "web [pa-controller pa-cluster-k8s-a]", described)
+	assert.Equal(t, described, Describe(described, "k8s-a"))
+	assert.Equal(t, "[pa-controller pa-cluster-k8s-a]", Describe("", "k8s-a"))
+
+	assert.True(t, IsDescribed(described, "k8s-a"))
+	assert.False(t, IsDescribed(described, "k8s-b"))
+	assert.False(t, IsDescribed("web", "k8s-a"))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package panorama adapts the device group objects and policies of Panorama to the methods
+// of a firewall, so the controllers sync them as a firewall. The vsys given to the methods
+// is ignored, since the location is set when they're created.
+package panorama
+
+import (
+	"github.com/inwinstack/pango/objs/addr"
+	"github.com/inwinstack/pango/objs/addrgrp"
+	"github.com/inwinstack/pango/objs/edl"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/objs/srvcgrp"
+	"github.com/inwinstack/pango/objs/tags"
+	"github.com/inwinstack/pango/poli/nat"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/inwinstack/pango/util"
+)
+
+// Shared is the location of the objects shared by all device groups.
+const Shared = "shared"
+
+// Rulebase returns the rulebase of the given name, which is pre-rulebase by default.
+func Rulebase(name string) string {
+	switch name {
+	case "pre", util.PreRulebase, "":
+		return util.PreRulebase
+	case "post", util.PostRulebase:
+		return util.PostRulebase
+	}
+	return name
+}
+
+// Address represents the address objects of a location.
+type Address struct {
+	pano     *addr.PanoAddr
+	location string
+}
+
+// NewAddress creates the address objects of a location.
+func NewAddress(pano *addr.PanoAddr, location string) *Address {
+	return &Address{pano: pano, location: location}
+}
+
+func (a *Address) GetList(_ string) ([]string, error) {
+	return a.pano.GetList(a.location)
+}
+
+func (a *Address) Get(_, name string) (addr.Entry, error) {
+	return a.pano.Get(a.location, name)
+}
+
+func (a *Address) Edit(_ string, e addr.Entry) error {
+	return a.pano.Edit(a.location, e)
+}
+
+func (a *Address) Delete(_ string, e ...interface{}) error {
+	return a.pano.Delete(a.location, e...)
+}
+
+// AddressGroup represents the address groups of a location.
+type AddressGroup struct {
+	pano     *addrgrp.PanoAddrGrp
+	location string
+}
+
+// NewAddressGroup creates the address groups of a location.
+func NewAddressGroup(pano *addrgrp.PanoAddrGrp, location string) *AddressGroup {
+	return &AddressGroup{pano: pano, location: location}
+}
+
+func (a *AddressGroup) GetList(_ string) ([]string, error) {
+	return a.pano.GetList(a.location)
+}
+
+func (a *AddressGroup) Get(_, name string) (addrgrp.Entry, error) {
+	return a.pano.Get(a.location, name)
+}
+
+func (a *AddressGroup) Edit(_ string, e addrgrp.Entry) error {
+	return a.pano.Edit(a.location, e)
+}
+
+func (a *AddressGroup) Delete(_ string, e ...interface{}) error {
+	return a.pano.Delete(a.location, e...)
+}
+
+// Edl represents the external dynamic lists of a location.
+type Edl struct {
+	pano     *edl.PanoEdl
+	location string
+}
+
+// NewEdl creates the external dynamic lists of a location.
+func NewEdl(pano *edl.PanoEdl, location string) *Edl {
+	return &Edl{pano: pano, location: location}
+}
+
+func (e *Edl) GetList(_ string) ([]string, error) {
+	return e.pano.GetList(e.location)
+}
+
+func (e *Edl) Get(_, name string) (edl.Entry, error) {
+	return e.pano.Get(e.location, name)
+}
+
+func (e *Edl) Edit(_ string, entry edl.Entry) error {
+	return e.pano.Edit(e.location, entry)
+}
+
+func (e *Edl) Delete(_ string, entry ...interface{}) error {
+	return e.pano.Delete(e.location, entry...)
+}
+
+// Services represents the service objects of a location.
+type Services struct {
+	pano     *srvc.PanoSrvc
+	location string
+}
+
+// NewServices creates the service objects of a location.
+func NewServices(pano *srvc.PanoSrvc, location string) *Services {
+	return &Services{pano: pano, location: location}
+}
+
+func (s *Services) GetList(_ string) ([]string, error) {
+	return s.pano.GetList(s.location)
+}
+
+func (s *Services) Get(_, name string) (srvc.Entry, error) {
+	return s.pano.Get(s.location, name)
+}
+
+func (s *Services) Edit(_ string, e srvc.Entry) error {
+	return s.pano.Edit(s.location, e)
+}
+
+func (s *Services) Delete(_ string, e ...interface{}) error {
+	return s.pano.Delete(s.location, e...)
+}
+
+// ServiceGroup represents the service groups of a location.
+type ServiceGroup struct {
+	pano     *srvcgrp.PanoSrvcGrp
+	location string
+}
+
+// NewServiceGroup creates the service groups of a location.
+func NewServiceGroup(pano *srvcgrp.PanoSrvcGrp, location string) *ServiceGroup {
+	return &ServiceGroup{pano: pano, location: location}
+}
+
+func (s *ServiceGroup) GetList(_ string) ([]string, error) {
+	return s.pano.GetList(s.location)
+}
+
+func (s *ServiceGroup) Get(_, name string) (srvcgrp.Entry, error) {
+	return s.pano.Get(s.location, name)
+}
+
+func (s *ServiceGroup) Edit(_ string, e srvcgrp.Entry) error {
+	return s.pano.Edit(s.location, e)
+}
+
+func (s *ServiceGroup) Delete(_ string, e ...interface{}) error {
+	return s.pano.Delete(s.location, e...)
+}
+
+// Tags represents the tags of a location.
+type Tags struct {
+	pano     *tags.PanoTags
+	location string
+}
+
+// NewTags creates the tags of a location.
+func NewTags(pano *tags.PanoTags, location string) *Tags {
+	return &Tags{pano: pano, location: location}
+}
+
+func (t *Tags) Edit(_ string, e tags.Entry) error {
+	return t.pano.Edit(t.location, e)
+}
+
+// Nat represents the NAT policies of a rulebase in a device group.
+type Nat struct {
+	pano        *nat.PanoNat
+	deviceGroup string
+	rulebase    string
+}
+
+// NewNat creates the NAT policies of a rulebase in a device group.
+func NewNat(pano *nat.PanoNat, deviceGroup, rulebase string) *Nat {
+	return &Nat{pano: pano, deviceGroup: deviceGroup, rulebase: Rulebase(rulebase)}
+}
+
+func (n *Nat) GetList(_ string) ([]string, error) {
+	return n.pano.GetList(n.deviceGroup, n.rulebase)
+}
+
+func (n *Nat) Get(_, name string) (nat.Entry, error) {
+	return n.pano.Get(n.deviceGroup, n.rulebase, name)
+}
+
+func (n *Nat) Edit(_ string, e nat.Entry) error {
+	return n.pano.Edit(n.deviceGroup, n.rulebase, e)
+}
+
+func (n *Nat) Delete(_ string, e ...interface{}) error {
+	return n.pano.Delete(n.deviceGroup, n.rulebase, e...)
+}
+
+// Security represents the security policies of a rulebase in a device group.
+type Security struct {
+	pano        *security.PanoSecurity
+	deviceGroup string
+	rulebase    string
+}
+
+// NewSecurity creates the security policies of a rulebase in a device group.
+func NewSecurity(pano *security.PanoSecurity, deviceGroup, rulebase string) *Security {
+	return &Security{pano: pano, deviceGroup: deviceGroup, rulebase: Rulebase(rulebase)}
+}
+
+func (s *Security) GetList(_ string) ([]string, error) {
+	return s.pano.GetList(s.deviceGroup, s.rulebase)
+}
+
+func (s *Security) Get(_, name string) (security.Entry, error) {
+	return s.pano.Get(s.deviceGroup, s.rulebase, name)
+}
+
+func (s *Security) Edit(_ string, e security.Entry) error {
+	return s.pano.Edit(s.deviceGroup, s.rulebase, e)
+}
+
+func (s *Security) MoveGroup(_ string, movement int, rule string, e ...security.Entry) error {
+	return s.pano.MoveGroup(s.deviceGroup, s.rulebase, movement, rule, e...)
+}
+
+func (s *Security) Delete(_ string, e ...interface{}) error {
+	return s.pano.Delete(s.deviceGroup, s.rulebase, e...)
+}
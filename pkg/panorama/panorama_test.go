@@ -0,0 +1,68 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package panorama
+
+import (
+	"testing"
+
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/poli/security"
+	"github.com/inwinstack/pango/testdata"
+	"github.com/inwinstack/pango/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulebase(t *testing.T) {
+	assert.Equal(t, util.PreRulebase, Rulebase(""))
+	assert.Equal(t, util.PreRulebase, Rulebase("pre"))
+	assert.Equal(t, util.PostRulebase, Rulebase("post"))
+	assert.Equal(t, util.PostRulebase, Rulebase(util.PostRulebase))
+}
+
+func TestObjects(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp("")
+
+	panoSec := &security.PanoSecurity{}
+	panoSec.Initialize(mc)
+	sec := NewSecurity(panoSec, "k8s", "post")
+	assert.Nil(t, sec.Edit("vsys1", security.Entry{Name: "test-sec"}))
+	assert.Contains(t, mc.Path, "/device-group/entry[@name='k8s']/post-rulebase/security/rules/entry[@name='test-sec']")
+
+	panoSrvc := &srvc.PanoSrvc{}
+	panoSrvc.Initialize(mc)
+	svc := NewServices(panoSrvc, Shared)
+	assert.Nil(t, svc.Edit("vsys1", srvc.Entry{Name: "test-svc", Protocol: "tcp", DestinationPort: "80"}))
+	assert.Equal(t, "/config/shared/service/entry[@name='test-svc']", mc.Path)
+}
+
+func TestDevices(t *testing.T) {
+	mc := &testdata.MockClient{}
+	mc.AddResp(`<job><devices>
+		<entry><serial-no>0001</serial-no><devicename>fw-a</devicename><result>OK</result></entry>
+		<entry><serial-no>0002</serial-no><devicename>fw-b</devicename><result>FAIL</result>
+			<details><msg><errors><line>commit failed</line></errors></msg></details></entry>
+	</devices></job>`)
+
+	devices, err := Devices(mc, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []inwinv1.FirewallDeviceStatus{
+		{Serial: "0001", Name: "fw-a", Result: "OK"},
+		{Serial: "0002", Name: "fw-b", Result: "FAIL", Details: []string{"commit failed"}},
+	}, devices)
+}
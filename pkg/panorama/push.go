@@ -0,0 +1,78 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package panorama
+
+import (
+	"encoding/xml"
+
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pango"
+	"github.com/inwinstack/pango/util"
+)
+
+type jobRequest struct {
+	XMLName xml.Name `xml:"show"`
+	ID      uint     `xml:"jobs>id"`
+}
+
+type jobDevice struct {
+	Serial string   `xml:"serial-no"`
+	Name   string   `xml:"devicename"`
+	Result string   `xml:"result"`
+	Errors []string `xml:"details>msg>errors>line"`
+}
+
+type jobResponse struct {
+	XMLName xml.Name    `xml:"response"`
+	Devices []jobDevice `xml:"result>job>devices>entry"`
+}
+
+// Push pushes the committed config of Panorama to the devices of the device group, and
+// returns the result of each device. The error is returned when any device fails.
+func Push(pano *pango.Panorama, deviceGroup string) ([]inwinv1.FirewallDeviceStatus, error) {
+	job, err := pano.CommitAll(deviceGroup, "", nil, false, false)
+	if err != nil || job == 0 {
+		return nil, err
+	}
+
+	// The results are got from the job, since they aren't returned when any device fails.
+	pushErr := pano.WaitForJob(job, nil)
+	devices, err := Devices(pano, job)
+	if err != nil {
+		return nil, err
+	}
+	return devices, pushErr
+}
+
+// Devices returns the result of each device in a commit-all job.
+func Devices(pano util.XapiClient, job uint) ([]inwinv1.FirewallDeviceStatus, error) {
+	ans := jobResponse{}
+	if _, err := pano.Op(jobRequest{ID: job}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+
+	devices := make([]inwinv1.FirewallDeviceStatus, 0, len(ans.Devices))
+	for _, d := range ans.Devices {
+		devices = append(devices, inwinv1.FirewallDeviceStatus{
+			Serial:  d.Serial,
+			Name:    d.Name,
+			Result:  d.Result,
+			Details: d.Errors,
+		})
+	}
+	return devices, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placement positions the security policies in the rulebase by their resources, and
+// keeps the order of the policies stable by moving them only when it differs.
+package placement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// wheres are the positions of the annotation by the Move constants of pango.
+var wheres = map[string]int{
+	"top":             util.MoveTop,
+	"bottom":          util.MoveBottom,
+	"before":          util.MoveBefore,
+	"directly-before": util.MoveDirectlyBefore,
+	"after":           util.MoveAfter,
+	"directly-after":  util.MoveDirectlyAfter,
+}
+
+// Placement is the position of a security policy, which is either at the top or bottom
+// of the rulebase, or relative to another rule.
+type Placement struct {
+	Where int
+	Rule  string
+}
+
+// IsRelative returns true when the position is relative to another rule.
+func (p Placement) IsRelative() bool {
+	switch p.Where {
+	case util.MoveBefore, util.MoveDirectlyBefore, util.MoveAfter, util.MoveDirectlyAfter:
+		return true
+	}
+	return false
+}
+
+func (p Placement) String() string {
+	for name, where := range wheres {
+		if where == p.Where {
+			if p.IsRelative() {
+				return name + " " + p.Rule
+			}
+			return name
+		}
+	}
+	return "skip"
+}
+
+// Parse parses a position, such as "top", "bottom", "before deny-all" or
+// "directly-after allow-dns".
+func Parse(value string) (Placement, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return Placement{}, fmt.Errorf("empty position")
+	}
+
+	where, ok := wheres[fields[0]]
+	if !ok {
+		return Placement{}, fmt.Errorf("invalid position '%s'", value)
+	}
+
+	p := Placement{Where: where}
+	if !p.IsRelative() {
+		if len(fields) != 1 {
+			return Placement{}, fmt.Errorf("invalid position '%s', %s takes no rule", value, fields[0])
+		}
+		return p, nil
+	}
+
+	if len(fields) != 2 {
+		return Placement{}, fmt.Errorf("invalid position '%s', %s takes a rule", value, fields[0])
+	}
+	p.Rule = fields[1]
+	return p, nil
+}
+
+// Of returns the placement of a resource from its position annotation, and the default
+// placement is used when the annotation isn't set. The name is the name of the rule of the
+// resource on PAN, which the position can't be relative to.
+func Of(meta metav1.ObjectMeta, name string, def Placement) (Placement, error) {
+	value, ok := meta.Annotations[constants.PositionKey]
+	if !ok {
+		return def, nil
+	}
+
+	p, err := Parse(value)
+	if err != nil {
+		return Placement{}, err
+	}
+
+	if p.IsRelative() && p.Rule == name {
+		return Placement{}, fmt.Errorf("the rule can't be positioned relative to itself")
+	}
+	return p, nil
+}
+
+// Check returns an error when the rule of a relative placement doesn't exist in the rules,
+// or when the placements of the managed rules have a cycle through the given rule, such as
+// a before b and b before a.
+func Check(name string, rules []string, placements map[string]Placement) error {
+	p := placements[name]
+	if !p.IsRelative() {
+		return nil
+	}
+
+	if index(rules, p.Rule) < 0 {
+		return fmt.Errorf("the rule '%s' of the position doesn't exist", p.Rule)
+	}
+
+	// A rule before another has an edge to it, and a rule after another has an edge from it.
+	edges := map[string][]string{}
+	for n, q := range placements {
+		switch q.Where {
+		case util.MoveBefore, util.MoveDirectlyBefore:
+			edges[n] = append(edges[n], q.Rule)
+		case util.MoveAfter, util.MoveDirectlyAfter:
+			edges[q.Rule] = append(edges[q.Rule], n)
+		}
+	}
+
+	if path := findCycle(name, name, edges, map[string]bool{}); path != nil {
+		return fmt.Errorf("the positions have a cycle: %s", strings.Join(append([]string{name}, path...), " -> "))
+	}
+	return nil
+}
+
+func findCycle(start, from string, edges map[string][]string, visited map[string]bool) []string {
+	for _, to := range edges[from] {
+		if to == start {
+			return []string{to}
+		}
+
+		if visited[to] {
+			continue
+		}
+		visited[to] = true
+		if path := findCycle(start, to, edges, visited); path != nil {
+			return append([]string{to}, path...)
+		}
+	}
+	return nil
+}
+
+// IsSatisfied returns true when a rule is at its position in the rules, which are in the
+// order of the rulebase. The rules at the top, or at the bottom, are only moved when there's
+// any rule not at the top, or at the bottom, between the rule and the end of the rulebase,
+// so they aren't moved over each other.
+func IsSatisfied(name string, rules []string, placements map[string]Placement) bool {
+	i := index(rules, name)
+	if i < 0 {
+		return false
+	}
+
+	p := placements[name]
+	switch p.Where {
+	case util.MoveTop:
+		return allAt(rules[:i], util.MoveTop, placements)
+	case util.MoveBottom:
+		return allAt(rules[i+1:], util.MoveBottom, placements)
+	case util.MoveBefore:
+		return i < index(rules, p.Rule)
+	case util.MoveDirectlyBefore:
+		return i+1 == index(rules, p.Rule)
+	case util.MoveAfter:
+		return i > index(rules, p.Rule)
+	case util.MoveDirectlyAfter:
+		return i == index(rules, p.Rule)+1
+	}
+	return true
+}
+
+// Order returns the rules moved to their placements as the controller moves them on PAN, where
+// the rules are created at the bottom in the given order. The rules positioned relative to a
+// rule not in the rules are kept where they are.
+func Order(rules []string, placements map[string]Placement) []string {
+	result := append([]string{}, rules...)
+	for pass := 0; pass <= len(rules); pass++ {
+		moved := false
+		for _, name := range rules {
+			p := placements[name]
+			if p.IsRelative() && index(result, p.Rule) < 0 {
+				continue
+			}
+
+			if !IsSatisfied(name, result, placements) {
+				result = move(result, name, p)
+				moved = true
+			}
+		}
+
+		// The placements with a cycle never settle, so the moves stop after a pass per rule.
+		if !moved {
+			break
+		}
+	}
+	return result
+}
+
+// move returns the rules with a rule moved to its placement.
+func move(rules []string, name string, p Placement) []string {
+	result := []string{}
+	for _, r := range rules {
+		if r != name {
+			result = append(result, r)
+		}
+	}
+
+	i := len(result)
+	switch p.Where {
+	case util.MoveTop:
+		i = 0
+	case util.MoveBefore, util.MoveDirectlyBefore:
+		i = index(result, p.Rule)
+	case util.MoveAfter, util.MoveDirectlyAfter:
+		i = index(result, p.Rule) + 1
+	}
+	return append(result[:i], append([]string{name}, result[i:]...)...)
+}
+
+func allAt(rules []string, where int, placements map[string]Placement) bool {
+	for _, r := range rules {
+		if p, ok := placements[r]; !ok || p.Where != where {
+			return false
+		}
+	}
+	return true
+}
+
+func index(rules []string, name string) int {
+	for i, r := range rules {
+		if r == name {
+			return i
+		}
+	}
+	return -1
+}
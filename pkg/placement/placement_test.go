@@ -0,0 +1,139 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"testing"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango/util"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse("top")
+	assert.Nil(t, err)
+	assert.Equal(t, Placement{Where: util.MoveTop}, p)
+	assert.Equal(t, "top", p.String())
+
+	p, err = Parse(" directly-before  deny-all ")
+	assert.Nil(t, err)
+	assert.Equal(t, Placement{Where: util.MoveDirectlyBefore, Rule: "deny-all"}, p)
+	assert.Equal(t, "directly-before deny-all", p.String())
+
+	for _, value := range []string{"", "middle", "top deny-all", "before", "after a b"} {
+		_, err := Parse(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestOf(t *testing.T) {
+	def := Placement{Where: util.MoveBottom}
+	meta := metav1.ObjectMeta{Name: "allow-web"}
+	p, err := Of(meta, "allow-web", def)
+	assert.Nil(t, err)
+	assert.Equal(t, def, p)
+
+	meta.Annotations = map[string]string{constants.PositionKey: "after allow-dns"}
+	p, err = Of(meta, "allow-web", def)
+	assert.Nil(t, err)
+	assert.Equal(t, Placement{Where: util.MoveAfter, Rule: "allow-dns"}, p)
+
+	meta.Annotations[constants.PositionKey] = "before allow-web"
+	_, err = Of(meta, "allow-web", def)
+	assert.NotNil(t, err)
+
+	// The rule is named by the naming scheme on PAN, such as default-allow-web.
+	p, err = Of(meta, "default-allow-web", def)
+	assert.Nil(t, err)
+	assert.Equal(t, Placement{Where: util.MoveBefore, Rule: "allow-web"}, p)
+
+	meta.Annotations[constants.PositionKey] = "after default-allow-web"
+	_, err = Of(meta, "default-allow-web", def)
+	assert.NotNil(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	rules := []string{"a", "b", "c", "deny-all"}
+	placements := map[string]Placement{
+		"a": {Where: util.MoveBefore, Rule: "deny-all"},
+		"b": {Where: util.MoveTop},
+	}
+	assert.Nil(t, Check("a", rules, placements))
+	assert.Nil(t, Check("b", rules, placements))
+
+	placements["a"] = Placement{Where: util.MoveBefore, Rule: "missing"}
+	assert.EqualError(t, Check("a", rules, placements), "the rule 'missing' of the position doesn't exist")
+
+	// a before b and b after a are the same order.
+	placements["a"] = Placement{Where: util.MoveBefore, Rule: "b"}
+	placements["b"] = Placement{Where: util.MoveAfter, Rule: "a"}
+	assert.Nil(t, Check("a", rules, placements))
+
+	placements["b"] = Placement{Where: util.MoveBefore, Rule: "c"}
+	placements["c"] = Placement{Where: util.MoveDirectlyBefore, Rule: "a"}
+	assert.EqualError(t, Check("a", rules, placements), "the positions have a cycle: a -> b -> c -> a")
+	assert.EqualError(t, Check("c", rules, placements), "the positions have a cycle: c -> a -> b -> c")
+}
+
+func TestIsSatisfied(t *testing.T) {
+	rules := []string{"t1", "t2", "admin", "a", "b", "deny-all", "z"}
+	placements := map[string]Placement{
+		"t1": {Where: util.MoveTop},
+		"t2": {Where: util.MoveTop},
+		"a":  {Where: util.MoveBefore, Rule: "deny-all"},
+		"b":  {Where: util.MoveDirectlyBefore, Rule: "deny-all"},
+		"z":  {Where: util.MoveBottom},
+	}
+
+	for _, name := range []string{"t1", "t2", "a", "b", "z", "admin"} {
+		assert.True(t, IsSatisfied(name, rules, placements), name)
+	}
+
+	placements["a"] = Placement{Where: util.MoveDirectlyAfter, Rule: "deny-all"}
+	placements["admin"] = Placement{Where: util.MoveTop}
+	placements["z"] = Placement{Where: util.MoveAfter, Rule: "deny-all"}
+	assert.False(t, IsSatisfied("a", rules, placements))
+	assert.True(t, IsSatisfied("admin", rules, placements))
+	assert.True(t, IsSatisfied("z", rules, placements))
+
+	delete(placements, "t2")
+	assert.False(t, IsSatisfied("admin", rules, placements))
+	assert.False(t, IsSatisfied("missing", rules, placements))
+}
+
+func TestOrder(t *testing.T) {
+	rules := []string{"allow-web", "deny-all", "dns", "first", "ldap", "second"}
+	placements := map[string]Placement{
+		"allow-web": {Where: util.MoveTop},
+		"deny-all":  {Where: util.MoveBottom},
+		"dns":       {Where: util.MoveAfter, Rule: "first"},
+		"first":     {Where: util.MoveTop},
+		"ldap":      {Where: util.MoveDirectlyAfter, Rule: "corp"},
+		"second":    {Where: util.MoveDirectlyBefore, Rule: "deny-all"},
+	}
+
+	order := Order(rules, placements)
+	assert.Equal(t, []string{"allow-web", "first", "dns", "ldap", "second", "deny-all"}, order)
+	for _, name := range []string{"allow-web", "deny-all", "dns", "first", "second"} {
+		assert.True(t, IsSatisfied(name, order, placements), name)
+	}
+
+	placements["allow-web"] = Placement{Where: util.MoveAfter, Rule: "dns"}
+	assert.Equal(t, len(rules), len(Order(rules, placements)))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan computes the changes that the controller would make to PAN in the dry-run
+// mode, where the firewall isn't edited.
+package plan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Action is the change of an entry on PAN.
+type Action string
+
+// These are the valid actions of a plan.
+const (
+	ActionNone   Action = "none"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is the change of a field of an entry.
+type Change struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Plan is the change of an entry on PAN.
+type Plan struct {
+	Action  Action
+	Name    string
+	Changes []Change
+}
+
+// Create plans to create the entry, where the changes are the fields set in it.
+func Create(name string, desired interface{}) *Plan {
+	zero := reflect.New(reflect.TypeOf(desired)).Elem().Interface()
+	return &Plan{Action: ActionCreate, Name: name, Changes: Diff(zero, desired)}
+}
+
+// Update plans to update the current entry to the desired one, and nothing is changed when
+// they have the same fields.
+func Update(name string, current, desired interface{}) *Plan {
+	changes := Diff(current, desired)
+	if len(changes) == 0 {
+		return &Plan{Action: ActionNone, Name: name}
+	}
+	return &Plan{Action: ActionUpdate, Name: name, Changes: changes}
+}
+
+// Delete plans to delete the entry, and nothing is changed when it doesn't exist.
+func Delete(name string, exists bool) *Plan {
+	if !exists {
+		return &Plan{Action: ActionNone, Name: name}
+	}
+	return &Plan{Action: ActionDelete, Name: name}
+}
+
+// Diff returns the changes of the exported fields between two entries of the same type.
+// The nil and empty slices or maps are equal, and the string lists are compared as sets.
+func Diff(current, desired interface{}) []Change {
+	cv := reflect.ValueOf(current)
+	dv := reflect.ValueOf(desired)
+	changes := []Change{}
+	for i := 0; i < dv.NumField(); i++ {
+		field := dv.Type().Field(i)
+		if field.PkgPath != "" || field.Name == "Name" {
+			continue
+		}
+
+		if equal(cv.Field(i), dv.Field(i)) {
+			continue
+		}
+		changes = append(changes, Change{Field: field.Name, Old: cv.Field(i).Interface(), New: dv.Field(i).Interface()})
+	}
+	return changes
+}
+
+func equal(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+
+	// The members are unordered on PAN, which may return them in another order.
+	if x, ok := a.Interface().([]string); ok {
+		return reflect.DeepEqual(sorted(x), sorted(b.Interface().([]string)))
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// sorted returns a sorted copy of the strings.
+func sorted(s []string) []string {
+	result := append([]string{}, s...)
+	sort.Strings(result)
+	return result
+}
+
+// String returns the plan in the form of "action name: field: old -> new; ...".
+func (p *Plan) String() string {
+	if len(p.Changes) == 0 {
+		return fmt.Sprintf("%s %s", p.Action, p.Name)
+	}
+
+	changes := make([]string, 0, len(p.Changes))
+	for _, c := range p.Changes {
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", c.Field, c.Old, c.New))
+	}
+	return fmt.Sprintf("%s %s: %s", p.Action, p.Name, strings.Join(changes, "; "))
+}
+
+// Record records the plan on the annotations of a resource, and returns false when the
+// resource has the same plan.
+func Record(meta *metav1.ObjectMeta, p *Plan) bool {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+
+	s := p.String()
+	if meta.Annotations[constants.PlanKey] == s {
+		return false
+	}
+	meta.Annotations[constants.PlanKey] = s
+	return true
+}
+
+// Clear removes the plan from the annotations of a resource after the change is applied.
+func Clear(meta *metav1.ObjectMeta) {
+	delete(meta.Annotations, constants.PlanKey)
+}
+
+// IsNotFound returns true when the entry doesn't exist on PAN.
+func IsNotFound(err error) bool {
+	e, ok := err.(pango.PanosError)
+	return ok && e.ObjectNotFound()
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	"github.com/inwinstack/pango"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlan(t *testing.T) {
+	desired := srvc.Entry{Name: "k8s-web", Protocol: "tcp", DestinationPort: "80,443"}
+
+	p := Create(desired.Name, desired)
+	assert.Equal(t, ActionCreate, p.Action)
+	assert.Equal(t, "create k8s-web: Protocol:  -> tcp; DestinationPort:  -> 80,443", p.String())
+
+	current := srvc.Entry{Name: "k8s-web", Protocol: "tcp", DestinationPort: "80", Tags: []string{}}
+	p = Update(desired.Name, current, desired)
+	assert.Equal(t, ActionUpdate, p.Action)
+	assert.Equal(t, []Change{{Field: "DestinationPort", Old: "80", New: "80,443"}}, p.Changes)
+	assert.Equal(t, "update k8s-web: DestinationPort: 80 -> 80,443", p.String())
+
+	p = Update(desired.Name, desired, desired)
+	assert.Equal(t, "none k8s-web", p.String())
+
+	// The members are compared regardless of their order.
+	current = srvc.Entry{Name: "k8s-web", Protocol: "tcp", DestinationPort: "80,443", Tags: []string{"pa-controller", "k8s"}}
+	desired.Tags = []string{"k8s", "pa-controller"}
+	assert.Equal(t, ActionNone, Update(desired.Name, current, desired).Action)
+	assert.Equal(t, []string{"k8s", "pa-controller"}, desired.Tags)
+
+	current.Tags = []string{"pa-controller"}
+	assert.Equal(t, []Change{{Field: "Tags", Old: current.Tags, New: desired.Tags}}, Update(desired.Name, current, desired).Changes)
+
+	assert.Equal(t, "delete k8s-web", Delete("k8s-web", true).String())
+	assert.Equal(t, ActionNone, Delete("k8s-web", false).Action)
+}
+
+func TestRecord(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "k8s-web"}
+	p := Delete(meta.Name, true)
+	assert.True(t, Record(&meta, p))
+	assert.Equal(t, "delete k8s-web", meta.Annotations[constants.PlanKey])
+	assert.False(t, Record(&meta, p))
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, IsNotFound(pango.PanosError{Code: 7}))
+	assert.False(t, IsNotFound(pango.PanosError{Code: 13}))
+	assert.False(t, IsNotFound(nil))
+}
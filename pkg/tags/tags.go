@@ -0,0 +1,75 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefix is the prefix of the PAN tags used for Kubernetes objects.
+const Prefix = "k8s"
+
+// Tagger builds the tags of the Kubernetes objects of a cluster. The tags are prefixed with
+// "k8s.<cluster ID>", so the clusters sharing a firewall only manage their own tags. Every kind
+// of tags has its own prefix, so a label can't forge the tag of a namespace.
+type Tagger struct {
+	prefix string
+}
+
+// New returns the tagger of the given cluster. The tags are only prefixed with "k8s" when
+// the cluster ID is empty.
+func New(clusterID string) Tagger {
+	if clusterID == "" {
+		return Tagger{prefix: Prefix}
+	}
+	return Tagger{prefix: fmt.Sprintf("%s.%s", Prefix, sanitize(clusterID))}
+}
+
+func sanitize(s string) string {
+	return strings.Replace(s, "/", ".", -1)
+}
+
+// Namespace returns the tag of the pods in the given namespace, e.g. "k8s.ns.default".
+func (t Tagger) Namespace(namespace string) string {
+	return fmt.Sprintf("%s.ns.%s", t.prefix, namespace)
+}
+
+// NamespaceLabel returns the tag of the pods in the namespaces that have the given label.
+func (t Tagger) NamespaceLabel(key, value string) string {
+	return fmt.Sprintf("%s.nslabel.%s.%s", t.prefix, sanitize(key), value)
+}
+
+// Label returns the tag of the pods that have the given label, e.g. "k8s.label.app.web".
+func (t Tagger) Label(key, value string) string {
+	return fmt.Sprintf("%s.label.%s.%s", t.prefix, sanitize(key), value)
+}
+
+// IsManaged returns true when the tag is a namespace, namespace label or label tag of the cluster.
+func (t Tagger) IsManaged(tag string) bool {
+	for _, kind := range []string{"ns", "nslabel", "label"} {
+		if strings.HasPrefix(tag, fmt.Sprintf("%s.%s.", t.prefix, kind)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote returns the tag quoted for a PAN dynamic address group match expression.
+func Quote(tag string) string {
+	return fmt.Sprintf("'%s'", tag)
+}
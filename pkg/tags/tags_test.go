@@ -0,0 +1,59 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	tagger := New("")
+	assert.Equal(t, "k8s.ns.default", tagger.Namespace("default"))
+	assert.Equal(t, "k8s.label.app.web", tagger.Label("app", "web"))
+	assert.Equal(t, "k8s.label.app.kubernetes.io.name.web", tagger.Label("app.kubernetes.io/name", "web"))
+	assert.Equal(t, "k8s.nslabel.team.infra", tagger.NamespaceLabel("team", "infra"))
+	assert.Equal(t, "'k8s.label.app.web'", Quote(tagger.Label("app", "web")))
+
+	tagger = New("k8s-a")
+	assert.Equal(t, "k8s.k8s-a.ns.default", tagger.Namespace("default"))
+	assert.Equal(t, "k8s.k8s-a.label.app.web", tagger.Label("app", "web"))
+	assert.Equal(t, "k8s.k8s-a.nslabel.team.infra", tagger.NamespaceLabel("team", "infra"))
+}
+
+func TestTagsCollision(t *testing.T) {
+	// The pod labels can't forge the tags of a namespace or its labels.
+	tagger := New("")
+	assert.NotEqual(t, tagger.Namespace("prod"), tagger.Label("ns", "prod"))
+	assert.NotEqual(t, tagger.NamespaceLabel("team", "infra"), tagger.Label("nslabel", "team.infra"))
+	assert.NotEqual(t, tagger.NamespaceLabel("team", "infra"), tagger.Label("nslabel.team", "infra"))
+}
+
+func TestTagsIsManaged(t *testing.T) {
+	a := New("k8s-a")
+	b := New("k8s-b")
+	assert.True(t, a.IsManaged(a.Namespace("default")))
+	assert.True(t, a.IsManaged(a.Label("app", "web")))
+	assert.True(t, a.IsManaged(a.NamespaceLabel("team", "infra")))
+
+	// The tags of other clusters and the tags registered by others aren't managed.
+	assert.False(t, a.IsManaged(b.Namespace("default")))
+	assert.False(t, a.IsManaged(New("").Label("app", "web")))
+	assert.False(t, New("").IsManaged(a.Label("app", "web")))
+	assert.False(t, a.IsManaged("k8s.k8s-a.quarantine"))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const certValidity = 365 * 24 * time.Hour
+
+// SelfSigned generates a self-signed certificate for the hosts, which are DNS names or IPs, and
+// returns it with its PEM to be the CA bundle of the webhook configurations.
+func SelfSigned(hosts []string) (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "pa-controller-webhook"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	return cert, certPEM, nil
+}
+
+// InjectCABundle sets the CA bundle of all webhooks in the validating and mutating webhook
+// configurations of a name, and one of them may not exist.
+func InjectCABundle(kubeset kubernetes.Interface, name string, ca []byte) error {
+	found := false
+	validating := kubeset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	if conf, err := validating.Get(name, metav1.GetOptions{}); err == nil {
+		confCopy := conf.DeepCopy()
+		for i := range confCopy.Webhooks {
+			confCopy.Webhooks[i].ClientConfig.CABundle = ca
+		}
+
+		if _, err := validating.Update(confCopy); err != nil {
+			return err
+		}
+		found = true
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	mutating := kubeset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	if conf, err := mutating.Get(name, metav1.GetOptions{}); err == nil {
+		confCopy := conf.DeepCopy()
+		for i := range confCopy.Webhooks {
+			confCopy.Webhooks[i].ClientConfig.CABundle = ca
+		}
+
+		if _, err := mutating.Update(confCopy); err != nil {
+			return err
+		}
+		found = true
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("the webhook configuration '%s' doesn't exist", name)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/thoas/go-funk"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SelectDefaults returns the defaults of a namespace, which is the one with the highest priority
+// and then the first name among the defaults selecting the namespace. It's nil when none of
+// them selects the namespace.
+func SelectDefaults(defaults []*inwinv1.SecurityDefaults, ns *v1.Namespace) (*inwinv1.SecurityDefaults, error) {
+	var selected *inwinv1.SecurityDefaults
+	for _, d := range defaults {
+		selector := labels.Everything()
+		if d.Spec.NamespaceSelector != nil {
+			s, err := metav1.LabelSelectorAsSelector(d.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, err
+			}
+			selector = s
+		}
+
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+
+		if selected == nil || d.Spec.Priority > selected.Spec.Priority ||
+			(d.Spec.Priority == selected.Spec.Priority && d.Name < selected.Name) {
+			selected = d
+		}
+	}
+	return selected, nil
+}
+
+// ApplySecurity fills the defaults in the empty fields of a Security, and in the enforced fields.
+// A false bool is empty, so only the enforced bools can be turned off.
+func ApplySecurity(d *inwinv1.SecurityDefaults, spec *blendedv1.SecuritySpec) {
+	v := d.Spec.Security
+	enforced := func(field string) bool {
+		return funk.ContainsString(d.Spec.Enforced, field)
+	}
+
+	setStrings(&spec.SourceZones, v.SourceZones, enforced("sourceZones"))
+	setStrings(&spec.DestinationZones, v.DestinationZones, enforced("destinationZones"))
+	setStrings(&spec.SourceUsers, v.SourceUsers, enforced("sourceUsers"))
+	setStrings(&spec.HipProfiles, v.HipProfiles, enforced("hipProfiles"))
+	setStrings(&spec.Categories, v.Categories, enforced("categories"))
+	setString(&spec.LogSetting, v.LogSetting, enforced("logSetting"))
+	setString(&spec.Group, v.Group, enforced("group"))
+	setBool(&spec.LogStart, v.LogStart, enforced("logStart"))
+	setBool(&spec.LogEnd, v.LogEnd, enforced("logEnd"))
+	spec.Tags = addTags(spec.Tags, v.Tags)
+}
+
+// ApplyNAT fills the defaults in the empty fields of a NAT.
+func ApplyNAT(d *inwinv1.SecurityDefaults, spec *blendedv1.NATSpec) {
+	v := d.Spec.NAT
+	setStrings(&spec.SourceZones, v.SourceZones, false)
+	setString(&spec.DestinationZone, v.DestinationZone, false)
+	setString(&spec.ToInterface, v.ToInterface, false)
+	spec.Tags = addTags(spec.Tags, v.Tags)
+}
+
+func setStrings(field *[]string, def []string, enforced bool) {
+	if len(def) != 0 && (len(*field) == 0 || enforced) {
+		*field = append([]string{}, def...)
+	}
+}
+
+func setString(field *string, def string, enforced bool) {
+	if def != "" && (*field == "" || enforced) {
+		*field = def
+	}
+}
+
+func setBool(field *bool, def *bool, enforced bool) {
+	if def != nil && (!*field || enforced) {
+		*field = *def
+	}
+}
+
+// addTags adds the default tags after the tags of a resource, since the tags are ordered.
+func addTags(tags, defaults []string) []string {
+	for _, t := range defaults {
+		if !funk.ContainsString(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelectDefaults(t *testing.T) {
+	all := &inwinv1.SecurityDefaults{ObjectMeta: metav1.ObjectMeta{Name: "org"}}
+	prod := &inwinv1.SecurityDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod"},
+		Spec: inwinv1.SecurityDefaultsSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Priority:          10,
+		},
+	}
+	defaults := []*inwinv1.SecurityDefaults{prod, all}
+
+	d, err := SelectDefaults(defaults, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"env": "prod"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, prod, d)
+
+	d, err = SelectDefaults(defaults, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	assert.Nil(t, err)
+	assert.Equal(t, all, d)
+
+	d, err = SelectDefaults([]*inwinv1.SecurityDefaults{prod}, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+}
+
+func TestApplySecurity(t *testing.T) {
+	on, off := true, false
+	d := &inwinv1.SecurityDefaults{Spec: inwinv1.SecurityDefaultsSpec{
+		Security: inwinv1.SecurityDefaultValues{
+			SourceZones: []string{"untrust"},
+			HipProfiles: []string{"any"},
+			LogSetting:  "siem_forward",
+			LogStart:    &off,
+			LogEnd:      &on,
+			Group:       "inwin-monitor",
+			Tags:        []string{"k8s", "audit"},
+		},
+		Enforced: []string{"logSetting", "logStart"},
+	}}
+
+	spec := &blendedv1.SecuritySpec{
+		SourceZones: []string{"dmz"},
+		LogSetting:  "local",
+		LogStart:    true,
+		Tags:        []string{"web", "k8s"},
+	}
+	ApplySecurity(d, spec)
+	assert.Equal(t, []string{"dmz"}, spec.SourceZones)
+	assert.Equal(t, []string{"any"}, spec.HipProfiles)
+	assert.Equal(t, "siem_forward", spec.LogSetting)
+	assert.False(t, spec.LogStart)
+	assert.True(t, spec.LogEnd)
+	assert.Equal(t, "inwin-monitor", spec.Group)
+	assert.Equal(t, []string{"web", "k8s", "audit"}, spec.Tags)
+}
@@ -0,0 +1,374 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook serves the admission webhooks of the NAT, Security and Service resources, so
+// the invalid specs are rejected when they're applied instead of failing on PAN, and the
+// defaults of the namespaces are filled in.
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	"github.com/golang/glog"
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	inwinlisterv1 "github.com/inwinstack/pa-controller/pkg/generated/listers/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/guardrail"
+	"github.com/thoas/go-funk"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Server serves the admission webhooks over TLS.
+type Server struct {
+	cfg            *config.Config
+	kubeset        kubernetes.Interface
+	defaultsLister inwinlisterv1.SecurityDefaultsLister
+	nsLister       listerv1.NamespaceLister
+	guard          *guardrail.Guard
+	synced         []cache.InformerSynced
+	server         *http.Server
+}
+
+// NewServer creates an instance of the webhook server
+func NewServer(cfg *config.Config, kubeset kubernetes.Interface, kubeInformer informers.SharedInformerFactory, inwinInformer inwininformers.SharedInformerFactory) *Server {
+	defaultsInformer := inwinInformer.Inwinstack().V1().SecurityDefaultses()
+	nsInformer := kubeInformer.Core().V1().Namespaces()
+	guard := guardrail.NewGuard(kubeInformer, inwinInformer)
+	s := &Server{
+		cfg:            cfg,
+		kubeset:        kubeset,
+		defaultsLister: defaultsInformer.Lister(),
+		nsLister:       nsInformer.Lister(),
+		guard:          guard,
+		synced: append([]cache.InformerSynced{
+			defaultsInformer.Informer().HasSynced,
+			nsInformer.Informer().HasSynced,
+		}, guard.Synced()...),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", s.handle(s.validate))
+	mux.HandleFunc("/mutate", s.handle(s.mutate))
+	s.server = &http.Server{Addr: cfg.WebhookListenAddress, Handler: mux}
+	return s
+}
+
+// Run serves the webhook server. The certificate is generated when it's self-signed, and then
+// set as the CA bundle of the webhook configuration.
+func (s *Server) Run(ctx context.Context) error {
+	glog.Info("Starting the webhook server")
+	glog.Info("Waiting for the webhook informer caches to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), s.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	cert, err := s.certificate()
+	if err != nil {
+		return err
+	}
+	s.server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	go func() {
+		glog.Infof("Serving webhooks on %s", s.cfg.WebhookListenAddress)
+		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			glog.Errorf("Failed to serve webhooks: %+v.", err)
+		}
+	}()
+	return nil
+}
+
+// Stop stops the webhook server
+func (s *Server) Stop() {
+	glog.Info("Stopping the webhook server")
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		glog.Errorf("Failed to stop the webhook server: %+v.", err)
+	}
+}
+
+func (s *Server) certificate() (tls.Certificate, error) {
+	if !s.cfg.WebhookSelfSigned {
+		return tls.LoadX509KeyPair(s.cfg.WebhookCertFile, s.cfg.WebhookKeyFile)
+	}
+
+	cert, ca, err := SelfSigned(s.cfg.WebhookHosts)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate the certificate: %s", err.Error())
+	}
+
+	if s.cfg.WebhookConfiguration != "" {
+		if err := InjectCABundle(s.kubeset, s.cfg.WebhookConfiguration, ca); err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to inject the CA bundle: %s", err.Error())
+		}
+	}
+	return cert, nil
+}
+
+// handle returns the handler of a webhook, which responds the admission reviews by a review
+// function.
+func (s *Server) handle(review func(*admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ar := &admissionv1beta1.AdmissionReview{}
+		if err := json.Unmarshal(body, ar); err != nil || ar.Request == nil {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+
+		ar.Response = review(ar.Request)
+		ar.Response.UID = ar.Request.UID
+		ar.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ar); err != nil {
+			glog.Errorf("Failed to write the admission review: %+v.", err)
+		}
+	}
+}
+
+// validate reviews the created and updated resources, and the Securities and NATs against the
+// firewall policies of their namespaces. An update which doesn't change the spec is always
+// allowed, so the resources created before the webhook can still be updated and deleted, unless
+// it changes the entry name.
+func (s *Server) validate(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	var (
+		meta metav1.ObjectMeta
+		errs field.ErrorList
+	)
+
+	switch req.Kind.Kind {
+	case "NAT":
+		obj, old := &blendedv1.NAT{}, &blendedv1.NAT{}
+		if err := decode(req, obj, old); err != nil {
+			return denied(err)
+		}
+
+		if err := s.checkEntryName(req, obj.ObjectMeta, old.ObjectMeta); err != nil {
+			return denied(err)
+		}
+
+		if !isChanged(req, obj.ObjectMeta, obj.Spec, old.Spec) {
+			return allowed()
+		}
+		violations, err := s.guard.NAT(obj.Namespace, obj.Spec)
+		if err != nil {
+			return denied(err)
+		}
+		meta, errs = obj.ObjectMeta, append(ValidateNAT(s.cfg.NamingScheme, obj), violations...)
+	case "Security":
+		obj, old := &blendedv1.Security{}, &blendedv1.Security{}
+		if err := decode(req, obj, old); err != nil {
+			return denied(err)
+		}
+
+		if err := s.checkEntryName(req, obj.ObjectMeta, old.ObjectMeta); err != nil {
+			return denied(err)
+		}
+
+		if !isChanged(req, obj.ObjectMeta, obj.Spec, old.Spec) {
+			return allowed()
+		}
+		violations, err := s.guard.Security(obj.Namespace, obj.Spec)
+		if err != nil {
+			return denied(err)
+		}
+		meta, errs = obj.ObjectMeta, append(ValidateSecurity(s.cfg.NamingScheme, obj), violations...)
+	case "Service":
+		obj, old := &blendedv1.Service{}, &blendedv1.Service{}
+		if err := decode(req, obj, old); err != nil {
+			return denied(err)
+		}
+
+		if err := s.checkEntryName(req, obj.ObjectMeta, old.ObjectMeta); err != nil {
+			return denied(err)
+		}
+
+		if !isChanged(req, obj.ObjectMeta, obj.Spec, old.Spec) {
+			return allowed()
+		}
+		meta, errs = obj.ObjectMeta, ValidateService(obj)
+	default:
+		return allowed()
+	}
+
+	if len(errs) != 0 {
+		gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+		return denied(errors.NewInvalid(gk, meta.Name, errs))
+	}
+	return allowed()
+}
+
+// checkEntryName rejects the changes of the entry name annotation by the users other than the
+// trusted ones, such as the controller and the importer, since a resource would take over the
+// entry of any name on PAN.
+func (s *Server) checkEntryName(req *admissionv1beta1.AdmissionRequest, meta, old metav1.ObjectMeta) error {
+	name := meta.Annotations[constants.EntryNameKey]
+	if name == old.Annotations[constants.EntryNameKey] || funk.ContainsString(s.cfg.WebhookTrustedUsers, req.UserInfo.Username) {
+		return nil
+	}
+
+	gr := schema.GroupResource{Group: req.Resource.Group, Resource: req.Resource.Resource}
+	return errors.NewForbidden(gr, meta.Name, fmt.Errorf("the annotation %s can only be changed by the controller", constants.EntryNameKey))
+}
+
+// mutate fills the defaults of the namespace in the created and updated Securities and NATs.
+// An update which doesn't change the spec, such as the status updates of the controller, is
+// never mutated, so the defaults changed later only apply when the spec changes.
+func (s *Server) mutate(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	var (
+		sec *blendedv1.Security
+		nat *blendedv1.NAT
+	)
+
+	switch req.Kind.Kind {
+	case "Security":
+		obj, old := &blendedv1.Security{}, &blendedv1.Security{}
+		if err := decode(req, obj, old); err != nil {
+			return denied(err)
+		}
+
+		if !isChanged(req, obj.ObjectMeta, obj.Spec, old.Spec) {
+			return allowed()
+		}
+		sec = obj
+	case "NAT":
+		obj, old := &blendedv1.NAT{}, &blendedv1.NAT{}
+		if err := decode(req, obj, old); err != nil {
+			return denied(err)
+		}
+
+		if !isChanged(req, obj.ObjectMeta, obj.Spec, old.Spec) {
+			return allowed()
+		}
+		nat = obj
+	default:
+		return allowed()
+	}
+
+	d, err := s.defaults(req.Namespace)
+	if err != nil {
+		return denied(err)
+	}
+
+	if d == nil {
+		return allowed()
+	}
+
+	var spec, mutated interface{}
+	if sec != nil {
+		specCopy := sec.Spec.DeepCopy()
+		ApplySecurity(d, specCopy)
+		spec, mutated = sec.Spec, *specCopy
+	} else {
+		specCopy := nat.Spec.DeepCopy()
+		ApplyNAT(d, specCopy)
+		spec, mutated = nat.Spec, *specCopy
+	}
+
+	if reflect.DeepEqual(spec, mutated) {
+		return allowed()
+	}
+	return patched(mutated)
+}
+
+// defaults returns the security defaults of a namespace.
+func (s *Server) defaults(namespace string) (*inwinv1.SecurityDefaults, error) {
+	ns, err := s.nsLister.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := s.defaultsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return SelectDefaults(defaults, ns)
+}
+
+// decode decodes the object of a request, and the old object of an update if it's given.
+func decode(req *admissionv1beta1.AdmissionRequest, obj, old interface{}) error {
+	if req.Operation == admissionv1beta1.Delete {
+		return nil
+	}
+
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return err
+	}
+
+	if req.Operation == admissionv1beta1.Update && old != nil {
+		return json.Unmarshal(req.OldObject.Raw, old)
+	}
+	return nil
+}
+
+func isChanged(req *admissionv1beta1.AdmissionRequest, meta metav1.ObjectMeta, spec, oldSpec interface{}) bool {
+	switch {
+	case req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update:
+		return false
+	case meta.DeletionTimestamp != nil:
+		return false
+	case req.Operation == admissionv1beta1.Update && reflect.DeepEqual(spec, oldSpec):
+		return false
+	}
+	return true
+}
+
+func allowed() *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{Allowed: true}
+}
+
+// patched allows a request with a patch replacing the spec of the object.
+func patched(spec interface{}) *admissionv1beta1.AdmissionResponse {
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "add", "path": "/spec", "value": spec},
+	})
+	if err != nil {
+		return denied(err)
+	}
+
+	pt := admissionv1beta1.PatchTypeJSONPatch
+	return &admissionv1beta1.AdmissionResponse{Allowed: true, Patch: patch, PatchType: &pt}
+}
+
+func denied(err error) *admissionv1beta1.AdmissionResponse {
+	if status, ok := err.(errors.APIStatus); ok {
+		s := status.Status()
+		return &admissionv1beta1.AdmissionResponse{Result: &s}
+	}
+	return &admissionv1beta1.AdmissionResponse{Result: &metav1.Status{Message: err.Error()}}
+}
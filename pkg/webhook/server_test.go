@@ -0,0 +1,330 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	inwinv1 "github.com/inwinstack/pa-controller/pkg/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/config"
+	"github.com/inwinstack/pa-controller/pkg/constants"
+	inwinfake "github.com/inwinstack/pa-controller/pkg/generated/clientset/versioned/fake"
+	inwininformers "github.com/inwinstack/pa-controller/pkg/generated/informers/externalversions"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func review(t *testing.T, h http.HandlerFunc, op admissionv1beta1.Operation, kind string, obj, old runtime.Object) *admissionv1beta1.AdmissionResponse {
+	return reviewAs(t, h, "admin", op, kind, obj, old)
+}
+
+// reviewAs reviews a request of the user.
+func reviewAs(t *testing.T, h http.HandlerFunc, user string, op admissionv1beta1.Operation, kind string, obj, old runtime.Object) *admissionv1beta1.AdmissionResponse {
+	meta, err := apimeta.Accessor(obj)
+	assert.Nil(t, err)
+
+	req := &admissionv1beta1.AdmissionRequest{
+		UID:       "1",
+		Kind:      metav1.GroupVersionKind{Group: "inwinstack.com", Version: "v1", Kind: kind},
+		Namespace: meta.GetNamespace(),
+		Operation: op,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+		Object:    runtime.RawExtension{Object: obj},
+	}
+	if old != nil {
+		req.OldObject = runtime.RawExtension{Object: old}
+	}
+
+	body, err := json.Marshal(&admissionv1beta1.AdmissionReview{Request: req})
+	assert.Nil(t, err)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resp := &admissionv1beta1.AdmissionReview{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), resp))
+	assert.Equal(t, "1", string(resp.Response.UID))
+	return resp.Response
+}
+
+func newServer(t *testing.T, ctx context.Context, objs ...runtime.Object) *Server {
+	kubeset := kubefake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "web"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+	)
+	inwinset := inwinfake.NewSimpleClientset()
+	for _, obj := range objs {
+		var err error
+		switch o := obj.(type) {
+		case *inwinv1.SecurityDefaults:
+			_, err = inwinset.InwinstackV1().SecurityDefaultses().Create(o)
+		case *inwinv1.FirewallPolicy:
+			_, err = inwinset.InwinstackV1().FirewallPolicies().Create(o)
+		}
+		assert.Nil(t, err)
+	}
+	kubeInformer := informers.NewSharedInformerFactory(kubeset, 0)
+	inwinInformer := inwininformers.NewSharedInformerFactory(inwinset, 0)
+
+	s := NewServer(&config.Config{NamingScheme: "name"}, kubeset, kubeInformer, inwinInformer)
+	go kubeInformer.Start(ctx.Done())
+	go inwinInformer.Start(ctx.Done())
+	assert.True(t, cache.WaitForCacheSync(ctx.Done(), s.synced...))
+	return s
+}
+
+func TestValidateWebhook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServer(t, ctx)
+	validate := s.handle(s.validate)
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-tcp80"},
+		Spec:       blendedv1.ServiceSpec{Protocol: "tcp", DestinationPort: "80"},
+	}
+	assert.True(t, review(t, validate, admissionv1beta1.Create, "Service", svc, nil).Allowed)
+
+	bad := svc.DeepCopy()
+	bad.Spec.DestinationPort = "80-"
+	resp := review(t, validate, admissionv1beta1.Create, "Service", bad, nil)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, metav1.StatusReasonInvalid, resp.Result.Reason)
+	assert.Contains(t, resp.Result.Message, `Service.inwinstack.com "k8s-tcp80" is invalid: spec.destinationPort`)
+
+	// An update which doesn't change the spec of an invalid resource is allowed.
+	old := bad.DeepCopy()
+	bad.Finalizers = []string{"kubernetes"}
+	assert.True(t, review(t, validate, admissionv1beta1.Update, "Service", bad, old).Allowed)
+	assert.False(t, review(t, validate, admissionv1beta1.Update, "Service", bad, svc).Allowed)
+
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+		Spec:       blendedv1.SecuritySpec{Action: "accept"},
+	}
+	assert.False(t, review(t, validate, admissionv1beta1.Create, "Security", sec, nil).Allowed)
+
+	n := &blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       blendedv1.NATSpec{Type: "ipv5"},
+	}
+	assert.False(t, review(t, validate, admissionv1beta1.Create, "NAT", n, nil).Allowed)
+}
+
+func TestValidateWebhookEntryName(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServer(t, ctx)
+	s.cfg.WebhookTrustedUsers = []string{"system:serviceaccount:kube-system:pa-controller"}
+	validate := s.handle(s.validate)
+
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+		Spec:       blendedv1.SecuritySpec{Action: blendedv1.SecurityAllow},
+	}
+	named := sec.DeepCopy()
+	named.Annotations = map[string]string{constants.EntryNameKey: "deny-all"}
+
+	// Only the trusted users can set or change the entry name, even without changing the spec.
+	resp := review(t, validate, admissionv1beta1.Create, "Security", named, nil)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, metav1.StatusReasonForbidden, resp.Result.Reason)
+	assert.False(t, review(t, validate, admissionv1beta1.Update, "Security", named, sec).Allowed)
+	assert.False(t, review(t, validate, admissionv1beta1.Update, "Security", sec, named).Allowed)
+	assert.True(t, reviewAs(t, validate, "system:serviceaccount:kube-system:pa-controller", admissionv1beta1.Update, "Security", named, sec).Allowed)
+
+	// The resources with an entry name can still be updated.
+	updated := named.DeepCopy()
+	updated.Spec.Description = "Allow web"
+	assert.True(t, review(t, validate, admissionv1beta1.Update, "Security", updated, named).Allowed)
+
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "k8s-tcp80",
+			Annotations: map[string]string{constants.EntryNameKey: "service-http"},
+		},
+		Spec: blendedv1.ServiceSpec{Protocol: "tcp", DestinationPort: "80"},
+	}
+	assert.False(t, review(t, validate, admissionv1beta1.Create, "Service", svc, nil).Allowed)
+
+	n := &blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			Annotations: map[string]string{constants.EntryNameKey: "snat"},
+		},
+	}
+	assert.False(t, review(t, validate, admissionv1beta1.Create, "NAT", n, nil).Allowed)
+}
+
+func TestValidateWebhookPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newServer(t, ctx, &inwinv1.FirewallPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: inwinv1.FirewallPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			AllowedZones:      []string{"trust", "dmz"},
+			ForbidAnySource:   true,
+		},
+	})
+	validate := s.handle(s.validate)
+
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+		Spec: blendedv1.SecuritySpec{
+			SourceZones:     []string{"untrust"},
+			SourceAddresses: []string{"any"},
+			Action:          blendedv1.SecurityAllow,
+		},
+	}
+	resp := review(t, validate, admissionv1beta1.Create, "Security", sec, nil)
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "spec.sourceZones[0]: Forbidden: zone 'untrust' isn't allowed by the FirewallPolicy 'web'")
+	assert.Contains(t, resp.Result.Message, "spec.sourceAddresses[0]: Forbidden: source 'any' isn't allowed by the FirewallPolicy 'web'")
+
+	// The namespaces which aren't selected aren't constrained.
+	sec.Namespace = "test"
+	assert.True(t, review(t, validate, admissionv1beta1.Create, "Security", sec, nil).Allowed)
+
+	n := &blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       blendedv1.NATSpec{SourceZones: []string{"trust"}, SourceAddresses: []string{"10.0.0.0/24"}, DestinationZone: "dmz"},
+	}
+	assert.True(t, review(t, validate, admissionv1beta1.Create, "NAT", n, nil).Allowed)
+}
+
+func TestMutateWebhook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logEnd := true
+	s := newServer(t, ctx, &inwinv1.SecurityDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging"},
+		Spec: inwinv1.SecurityDefaultsSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			Security:          inwinv1.SecurityDefaultValues{SourceUsers: []string{"any"}, LogSetting: "siem_forward", LogEnd: &logEnd},
+			NAT:               inwinv1.NATDefaultValues{ToInterface: "any"},
+			Enforced:          []string{"logSetting"},
+		},
+	})
+	mutate := s.handle(s.mutate)
+
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+		Spec:       blendedv1.SecuritySpec{Action: blendedv1.SecurityAllow, LogSetting: "local"},
+	}
+	resp := review(t, mutate, admissionv1beta1.Create, "Security", sec, nil)
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, admissionv1beta1.PatchTypeJSONPatch, *resp.PatchType)
+
+	patch := []struct {
+		Op    string                 `json:"op"`
+		Path  string                 `json:"path"`
+		Value blendedv1.SecuritySpec `json:"value"`
+	}{}
+	assert.Nil(t, json.Unmarshal(resp.Patch, &patch))
+	assert.Equal(t, "/spec", patch[0].Path)
+	assert.Equal(t, []string{"any"}, patch[0].Value.SourceUsers)
+	assert.Equal(t, "siem_forward", patch[0].Value.LogSetting)
+	assert.True(t, patch[0].Value.LogEnd)
+	assert.Equal(t, blendedv1.SecurityAllow, patch[0].Value.Action)
+
+	// A spec with the defaults isn't patched.
+	resp = review(t, mutate, admissionv1beta1.Update, "Security", &blendedv1.Security{ObjectMeta: sec.ObjectMeta, Spec: patch[0].Value}, sec)
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Patch)
+
+	// An update which doesn't change the spec, such as a status update, isn't patched.
+	updated := sec.DeepCopy()
+	updated.Status.Phase = blendedv1.SecurityActive
+	resp = review(t, mutate, admissionv1beta1.Update, "Security", updated, sec)
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Patch)
+
+	updated.Spec.Action = blendedv1.SecurityDeny
+	resp = review(t, mutate, admissionv1beta1.Update, "Security", updated, sec)
+	assert.True(t, resp.Allowed)
+	assert.Contains(t, string(resp.Patch), `"logSetting":"siem_forward"`)
+
+	n := &blendedv1.NAT{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	resp = review(t, mutate, admissionv1beta1.Create, "NAT", n, nil)
+	assert.True(t, resp.Allowed)
+	assert.Contains(t, string(resp.Patch), `"toInterface":"any"`)
+
+	// The namespaces which aren't selected have no defaults.
+	n.Namespace = "test"
+	resp = review(t, mutate, admissionv1beta1.Create, "NAT", n, nil)
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Patch)
+
+	n.Namespace = "missing"
+	assert.False(t, review(t, mutate, admissionv1beta1.Create, "NAT", n, nil).Allowed)
+	assert.True(t, review(t, mutate, admissionv1beta1.Update, "NAT", n, n).Allowed)
+}
+
+func TestSelfSigned(t *testing.T) {
+	cert, ca, err := SelfSigned([]string{"pa-controller.kube-system.svc", "127.0.0.1"})
+	assert.Nil(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pa-controller.kube-system.svc"}, leaf.DNSNames)
+	assert.Equal(t, "127.0.0.1", leaf.IPAddresses[0].String())
+
+	pool := x509.NewCertPool()
+	assert.True(t, pool.AppendCertsFromPEM(ca))
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "pa-controller.kube-system.svc", Roots: pool})
+	assert.Nil(t, err)
+
+	kubeset := kubefake.NewSimpleClientset(&admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-controller"},
+		Webhooks:   []admissionregistrationv1beta1.ValidatingWebhook{{Name: "validate.inwinstack.com"}},
+	})
+	assert.Nil(t, InjectCABundle(kubeset, "pa-controller", ca))
+	_, err = kubeset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "pa-controller"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "mutate.inwinstack.com"}},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, InjectCABundle(kubeset, "pa-controller", ca))
+
+	conf, err := kubeset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get("pa-controller", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, ca, conf.Webhooks[0].ClientConfig.CABundle)
+
+	mconf, err := kubeset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get("pa-controller", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, ca, mconf.Webhooks[0].ClientConfig.CABundle)
+	assert.NotNil(t, InjectCABundle(kubeset, "missing", ca))
+}
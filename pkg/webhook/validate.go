@@ -0,0 +1,195 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/inwinstack/pango/objs/srvc"
+	"github.com/inwinstack/pango/poli/nat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// These are the values accepted by PAN which have no constants in pango.
+var (
+	securityTypes = []string{"universal", "intrazone", "interzone"}
+	actions       = []string{"allow", "deny", "drop", "reset-client", "reset-server", "reset-both"}
+	distributions = []string{"round-robin", "source-ip-hash", "ip-modulo", "ip-hash", "least-sessions"}
+)
+
+// ValidateNAT validates the spec of a NAT against the NAT policies of PAN.
+func ValidateNAT(scheme string, n *blendedv1.NAT) field.ErrorList {
+	errs := validateName(scheme, n.ObjectMeta)
+	spec := field.NewPath("spec")
+	errs = append(errs, validateEnum(spec.Child("type"), n.Spec.Type, nat.TypeIpv4, nat.TypeNat64, nat.TypeNptv6)...)
+	errs = append(errs, validateEnum(spec.Child("satType"), n.Spec.SatType, nat.None, nat.DynamicIpAndPort, nat.DynamicIp, nat.StaticIp)...)
+	errs = append(errs, validateEnum(spec.Child("satAddressType"), n.Spec.SatAddressType, nat.InterfaceAddress, nat.TranslatedAddress)...)
+	errs = append(errs, validateEnum(spec.Child("satFallbackType"), n.Spec.SatFallbackType, nat.None, nat.InterfaceAddress, nat.TranslatedAddress)...)
+	errs = append(errs, validateEnum(spec.Child("satFallbackIPType"), n.Spec.SatFallbackIPType, nat.Ip, nat.FloatingIp)...)
+	errs = append(errs, validateEnum(spec.Child("datType"), n.Spec.DatType, nat.DatTypeStatic, nat.DatTypeDynamic)...)
+	errs = append(errs, validateEnum(spec.Child("datDynamicDistribution"), n.Spec.DatDynamicDistribution, distributions...)...)
+
+	errs = append(errs, validateAddresses(spec.Child("sourceAddresses"), n.Spec.SourceAddresses)...)
+	errs = append(errs, validateAddresses(spec.Child("destinationAddresses"), n.Spec.DestinationAddresses)...)
+	errs = append(errs, validateAddresses(spec.Child("satTranslatedAddresses"), n.Spec.SatTranslatedAddresses)...)
+	errs = append(errs, validateAddresses(spec.Child("satFallbackTranslatedAddresses"), n.Spec.SatFallbackTranslatedAddresses)...)
+	errs = append(errs, validateAddress(spec.Child("satIPAddress"), n.Spec.SatIPAddress)...)
+	errs = append(errs, validateAddress(spec.Child("satFallbackIPAddress"), n.Spec.SatFallbackIPAddress)...)
+	errs = append(errs, validateAddress(spec.Child("satStaticTranslatedAddress"), n.Spec.SatStaticTranslatedAddress)...)
+	errs = append(errs, validateAddress(spec.Child("datAddress"), n.Spec.DatAddress)...)
+
+	if n.Spec.DatPort < 0 || n.Spec.DatPort > 65535 {
+		errs = append(errs, field.Invalid(spec.Child("datPort"), n.Spec.DatPort, "must be a port between 1 and 65535, or 0 to keep the port"))
+	}
+	return errs
+}
+
+// ValidateSecurity validates the spec of a Security against the security policies of PAN.
+func ValidateSecurity(scheme string, sec *blendedv1.Security) field.ErrorList {
+	errs := validateName(scheme, sec.ObjectMeta)
+	spec := field.NewPath("spec")
+	errs = append(errs, validateEnum(spec.Child("type"), sec.Spec.Type, securityTypes...)...)
+	errs = append(errs, validateEnum(spec.Child("action"), sec.Spec.Action, actions...)...)
+	errs = append(errs, validateAddresses(spec.Child("sourceAddresses"), sec.Spec.SourceAddresses)...)
+	errs = append(errs, validateAddresses(spec.Child("destinationAddresses"), sec.Spec.DestinationAddresses)...)
+	return errs
+}
+
+// ValidateService validates the spec of a Service against the service objects of PAN.
+func ValidateService(svc *blendedv1.Service) field.ErrorList {
+	errs := validateName(naming.SchemeName, svc.ObjectMeta)
+	spec := field.NewPath("spec")
+	if svc.Spec.Protocol == "" {
+		errs = append(errs, field.Required(spec.Child("protocol"), ""))
+	}
+	errs = append(errs, validateEnum(spec.Child("protocol"), svc.Spec.Protocol, srvc.ProtocolTcp, srvc.ProtocolUdp, srvc.ProtocolSctp)...)
+
+	if svc.Spec.DestinationPort == "" {
+		errs = append(errs, field.Required(spec.Child("destinationPort"), ""))
+	}
+	errs = append(errs, validatePorts(spec.Child("destinationPort"), svc.Spec.DestinationPort)...)
+	errs = append(errs, validatePorts(spec.Child("sourcePort"), svc.Spec.SourcePort)...)
+	return errs
+}
+
+// validateName checks the length of the entry name on PAN, since the namespace scheme truncates
+// the long names but the name scheme doesn't.
+func validateName(scheme string, meta metav1.ObjectMeta) field.ErrorList {
+	name := naming.EntryName(scheme, meta)
+	if len(name) > naming.MaxLength {
+		msg := fmt.Sprintf("the entry name '%s' on PAN must be no more than %d characters", name, naming.MaxLength)
+		return field.ErrorList{field.Invalid(field.NewPath("metadata", "name"), meta.Name, msg)}
+	}
+	return nil
+}
+
+// validateEnum checks a value is one of the supported values, and an empty value is the default.
+func validateEnum(path *field.Path, value string, values ...string) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+
+	for _, v := range values {
+		if v == value {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(path, value, values)}
+}
+
+func validateAddresses(path *field.Path, addrs []string) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, addr := range addrs {
+		errs = append(errs, validateAddress(path.Index(i), addr)...)
+	}
+	return errs
+}
+
+// validateAddress checks the syntax of an IP address, a CIDR or an IP range. The other values are
+// the names of address objects, or any, which are resolved by PAN.
+func validateAddress(path *field.Path, addr string) field.ErrorList {
+	if !isIPLike(addr) {
+		return nil
+	}
+
+	var err error
+	switch {
+	case strings.Contains(addr, "-"):
+		err = checkRange(addr)
+	case strings.Contains(addr, "/"):
+		_, _, err = net.ParseCIDR(addr)
+	case net.ParseIP(addr) == nil:
+		err = fmt.Errorf("invalid IP address")
+	}
+
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, addr, "must be a valid IP address, CIDR, IP range or address name")}
+	}
+	return nil
+}
+
+// isIPLike tells whether a value is meant to be an IP, since the names on PAN can't have colons
+// and rarely consist of digits and periods only.
+func isIPLike(addr string) bool {
+	if strings.Contains(addr, ":") {
+		return true
+	}
+	return strings.Contains(addr, ".") && strings.Trim(addr, "0123456789./-") == ""
+}
+
+func checkRange(addr string) error {
+	parts := strings.Split(addr, "-")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid IP range")
+	}
+
+	from, to := net.ParseIP(parts[0]), net.ParseIP(parts[1])
+	if from == nil || to == nil || (from.To4() == nil) != (to.To4() == nil) {
+		return fmt.Errorf("invalid IP range")
+	}
+	return nil
+}
+
+// validatePorts checks a comma-separated list of ports or port ranges, such as 80,8080-8088.
+func validatePorts(path *field.Path, ports string) field.ErrorList {
+	if ports == "" {
+		return nil
+	}
+
+	for _, port := range strings.Split(ports, ",") {
+		bounds := strings.Split(port, "-")
+		if len(bounds) > 2 {
+			return field.ErrorList{field.Invalid(path, ports, fmt.Sprintf("invalid port range '%s'", port))}
+		}
+
+		prev := 0
+		for _, b := range bounds {
+			n, err := strconv.Atoi(b)
+			if err != nil || n < 1 || n > 65535 || n < prev {
+				return field.ErrorList{field.Invalid(path, ports, fmt.Sprintf("invalid port or port range '%s'", port))}
+			}
+			prev = n
+		}
+	}
+	return nil
+}
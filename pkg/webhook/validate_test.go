@@ -0,0 +1,90 @@
+/*
+Copyright © 2018 inwinSTACK Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	blendedv1 "github.com/inwinstack/blended/apis/inwinstack/v1"
+	"github.com/inwinstack/pa-controller/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateNAT(t *testing.T) {
+	n := &blendedv1.NAT{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: blendedv1.NATSpec{
+			Type:                 blendedv1.NATIPv4,
+			SourceAddresses:      []string{"any", "10.0.0.0/8", "k8s-web"},
+			DestinationAddresses: []string{"140.23.110.10", "10.0.0.1-10.0.0.9"},
+			DatType:              blendedv1.NATDatStatic,
+			DatAddress:           "172.22.132.10",
+			DatPort:              8080,
+		},
+	}
+	assert.Empty(t, ValidateNAT(naming.SchemeName, n))
+
+	n.Spec.DatType = "destination"
+	n.Spec.SourceAddresses = []string{"10.0.0.0/33"}
+	n.Spec.DestinationAddresses = []string{"10.0.0.1-fe80::1"}
+	n.Spec.DatPort = 70000
+	errs := ValidateNAT(naming.SchemeName, n)
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, "spec.datType", errs[0].Field)
+	assert.Equal(t, "spec.sourceAddresses[0]", errs[1].Field)
+	assert.Equal(t, "spec.destinationAddresses[0]", errs[2].Field)
+	assert.Equal(t, "spec.datPort", errs[3].Field)
+}
+
+func TestValidateSecurity(t *testing.T) {
+	sec := &blendedv1.Security{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 64), Namespace: "default"},
+		Spec:       blendedv1.SecuritySpec{Action: "permit", DestinationAddresses: []string{"256.0.0.1"}},
+	}
+	errs := ValidateSecurity(naming.SchemeName, sec)
+	assert.Equal(t, 3, len(errs))
+	assert.Equal(t, "metadata.name", errs[0].Field)
+	assert.Equal(t, "spec.action", errs[1].Field)
+	assert.Equal(t, "spec.destinationAddresses[0]", errs[2].Field)
+
+	// The namespace scheme truncates the long names.
+	sec.Spec = blendedv1.SecuritySpec{Action: blendedv1.SecurityDeny}
+	assert.Empty(t, ValidateSecurity(naming.SchemeNamespace, sec))
+}
+
+func TestValidateService(t *testing.T) {
+	svc := &blendedv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-tcp80"},
+		Spec:       blendedv1.ServiceSpec{Protocol: "tcp", DestinationPort: "80,443,8080-8088"},
+	}
+	assert.Empty(t, ValidateService(svc))
+
+	for _, port := range []string{"80-", "0", "8088-8080", "80,,443", "1-2-3", "http"} {
+		svc.Spec.DestinationPort = port
+		errs := ValidateService(svc)
+		assert.Equal(t, 1, len(errs), port)
+		assert.Equal(t, "spec.destinationPort", errs[0].Field)
+	}
+
+	svc.Spec = blendedv1.ServiceSpec{Protocol: "icmp", SourcePort: "1024-65535"}
+	errs := ValidateService(svc)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "spec.protocol", errs[0].Field)
+	assert.Equal(t, "spec.destinationPort", errs[1].Field)
+}